@@ -3,8 +3,11 @@ package main
 import (
 	"log"
 
+	"github.com/NavaneethaPrasad/RecipeManager/backend/configs"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/database"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/realtime"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/routes"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/storage"
 )
 
 func main() {
@@ -21,8 +24,32 @@ func main() {
 		log.Fatal("Failed to initialize database:", err)
 	}
 
+	// Seed reference data
+	if err := database.SeedIngredients(db); err != nil {
+		log.Fatal("Failed to seed ingredients:", err)
+	}
+	if err := database.SeedNutrition(db); err != nil {
+		log.Fatal("Failed to seed nutrition data:", err)
+	}
+	if err := database.SeedAisles(db); err != nil {
+		log.Fatal("Failed to seed aisles:", err)
+	}
+
+	// Photo storage
+	store, err := storage.New(configs.LoadStorageConfig())
+	if err != nil {
+		log.Fatal("Failed to configure storage:", err)
+	}
+
+	// Shopping list events
+	hub, err := realtime.New(configs.LoadRealtimeConfig())
+	if err != nil {
+		log.Fatal("Failed to start realtime hub:", err)
+	}
+	defer hub.Close()
+
 	//Setup routes and pass DB
-	r := routes.SetupRoutes(db)
+	r := routes.SetupRoutes(db, store, hub)
 
 	log.Println("Server running on http://localhost:8080")
 	if err := r.Run(":8080"); err != nil {
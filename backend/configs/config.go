@@ -5,6 +5,8 @@ import (
 	"log"
 	"os"
 
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/realtime"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/storage"
 	"github.com/joho/godotenv"
 )
 
@@ -13,6 +15,10 @@ func LoadConfig() string {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Note: .env file not found, relying on System Env Vars")
 	}
+	return databaseDSN()
+}
+
+func databaseDSN() string {
 	DBHost := getEnv("DB_HOST", "localhost")
 	DBUser := getEnv("DB_USER", "postgres")
 	DBPassword := getEnv("DB_PASSWORD", "password")
@@ -22,6 +28,31 @@ func LoadConfig() string {
 	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", DBHost, DBUser, DBPassword, DBName, DBPort)
 }
 
+// LoadStorageConfig picks where uploaded photos go. Call it after LoadConfig,
+// which loads the .env file.
+func LoadStorageConfig() storage.Config {
+	return storage.Config{
+		Driver:      getEnv("STORAGE_DRIVER", "local"),
+		LocalDir:    getEnv("UPLOAD_DIR", "uploads"),
+		PublicURL:   getEnv("UPLOAD_URL", ""),
+		S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+		S3Region:    getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:    getEnv("S3_BUCKET", ""),
+		S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+	}
+}
+
+// LoadRealtimeConfig picks how shopping list events reach subscribers. Use
+// the postgres driver when running more than one replica. Call it after
+// LoadConfig, which loads the .env file.
+func LoadRealtimeConfig() realtime.Config {
+	return realtime.Config{
+		Driver: getEnv("REALTIME_DRIVER", "memory"),
+		DSN:    databaseDSN(),
+	}
+}
+
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -0,0 +1,203 @@
+// Package aisle names the grocery store sections ingredients are shelved in
+// and guesses the section for an ingredient from its name.
+package aisle
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var ErrUnknownAisle = errors.New("unknown aisle")
+
+const (
+	Produce    = "produce"
+	Bakery     = "bakery"
+	Meat       = "meat"
+	Seafood    = "seafood"
+	Dairy      = "dairy"
+	Frozen     = "frozen"
+	Grains     = "grains"
+	Baking     = "baking"
+	Spices     = "spices"
+	Canned     = "canned"
+	Condiments = "condiments"
+	Snacks     = "snacks"
+	Beverages  = "beverages"
+	Other      = "other"
+)
+
+// Default is the order aisles are walked in when no store layout is given,
+// roughly the way a supermarket is laid out from the entrance.
+var Default = []string{
+	Produce, Bakery, Meat, Seafood, Dairy, Grains, Baking, Spices,
+	Canned, Condiments, Snacks, Beverages, Frozen, Other,
+}
+
+// Other names people use for the aisles.
+var aisleAliases = map[string]string{
+	"fruit":       Produce,
+	"fruits":      Produce,
+	"vegetables":  Produce,
+	"veg":         Produce,
+	"bread":       Bakery,
+	"deli":        Meat,
+	"butcher":     Meat,
+	"fish":        Seafood,
+	"eggs":        Dairy,
+	"dairy-eggs":  Dairy,
+	"chilled":     Dairy,
+	"freezer":     Frozen,
+	"pasta":       Grains,
+	"rice":        Grains,
+	"cereal":      Grains,
+	"dry-goods":   Grains,
+	"herbs":       Spices,
+	"seasonings":  Spices,
+	"tins":        Canned,
+	"canned-food": Canned,
+	"sauces":      Condiments,
+	"oils":        Condiments,
+	"drinks":      Beverages,
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Normalize maps an aisle name or one of its aliases to the aisle.
+func Normalize(name string) (string, error) {
+	s := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	for _, a := range Default {
+		if s == a {
+			return a, nil
+		}
+	}
+	if a, ok := aisleAliases[s]; ok {
+		return a, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownAisle, name)
+}
+
+// Words in ingredient names that give away their aisle. Longer phrases win
+// over the words in them, so "peanut butter" is not shelved with the dairy.
+var keywords = map[string]string{
+	"apple": Produce, "avocado": Produce, "banana": Produce, "basil": Produce,
+	"bell pepper": Produce, "berry": Produce, "berries": Produce, "blueberries": Produce,
+	"broccoli": Produce, "cabbage": Produce, "carrot": Produce, "celery": Produce,
+	"cilantro": Produce, "corn": Produce, "cucumber": Produce, "garlic": Produce,
+	"ginger": Produce, "green onion": Produce, "kale": Produce, "lemon": Produce,
+	"lettuce": Produce, "lime": Produce, "mushroom": Produce, "onion": Produce,
+	"orange": Produce, "parsley": Produce, "pea": Produce, "potato": Produce,
+	"spinach": Produce, "strawberries": Produce, "tomato": Produce, "zucchini": Produce,
+
+	"bagel": Bakery, "baguette": Bakery, "bread": Bakery, "bun": Bakery,
+	"croissant": Bakery, "pita": Bakery, "tortilla": Bakery,
+
+	"bacon": Meat, "beef": Meat, "chicken": Meat, "chicken breast": Meat,
+	"ham": Meat, "lamb": Meat, "pork": Meat, "sausage": Meat, "turkey": Meat,
+
+	"cod": Seafood, "fish": Seafood, "prawn": Seafood, "salmon": Seafood,
+	"shrimp": Seafood, "tuna": Seafood,
+
+	"butter": Dairy, "cheddar": Dairy, "cheese": Dairy, "cream": Dairy,
+	"egg": Dairy, "milk": Dairy, "mozzarella": Dairy, "parmesan": Dairy,
+	"sour cream": Dairy, "yogurt": Dairy,
+
+	"couscous": Grains, "lentil": Grains, "noodle": Grains, "oat": Grains,
+	"pasta": Grains, "quinoa": Grains, "rice": Grains, "spaghetti": Grains,
+	"rolled oats": Grains, "bean": Grains, "black beans": Grains, "chickpea": Grains,
+
+	"baking powder": Baking, "baking soda": Baking, "brown sugar": Baking,
+	"chocolate": Baking, "cocoa powder": Baking, "cornstarch": Baking,
+	"flour": Baking, "powdered sugar": Baking, "sugar": Baking,
+	"vanilla extract": Baking, "yeast": Baking,
+
+	"black pepper": Spices, "cinnamon": Spices, "cumin": Spices,
+	"oregano": Spices, "paprika": Spices, "pepper": Spices, "salt": Spices,
+	"turmeric": Spices,
+
+	"coconut milk": Canned, "tomato paste": Canned, "canned": Canned,
+	"stock": Canned, "broth": Canned,
+
+	"honey": Condiments, "ketchup": Condiments, "maple syrup": Condiments,
+	"mayonnaise": Condiments, "mustard": Condiments, "oil": Condiments,
+	"olive oil": Condiments, "peanut butter": Condiments, "soy sauce": Condiments,
+	"sauce": Condiments, "vinegar": Condiments, "vegetable oil": Condiments,
+
+	"almond": Snacks, "cracker": Snacks, "chip": Snacks, "walnut": Snacks,
+	"nut": Snacks,
+
+	"coffee": Beverages, "juice": Beverages, "tea": Beverages, "water": Beverages,
+	"wine": Beverages,
+
+	"ice cream": Frozen,
+}
+
+// Guess returns the aisle an ingredient is likely shelved in, or "" when its
+// name gives no clue. Anything called frozen is in the freezer; otherwise
+// the longest keyword wins, and the later one on a tie, since the last word
+// usually names the thing: "lemon juice" is a drink.
+func Guess(name string) string {
+	words := strings.Fields(nonSlug.ReplaceAllString(strings.ToLower(name), " "))
+	for _, w := range words {
+		if w == "frozen" {
+			return Frozen
+		}
+	}
+
+	best, bestLen := "", 0
+	for start := range words {
+		for end := start + 1; end <= len(words); end++ {
+			phrase := strings.Join(words[start:end], " ")
+			a, ok := keywords[phrase]
+			if !ok {
+				a, ok = keywords[singular(phrase)]
+			}
+			if ok && end-start >= bestLen {
+				best, bestLen = a, end-start
+			}
+		}
+	}
+	return best
+}
+
+// singular drops a plural "s" or "es" so "carrots" and "tomatoes" find their
+// keywords.
+func singular(phrase string) string {
+	switch {
+	case strings.HasSuffix(phrase, "oes"):
+		return strings.TrimSuffix(phrase, "es")
+	case strings.HasSuffix(phrase, "s") && !strings.HasSuffix(phrase, "ss"):
+		return strings.TrimSuffix(phrase, "s")
+	}
+	return phrase
+}
+
+// Of is the aisle an ingredient belongs in: the one recorded for it, else a
+// guess from its name, else Other.
+func Of(recorded, name string) string {
+	if recorded != "" {
+		return recorded
+	}
+	if guess := Guess(name); guess != "" {
+		return guess
+	}
+	return Other
+}
+
+// Order returns each aisle's position in a layout. Aisles the layout leaves
+// out come after it, in the default order.
+func Order(layout []string) map[string]int {
+	order := make(map[string]int, len(Default))
+	for _, a := range layout {
+		if _, ok := order[a]; !ok {
+			order[a] = len(order)
+		}
+	}
+	for _, a := range Default {
+		if _, ok := order[a]; !ok {
+			order[a] = len(order)
+		}
+	}
+	return order
+}
@@ -0,0 +1,51 @@
+package aisle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{"Produce": Produce, " Vegetables ": Produce, "Dairy & Eggs": Dairy, "drinks": Beverages}
+	for in, want := range cases {
+		got, err := Normalize(in)
+		if err != nil || got != want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := Normalize("garden centre"); !errors.Is(err, ErrUnknownAisle) {
+		t.Errorf("expected ErrUnknownAisle, got %v", err)
+	}
+}
+
+func TestGuess(t *testing.T) {
+	cases := map[string]string{
+		"Cherry Tomatoes": Produce,
+		"Peanut Butter":   Condiments,
+		"Butter":          Dairy,
+		"Cream Cheese":    Dairy,
+		"Lemon Juice":     Beverages,
+		"Frozen Peas":     Frozen,
+		"Chicken Stock":   Canned,
+		"Red Lentils":     Grains,
+		"Saffron":         "",
+	}
+	for name, want := range cases {
+		if got := Guess(name); got != want {
+			t.Errorf("Guess(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if got := Of("", "Saffron"); got != Other {
+		t.Errorf("Of unknown = %q, want other", got)
+	}
+	if got := Of(Spices, "Saffron"); got != Spices {
+		t.Errorf("Of recorded = %q, want spices", got)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	order := Order([]string{Dairy, Produce})
+	if order[Dairy] != 0 || order[Produce] != 1 || order[Bakery] != 2 || len(order) != len(Default) {
+		t.Errorf("unexpected order %v", order)
+	}
+}
@@ -0,0 +1,271 @@
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Version is the archive layout written by this build. Read accepts any
+// version up to it; bump it when a file's shape changes incompatibly.
+const Version = 1
+
+const Kind = "mealmate-account"
+
+var ErrUnsupportedVersion = errors.New("unsupported archive version")
+var ErrInvalidArchive = errors.New("invalid archive")
+
+// Archive is one user's data. IDs are those of the exporting instance and
+// only link records inside the archive; importers assign new ones.
+type Archive struct {
+	Manifest      Manifest
+	Ingredients   []Ingredient
+	Tags          []Tag
+	Recipes       []Recipe
+	MealPlans     []MealPlan
+	ShoppingLists []ShoppingList
+}
+
+type Manifest struct {
+	Kind       string         `json:"kind"`
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Counts     map[string]int `json:"counts"`
+}
+
+type Ingredient struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Density     *float64   `json:"density,omitempty"`
+	PieceWeight *float64   `json:"piece_weight,omitempty"`
+	Nutrition   *Nutrition `json:"nutrition,omitempty"` // per 100 g
+	Allergens   []string   `json:"allergens,omitempty"`
+	Diets       []string   `json:"diets,omitempty"` // absent when unknown
+}
+
+// Nutrition is sodium in mg, energy in kcal, the rest in grams.
+type Nutrition struct {
+	Kcal    float64 `json:"kcal"`
+	Protein float64 `json:"protein_g"`
+	Fat     float64 `json:"fat_g"`
+	Carbs   float64 `json:"carbs_g"`
+	Fiber   float64 `json:"fiber_g"`
+	Sugar   float64 `json:"sugar_g"`
+	Sodium  float64 `json:"sodium_mg"`
+}
+
+type Tag struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type Recipe struct {
+	ID           uint               `json:"id"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	Servings     int                `json:"servings"`
+	PrepTime     int                `json:"prep_time"`
+	CookTime     int                `json:"cook_time"`
+	Category     string             `json:"category"`
+	Nutrition    *Nutrition         `json:"nutrition,omitempty"` // per serving
+	TagIDs       []uint             `json:"tag_ids"`
+	Ingredients  []RecipeIngredient `json:"ingredients"`
+	Instructions []Instruction      `json:"instructions"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+type RecipeIngredient struct {
+	IngredientID uint    `json:"ingredient_id"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	Note         string  `json:"note,omitempty"`
+}
+
+type Instruction struct {
+	StepNumber int    `json:"step_number"`
+	Text       string `json:"text"`
+}
+
+type MealPlan struct {
+	ID             uint      `json:"id"`
+	RecipeID       uint      `json:"recipe_id"`
+	Date           time.Time `json:"date"`
+	MealType       string    `json:"meal_type"`
+	TargetServings int       `json:"target_servings"`
+}
+
+type ShoppingList struct {
+	ID        uint               `json:"id"`
+	StartDate time.Time          `json:"start_date"`
+	EndDate   time.Time          `json:"end_date"`
+	Items     []ShoppingListItem `json:"items"`
+}
+
+type ShoppingListItem struct {
+	IngredientID uint    `json:"ingredient_id"`  // 0 for a free-text item
+	Name         string  `json:"name,omitempty"` // free-text items only
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	Note         string  `json:"note,omitempty"`
+	Manual       bool    `json:"manual,omitempty"`
+	Stale        bool    `json:"stale,omitempty"`
+	Checked      bool    `json:"checked"`
+}
+
+// files maps each archive entry to the slice it holds, in write order.
+func (a *Archive) files() []struct {
+	name string
+	data any
+} {
+	return []struct {
+		name string
+		data any
+	}{
+		{"ingredients.json", &a.Ingredients},
+		{"tags.json", &a.Tags},
+		{"recipes.json", &a.Recipes},
+		{"meal_plans.json", &a.MealPlans},
+		{"shopping_lists.json", &a.ShoppingLists},
+	}
+}
+
+// Write stores the archive as a zip of JSON files with manifest.json first.
+func Write(w io.Writer, a *Archive) error {
+	a.Manifest.Kind = Kind
+	a.Manifest.Version = Version
+	if a.Manifest.ExportedAt.IsZero() {
+		a.Manifest.ExportedAt = time.Now().UTC()
+	}
+	a.Manifest.Counts = map[string]int{
+		"ingredients":    len(a.Ingredients),
+		"tags":           len(a.Tags),
+		"recipes":        len(a.Recipes),
+		"meal_plans":     len(a.MealPlans),
+		"shopping_lists": len(a.ShoppingLists),
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeJSON(zw, "manifest.json", a.Manifest); err != nil {
+		return err
+	}
+	for _, f := range a.files() {
+		if err := writeJSON(zw, f.name, f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(fw)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Read opens a zip written by Write and checks its version and that every
+// reference points at a record inside the archive.
+func Read(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	entries := map[string]*zip.File{}
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+
+	a := &Archive{}
+	if err := readJSON(entries, "manifest.json", &a.Manifest, true); err != nil {
+		return nil, err
+	}
+	if a.Manifest.Kind != Kind {
+		return nil, fmt.Errorf("%w: not a %s archive", ErrInvalidArchive, Kind)
+	}
+	if a.Manifest.Version < 1 || a.Manifest.Version > Version {
+		return nil, fmt.Errorf("%w %d (this instance reads up to %d)", ErrUnsupportedVersion, a.Manifest.Version, Version)
+	}
+
+	for _, f := range a.files() {
+		if err := readJSON(entries, f.name, f.data, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, a.validate()
+}
+
+func readJSON(entries map[string]*zip.File, name string, v any, required bool) error {
+	f, ok := entries[name]
+	if !ok {
+		if required {
+			return fmt.Errorf("%w: missing %s", ErrInvalidArchive, name)
+		}
+		return nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+	}
+	defer rc.Close()
+
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+	}
+	return nil
+}
+
+func (a *Archive) validate() error {
+	ingredients := map[uint]bool{}
+	for _, i := range a.Ingredients {
+		if i.Name == "" {
+			return fmt.Errorf("%w: ingredient %d has no name", ErrInvalidArchive, i.ID)
+		}
+		ingredients[i.ID] = true
+	}
+	tags := map[uint]bool{}
+	for _, t := range a.Tags {
+		tags[t.ID] = true
+	}
+
+	recipes := map[uint]bool{}
+	for _, r := range a.Recipes {
+		recipes[r.ID] = true
+		for _, id := range r.TagIDs {
+			if !tags[id] {
+				return fmt.Errorf("%w: recipe %q refers to unknown tag %d", ErrInvalidArchive, r.Name, id)
+			}
+		}
+		for _, ri := range r.Ingredients {
+			if !ingredients[ri.IngredientID] {
+				return fmt.Errorf("%w: recipe %q refers to unknown ingredient %d", ErrInvalidArchive, r.Name, ri.IngredientID)
+			}
+		}
+	}
+
+	for _, mp := range a.MealPlans {
+		if !recipes[mp.RecipeID] {
+			return fmt.Errorf("%w: meal plan %d refers to unknown recipe %d", ErrInvalidArchive, mp.ID, mp.RecipeID)
+		}
+	}
+	for _, sl := range a.ShoppingLists {
+		for _, item := range sl.Items {
+			if item.IngredientID == 0 && item.Name != "" {
+				continue
+			}
+			if !ingredients[item.IngredientID] {
+				return fmt.Errorf("%w: shopping list %d refers to unknown ingredient %d", ErrInvalidArchive, sl.ID, item.IngredientID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func roundTrip(t *testing.T, a *Archive) (*Archive, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Write(&buf, a); err != nil {
+		t.Fatal(err)
+	}
+	return Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
+
+func TestWriteRead(t *testing.T) {
+	in := &Archive{
+		Ingredients: []Ingredient{{ID: 7, Name: "Flour"}},
+		Tags:        []Tag{{ID: 3, Name: "Baking"}},
+		Recipes: []Recipe{{ID: 1, Name: "Bread", TagIDs: []uint{3},
+			Ingredients: []RecipeIngredient{{IngredientID: 7, Quantity: 500, Unit: "g"}}}},
+		MealPlans:     []MealPlan{{ID: 9, RecipeID: 1, MealType: "lunch"}},
+		ShoppingLists: []ShoppingList{{ID: 4, Items: []ShoppingListItem{{IngredientID: 7, Quantity: 1, Unit: "kg"}}}},
+	}
+
+	out, err := roundTrip(t, in)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if out.Manifest.Version != Version || out.Manifest.Counts["recipes"] != 1 {
+		t.Errorf("unexpected manifest %+v", out.Manifest)
+	}
+	if len(out.Recipes) != 1 || out.Recipes[0].Ingredients[0].Quantity != 500 || len(out.ShoppingLists[0].Items) != 1 {
+		t.Errorf("unexpected contents %+v", out)
+	}
+}
+
+func TestRead_DanglingReference(t *testing.T) {
+	_, err := roundTrip(t, &Archive{MealPlans: []MealPlan{{ID: 1, RecipeID: 42}}})
+	if !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("expected ErrInvalidArchive, got %v", err)
+	}
+}
+
+func TestRead_Version(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("manifest.json")
+	w.Write([]byte(`{"kind": "mealmate-account", "version": 99}`))
+	zw.Close()
+
+	if _, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len())); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("expected ErrUnsupportedVersion, got %v", err)
+	}
+	if _, err := Read(bytes.NewReader([]byte("not a zip")), 9); !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("expected ErrInvalidArchive, got %v", err)
+	}
+}
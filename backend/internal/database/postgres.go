@@ -13,7 +13,8 @@ func Connect() (*gorm.DB, error) {
 
 	dsn := configs.LoadConfig()
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError turns unique violations into gorm.ErrDuplicatedKey
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, err
 	}
@@ -27,10 +28,19 @@ func CreateDB(db *gorm.DB) error {
 		&models.User{},
 		&models.Recipe{},
 		&models.Ingredient{},
+		&models.IngredientAlias{},
 		&models.RecipeIngredient{},
 		&models.MealPlan{},
 		&models.ShoppingList{},
 		&models.ShoppingListItem{},
+		&models.ShoppingListChange{},
+		&models.ShoppingListSyncOp{},
 		&models.Instruction{},
+		&models.Tag{},
+		&models.Photo{},
+		&models.IngredientPrice{},
+		&models.PantryItem{},
+		&models.CookEvent{},
+		&models.StoreLayout{},
 	)
 }
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/aisle"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/diet"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/nutrition"
+	"gorm.io/gorm"
+)
+
+type ingredientSeed struct {
+	Name        string
+	Density     float64 // g/ml, 0 when unknown
+	PieceWeight float64 // g per piece, 0 when unknown
+	Allergens   []string
+	Diets       []string // vegan implies vegetarian, see diet.Resolve
+}
+
+// Approximate values for common kitchen ingredients, used to merge volume,
+// weight and count quantities of the same ingredient on shopping lists and
+// to weigh recipe quantities for nutrition, with typical allergens and
+// diets. Cheeses made with animal rennet are not marked vegetarian.
+var defaultIngredients = []ingredientSeed{
+	{Name: "Water", Density: 1.0, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Milk", Density: 1.03, Allergens: []string{"milk"}, Diets: []string{diet.Vegetarian, diet.GlutenFree}},
+	{Name: "Cream", Density: 0.99, Allergens: []string{"milk"}, Diets: []string{diet.Vegetarian, diet.GlutenFree, diet.Keto}},
+	{Name: "Yogurt", Density: 1.03, Allergens: []string{"milk"}, Diets: []string{diet.Vegetarian, diet.GlutenFree}},
+	{Name: "Butter", Density: 0.91, Allergens: []string{"milk"}, Diets: []string{diet.Vegetarian, diet.GlutenFree, diet.Keto}},
+	{Name: "Olive Oil", Density: 0.91, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Vegetable Oil", Density: 0.92, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Honey", Density: 1.42, Diets: []string{diet.Vegetarian, diet.GlutenFree}},
+	{Name: "Maple Syrup", Density: 1.32, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Flour", Density: 0.53, Allergens: []string{"gluten"}, Diets: []string{diet.Vegan}},
+	{Name: "Whole Wheat Flour", Density: 0.51, Allergens: []string{"gluten"}, Diets: []string{diet.Vegan}},
+	{Name: "Cornstarch", Density: 0.54, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Sugar", Density: 0.85, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Brown Sugar", Density: 0.93, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Powdered Sugar", Density: 0.56, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Cocoa Powder", Density: 0.42, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Salt", Density: 1.22, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Baking Powder", Density: 0.9, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Baking Soda", Density: 0.87, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Rice", Density: 0.85, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Rolled Oats", Density: 0.41, Allergens: []string{"gluten"}, Diets: []string{diet.Vegan}},
+	{Name: "Lentils", Density: 0.82, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Egg", Density: 1.03, PieceWeight: 50, Allergens: []string{"eggs"}, Diets: []string{diet.Vegetarian, diet.GlutenFree, diet.Keto}},
+	{Name: "Onion", Density: 0.6, PieceWeight: 150, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Tomato", PieceWeight: 120, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Potato", PieceWeight: 170, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Carrot", PieceWeight: 60, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Bell Pepper", PieceWeight: 150, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Lemon", PieceWeight: 100, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Lime", PieceWeight: 65, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Apple", PieceWeight: 180, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Banana", PieceWeight: 120, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Avocado", PieceWeight: 170, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Garlic", PieceWeight: 4, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}}, // one clove
+	{Name: "Green Onion", PieceWeight: 15, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Cucumber", PieceWeight: 300, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Zucchini", PieceWeight: 200, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Orange", PieceWeight: 130, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Bread", PieceWeight: 30, Allergens: []string{"gluten"}, Diets: []string{diet.Vegetarian}}, // one slice
+	{Name: "Chicken Breast", PieceWeight: 175, Diets: []string{diet.GlutenFree, diet.Keto}},
+	{Name: "Sour Cream", Density: 1.0, Allergens: []string{"milk"}, Diets: []string{diet.Vegetarian, diet.GlutenFree, diet.Keto}},
+	{Name: "Cream Cheese", Density: 1.0, Allergens: []string{"milk"}, Diets: []string{diet.Vegetarian, diet.GlutenFree, diet.Keto}},
+	{Name: "Cheddar Cheese", Density: 0.45, Allergens: []string{"milk"}, Diets: []string{diet.GlutenFree, diet.Keto}}, // shredded
+	{Name: "Mozzarella", Density: 0.45, Allergens: []string{"milk"}, Diets: []string{diet.GlutenFree, diet.Keto}},     // shredded
+	{Name: "Parmesan", Density: 0.42, Allergens: []string{"milk"}, Diets: []string{diet.GlutenFree, diet.Keto}},       // grated
+	{Name: "Vanilla Extract", Density: 0.88, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Yeast", Density: 0.6, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Quinoa", Density: 0.72, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Couscous", Density: 0.73, Allergens: []string{"gluten"}, Diets: []string{diet.Vegan}},
+	{Name: "Red Lentils", Density: 0.82, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Chickpeas", Density: 0.8, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Black Beans", Density: 0.78, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Peas", Density: 0.6, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Corn", Density: 0.65, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Spinach", Density: 0.13, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Mushrooms", Density: 0.3, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Blueberries", Density: 0.62, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Strawberries", Density: 0.6, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Almonds", Density: 0.6, Allergens: []string{"nuts"}, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Walnuts", Density: 0.47, Allergens: []string{"nuts"}, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Peanut Butter", Density: 1.09, Allergens: []string{"peanuts"}, Diets: []string{diet.Vegan, diet.GlutenFree}},
+	{Name: "Coconut Milk", Density: 0.97, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Soy Sauce", Density: 1.2, Allergens: []string{"gluten", "soy"}, Diets: []string{diet.Vegan}},
+	{Name: "Vinegar", Density: 1.01, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Black Pepper", Density: 0.46, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Cumin", Density: 0.4, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Cinnamon", Density: 0.53, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Paprika", Density: 0.46, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Cilantro", Density: 0.07, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Parsley", Density: 0.25, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+	{Name: "Basil", Density: 0.09, Diets: []string{diet.Vegan, diet.GlutenFree, diet.Keto}},
+}
+
+// SeedIngredients adds the built-in ingredient table. Existing rows are
+// matched case-insensitively and only have missing values filled in, so
+// edits made through the API are never overwritten.
+func SeedIngredients(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, seed := range defaultIngredients {
+			var ingredient models.Ingredient
+			err := tx.Where("LOWER(name) = ?", strings.ToLower(seed.Name)).First(&ingredient).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				ingredient = models.Ingredient{Name: seed.Name}
+			}
+
+			if ingredient.Density == nil && seed.Density > 0 {
+				density := seed.Density
+				ingredient.Density = &density
+			}
+			if ingredient.PieceWeight == nil && seed.PieceWeight > 0 {
+				weight := seed.PieceWeight
+				ingredient.PieceWeight = &weight
+			}
+			if ingredient.Diets == nil && len(ingredient.Allergens) == 0 {
+				flags := diet.Resolve(diet.Flags{Allergens: seed.Allergens, Diets: seed.Diets})
+				ingredient.Allergens, ingredient.Diets = flags.Allergens, flags.Diets
+			}
+
+			if err := tx.Save(&ingredient).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SeedNutrition loads the bundled nutrition dataset, creating missing
+// ingredients and filling nutrition only where none is recorded yet.
+func SeedNutrition(db *gorm.DB) error {
+	records, err := nutrition.Bundled()
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, record := range records {
+			var ingredient models.Ingredient
+			err := tx.Where("LOWER(name) = ?", strings.ToLower(record.Name)).First(&ingredient).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				ingredient = models.Ingredient{Name: record.Name}
+			}
+			if ingredient.Nutrition != nil {
+				continue
+			}
+
+			facts := models.Nutrition(record.Facts)
+			ingredient.Nutrition = &facts
+			if err := tx.Save(&ingredient).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SeedAisles assigns an aisle, guessed from the name, to ingredients that
+// have none. Ingredients the guess can't place are left for users to assign.
+func SeedAisles(db *gorm.DB) error {
+	var ingredients []models.Ingredient
+	if err := db.Where("aisle IS NULL OR aisle = ''").Find(&ingredients).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, ingredient := range ingredients {
+			guess := aisle.Guess(ingredient.Name)
+			if guess == "" {
+				continue
+			}
+			if err := tx.Model(&ingredient).Update("aisle", guess).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
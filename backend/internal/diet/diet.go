@@ -0,0 +1,231 @@
+// Package diet names allergens and diets and works out which of them apply
+// to a recipe from its ingredients.
+package diet
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidAllergen = errors.New("invalid allergen name")
+var ErrUnknownDiet = errors.New("unknown diet")
+
+// The 14 allergens that must be declared on food sold in the EU
+// (Regulation 1169/2011, Annex II).
+var Allergens = []string{
+	"celery", "gluten", "crustaceans", "eggs", "fish", "lupin", "milk",
+	"molluscs", "mustard", "nuts", "peanuts", "sesame", "soy", "sulphites",
+}
+
+const (
+	Vegan      = "vegan"
+	Vegetarian = "vegetarian"
+	GlutenFree = "gluten-free"
+	Keto       = "keto"
+)
+
+var Diets = []string{Vegan, Vegetarian, GlutenFree, Keto}
+
+// Other names people use for the standard allergens.
+var allergenAliases = map[string]string{
+	"dairy":           "milk",
+	"lactose":         "milk",
+	"egg":             "eggs",
+	"wheat":           "gluten",
+	"cereals":         "gluten",
+	"nut":             "nuts",
+	"tree-nuts":       "nuts",
+	"tree-nut":        "nuts",
+	"peanut":          "peanuts",
+	"soya":            "soy",
+	"soybeans":        "soy",
+	"sesame-seeds":    "sesame",
+	"sulfites":        "sulphites",
+	"sulphur-dioxide": "sulphites",
+	"shellfish":       "crustaceans",
+	"mollusks":        "molluscs",
+	"lupine":          "lupin",
+}
+
+var dietAliases = map[string]string{
+	"vegetarian":  Vegetarian,
+	"veggie":      Vegetarian,
+	"vegan":       Vegan,
+	"gluten-free": GlutenFree,
+	"glutenfree":  GlutenFree,
+	"keto":        Keto,
+	"ketogenic":   Keto,
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slug(name string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// NormalizeAllergen maps a name to its standard allergen, or to a lower-case
+// hyphenated custom allergen such as "kiwi" or "nightshades".
+func NormalizeAllergen(name string) (string, error) {
+	s := slug(name)
+	if s == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAllergen, name)
+	}
+	if standard, ok := allergenAliases[s]; ok {
+		return standard, nil
+	}
+	return s, nil
+}
+
+func NormalizeDiet(name string) (string, error) {
+	if diet, ok := dietAliases[slug(name)]; ok {
+		return diet, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownDiet, name)
+}
+
+// NormalizeAllergens normalises every name, dropping duplicates, and sorts
+// the result.
+func NormalizeAllergens(names []string) ([]string, error) {
+	return normalizeAll(names, NormalizeAllergen)
+}
+
+func NormalizeDiets(names []string) ([]string, error) {
+	return normalizeAll(names, NormalizeDiet)
+}
+
+func normalizeAll(names []string, normalize func(string) (string, error)) ([]string, error) {
+	seen := make(map[string]bool)
+	out := []string{}
+	for _, name := range names {
+		n, err := normalize(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// Flags are what is known about one ingredient. Diets lists the diets the
+// ingredient suits; nil means nobody has said, which is not the same as an
+// empty list, meaning it suits none.
+type Flags struct {
+	Allergens []string
+	Diets     []string
+}
+
+// Resolve applies the rules that follow from the flags themselves: vegan
+// food is vegetarian, and milk, eggs or seafood rule a diet out, as gluten
+// rules out gluten-free.
+func Resolve(f Flags) Flags {
+	if f.Diets == nil {
+		return f
+	}
+
+	has := func(list []string, v string) bool {
+		for _, item := range list {
+			if item == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	excluded := map[string]bool{}
+	for _, a := range f.Allergens {
+		switch a {
+		case "milk", "eggs":
+			excluded[Vegan] = true
+		case "fish", "crustaceans", "molluscs":
+			excluded[Vegan] = true
+			excluded[Vegetarian] = true
+		case "gluten":
+			excluded[GlutenFree] = true
+		}
+	}
+
+	diets := []string{}
+	for _, d := range f.Diets {
+		if !excluded[d] {
+			diets = append(diets, d)
+		}
+	}
+	if has(diets, Vegan) && !has(diets, Vegetarian) {
+		diets = append(diets, Vegetarian)
+	}
+	sort.Strings(diets)
+
+	return Flags{Allergens: f.Allergens, Diets: diets}
+}
+
+// Labels is what a recipe gets from its ingredients: every allergen any of
+// them contains, and the diets all of them suit.
+func Labels(ingredients []Flags) Flags {
+	allergens := map[string]bool{}
+	var diets map[string]bool
+
+	for _, ing := range ingredients {
+		ing = Resolve(ing)
+		for _, a := range ing.Allergens {
+			allergens[a] = true
+		}
+
+		suits := map[string]bool{}
+		for _, d := range ing.Diets {
+			suits[d] = true
+		}
+		if diets == nil {
+			diets = suits
+			continue
+		}
+		for d := range diets {
+			if !suits[d] {
+				delete(diets, d)
+			}
+		}
+	}
+
+	labels := Flags{Allergens: []string{}, Diets: []string{}}
+	for a := range allergens {
+		labels.Allergens = append(labels.Allergens, a)
+	}
+	for d := range diets {
+		labels.Diets = append(labels.Diets, d)
+	}
+	sort.Strings(labels.Allergens)
+	sort.Strings(labels.Diets)
+	return labels
+}
+
+// Violations lists, in words, how a recipe's labels break a set of
+// exclusions: allergens to avoid and diets that must be kept to.
+func Violations(labels Flags, avoid, require []string) []string {
+	contains := map[string]bool{}
+	for _, a := range labels.Allergens {
+		contains[a] = true
+	}
+	suits := map[string]bool{}
+	for _, d := range labels.Diets {
+		suits[d] = true
+	}
+
+	var out []string
+	for _, a := range avoid {
+		if contains[a] {
+			out = append(out, "contains "+a)
+		}
+	}
+	for _, d := range require {
+		if !suits[d] {
+			out = append(out, "not known to be "+d)
+		}
+	}
+	return out
+}
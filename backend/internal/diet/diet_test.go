@@ -0,0 +1,66 @@
+package diet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	allergens, err := NormalizeAllergens([]string{"Tree Nuts", "dairy", "milk", " Kiwi Fruit "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"kiwi-fruit", "milk", "nuts"}; !reflect.DeepEqual(allergens, want) {
+		t.Errorf("got %v, want %v", allergens, want)
+	}
+
+	if _, err := NormalizeAllergen(" -- "); !errors.Is(err, ErrInvalidAllergen) {
+		t.Errorf("expected ErrInvalidAllergen, got %v", err)
+	}
+
+	diets, err := NormalizeDiets([]string{"Gluten Free", "ketogenic"})
+	if err != nil || !reflect.DeepEqual(diets, []string{GlutenFree, Keto}) {
+		t.Errorf("got %v, %v", diets, err)
+	}
+	if _, err := NormalizeDiet("paleo"); !errors.Is(err, ErrUnknownDiet) {
+		t.Errorf("expected ErrUnknownDiet, got %v", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	got := Resolve(Flags{Allergens: []string{"milk", "gluten"}, Diets: []string{Vegan, GlutenFree, Keto}})
+	if !reflect.DeepEqual(got.Diets, []string{Keto}) {
+		t.Errorf("milk and gluten should rule out vegan and gluten-free, got %v", got.Diets)
+	}
+
+	got = Resolve(Flags{Diets: []string{Vegan}})
+	if !reflect.DeepEqual(got.Diets, []string{Vegan, Vegetarian}) {
+		t.Errorf("vegan should imply vegetarian, got %v", got.Diets)
+	}
+
+	if got := Resolve(Flags{Allergens: []string{"fish"}}); got.Diets != nil {
+		t.Errorf("unknown diets should stay unknown, got %v", got.Diets)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	labels := Labels([]Flags{
+		{Diets: []string{Vegan, GlutenFree, Keto}},
+		{Allergens: []string{"eggs"}, Diets: []string{Vegetarian, GlutenFree, Keto}},
+		{Allergens: []string{"milk", "eggs"}, Diets: []string{Vegetarian, GlutenFree}},
+	})
+	if !reflect.DeepEqual(labels.Allergens, []string{"eggs", "milk"}) ||
+		!reflect.DeepEqual(labels.Diets, []string{GlutenFree, Vegetarian}) {
+		t.Errorf("unexpected labels %+v", labels)
+	}
+
+	labels = Labels([]Flags{{Diets: []string{Vegan}}, {}})
+	if len(labels.Diets) != 0 {
+		t.Errorf("an ingredient with unknown diets should leave none, got %v", labels.Diets)
+	}
+
+	if v := Violations(labels, []string{"nuts"}, []string{Vegan}); !reflect.DeepEqual(v, []string{"not known to be vegan"}) {
+		t.Errorf("unexpected violations %v", v)
+	}
+}
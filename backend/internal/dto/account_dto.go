@@ -0,0 +1,23 @@
+package dto
+
+// Query parameters accepted by POST /api/account/import
+type AccountImportQuery struct {
+	OnConflict string `form:"on_conflict"` // skip (default), duplicate, fail
+	DryRun     bool   `form:"dry_run"`
+}
+
+type ImportConflict struct {
+	Type       string `json:"type"` // ingredient, recipe, meal_plan, shopping_list
+	Name       string `json:"name"`
+	Resolution string `json:"resolution"`
+}
+
+type AccountImportResponse struct {
+	DryRun             bool             `json:"dry_run"`
+	RecipesImported    int              `json:"recipes_imported"`
+	TagsCreated        int              `json:"tags_created"`
+	IngredientsCreated int              `json:"ingredients_created"`
+	MealPlansImported  int              `json:"meal_plans_imported"`
+	ListsImported      int              `json:"shopping_lists_imported"`
+	Conflicts          []ImportConflict `json:"conflicts"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+// CookRequest is the optional body when marking a recipe or meal plan as
+// cooked.
+type CookRequest struct {
+	Date   string `json:"date"` // YYYY-MM-DD; the plan's date or today by default
+	Notes  string `json:"notes"`
+	Rating *int   `json:"rating" binding:"omitempty,min=1,max=5"`
+}
+
+type CookEventResponse struct {
+	ID           uint   `json:"id"`
+	RecipeID     uint   `json:"recipe_id"`
+	RecipeName   string `json:"recipe_name"`
+	MealPlanID   *uint  `json:"meal_plan_id,omitempty"`
+	Date         string `json:"date"`
+	Servings     int    `json:"servings"`      // as cooked
+	BaseServings int    `json:"base_servings"` // the recipe's, at the time
+	Notes        string `json:"notes,omitempty"`
+	Rating       *int   `json:"rating,omitempty"`
+}
+
+type RecipeCookStats struct {
+	RecipeID       uint     `json:"recipe_id"`
+	RecipeName     string   `json:"recipe_name"`
+	TimesCooked    int      `json:"times_cooked"`
+	LastCooked     string   `json:"last_cooked"`
+	ServingsCooked int      `json:"servings_cooked"`
+	AverageRating  *float64 `json:"average_rating,omitempty"`
+}
+
+type RecipeCookHistory struct {
+	RecipeCookStats
+	Events []CookEventResponse `json:"events"`
+}
@@ -1,12 +1,93 @@
 package dto
 
 type CreateIngredientRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name        string          `json:"name" binding:"required"`
+	Density     *float64        `json:"density" binding:"omitempty,gt=0"`      // g/ml
+	PieceWeight *float64        `json:"piece_weight" binding:"omitempty,gt=0"` // g per piece
+	Nutrition   *NutritionFacts `json:"nutrition"`                             // per 100 g
+	Allergens   []string        `json:"allergens"`                             // EU allergens or custom names
+	Diets       []string        `json:"diets"`                                 // vegan, vegetarian, gluten-free, keto; null when unknown
+	Aisle       string          `json:"aisle"`                                 // guessed from the name when empty
+}
+
+type UpdateIngredientRequest struct {
+	Name        string          `json:"name" binding:"required"`
+	Density     *float64        `json:"density" binding:"omitempty,gt=0"`
+	PieceWeight *float64        `json:"piece_weight" binding:"omitempty,gt=0"`
+	Nutrition   *NutritionFacts `json:"nutrition"`
+	Allergens   []string        `json:"allergens"`
+	Diets       []string        `json:"diets"`
+	Aisle       string          `json:"aisle"` // unchanged when empty
 }
 
 type IngredientMasterResponse struct {
+	ID          uint            `json:"id"`
+	Name        string          `json:"name"`
+	Density     *float64        `json:"density"`
+	PieceWeight *float64        `json:"piece_weight"`
+	Nutrition   *NutritionFacts `json:"nutrition"`
+	Allergens   []string        `json:"allergens"`
+	Diets       []string        `json:"diets"`
+	Aisle       string          `json:"aisle"`
+	Aliases     []AliasResponse `json:"aliases"`
+}
+
+type AliasRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type AliasResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type MergeIngredientsRequest struct {
+	CanonicalID  uint   `json:"canonical_id" binding:"required"`
+	DuplicateIDs []uint `json:"duplicate_ids" binding:"required,min=1"`
+}
+
+// MergeIngredientsResponse is the merged ingredient and how many rows now
+// point at it instead of a duplicate.
+type MergeIngredientsResponse struct {
+	Ingredient        IngredientMasterResponse `json:"ingredient"`
+	Merged            []string                 `json:"merged"` // names of the removed duplicates
+	RecipeIngredients int64                    `json:"recipe_ingredients"`
+	ShoppingListItems int64                    `json:"shopping_list_items"`
+	Prices            int64                    `json:"prices"`
+	PantryItems       int64                    `json:"pantry_items"`
+}
+
+type DuplicateQuery struct {
+	Threshold float64 `form:"threshold" binding:"omitempty,gt=0,lte=1"` // default 0.8
+	Limit     int     `form:"limit" binding:"omitempty,min=1,max=200"`  // default 50
+}
+
+type DuplicateIngredient struct {
 	ID   uint   `json:"id"`
 	Name string `json:"name"`
+	Uses int64  `json:"uses"` // recipe lines using it
+}
+
+// DuplicateSuggestion is a pair of ingredients that look like the same one.
+// Canonical is the one to keep, the more used of the two.
+type DuplicateSuggestion struct {
+	Canonical DuplicateIngredient `json:"canonical"`
+	Duplicate DuplicateIngredient `json:"duplicate"`
+	Score     float64             `json:"score"`
+	Reason    string              `json:"reason"` // "same name", "spelling" or "word order"
+}
+
+type IngredientSearchQuery struct {
+	Q     string `form:"q" binding:"required"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"` // default 10
+}
+
+type IngredientSearchResult struct {
+	ID    uint    `json:"id"`
+	Name  string  `json:"name"`
+	Alias string  `json:"alias,omitempty"` // the alias that matched, when it beat the name
+	Uses  int64   `json:"uses"`            // the user's recipe lines using it
+	Score float64 `json:"score"`
 }
 
 type AddRecipeIngredientRequest struct {
@@ -14,3 +95,22 @@ type AddRecipeIngredientRequest struct {
 	Quantity     float64 `json:"quantity" binding:"required"`
 	Unit         string  `json:"unit" binding:"required"`
 }
+
+type ParseIngredientsRequest struct {
+	Text string `json:"text" binding:"required"` // one ingredient per line
+}
+
+type ParsedIngredientResponse struct {
+	Original  string  `json:"original"`
+	Name      string  `json:"name"`
+	Amount    float64 `json:"amount"`
+	AmountMax float64 `json:"amount_max,omitempty"`
+	Unit      string  `json:"unit"`
+	Size      string  `json:"size,omitempty"`
+	Note      string  `json:"note,omitempty"`
+}
+
+type ParseIngredientsResponse struct {
+	Ingredients []ParsedIngredientResponse `json:"ingredients"`
+	Errors      []string                   `json:"errors"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+// MatchRecipesRequest lists what is at hand, by name, by ID or both.
+type MatchRecipesRequest struct {
+	Ingredients   []string `json:"ingredients"`
+	IngredientIDs []uint   `json:"ingredient_ids"`
+	MaxMissing    int      `json:"max_missing" binding:"gte=0"` // leave out recipes missing more
+	Page          int      `json:"page" binding:"omitempty,gte=1"`
+	Limit         int      `json:"limit" binding:"omitempty,gte=1,lte=100"`
+}
+
+// IngredientSubstitute is a missing ingredient that something at hand can
+// stand in for.
+type IngredientSubstitute struct {
+	Ingredient string `json:"ingredient"`
+	Use        string `json:"use"`
+}
+
+type RecipeMatch struct {
+	Recipe RecipeResponse `json:"recipe"`
+	// Coverage is the share of the required ingredients at hand or
+	// substitutable; optional ones don't count.
+	Coverage    float64 `json:"coverage"`
+	Matched     int     `json:"matched"`
+	Missing     int     `json:"missing"`
+	Substitutes int     `json:"substitutes"`
+	Optional    int     `json:"optional"` // missing but marked optional
+
+	MatchedIngredients    []string               `json:"matched_ingredients"`
+	MissingIngredients    []string               `json:"missing_ingredients"`
+	SubstituteIngredients []IngredientSubstitute `json:"substitute_ingredients"`
+	OptionalIngredients   []string               `json:"optional_ingredients"`
+}
+
+type MatchRecipesResponse struct {
+	Total      int64         `json:"total"`
+	Page       int           `json:"page"`
+	Limit      int           `json:"limit"`
+	Unknown    []string      `json:"unknown"`     // names not in the ingredient list, still used for substitutes
+	UnknownIDs []uint        `json:"unknown_ids"` // IDs not in the ingredient list
+	Recipes    []RecipeMatch `json:"recipes"`
+}
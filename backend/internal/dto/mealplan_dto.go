@@ -20,3 +20,7 @@ type MealPlanResponse struct {
 	TargetServings int            `json:"target_servings"`
 	Recipe         RecipeResponse `json:"recipe"`
 }
+
+type CreateMealPlanResponse struct {
+	Warnings []string `json:"warnings"` // how the recipe breaks the user's exclusions
+}
@@ -0,0 +1,90 @@
+package dto
+
+// NutritionFacts are per 100 g on ingredients and for the stated amount on
+// recipes.
+type NutritionFacts struct {
+	Kcal    float64 `json:"kcal" binding:"gte=0"`
+	Protein float64 `json:"protein_g" binding:"gte=0"`
+	Fat     float64 `json:"fat_g" binding:"gte=0"`
+	Carbs   float64 `json:"carbs_g" binding:"gte=0"`
+	Fiber   float64 `json:"fiber_g" binding:"gte=0"`
+	Sugar   float64 `json:"sugar_g" binding:"gte=0"`
+	Sodium  float64 `json:"sodium_mg" binding:"gte=0"`
+}
+
+type IngredientNutrition struct {
+	Name      string         `json:"name"`
+	Quantity  float64        `json:"quantity"`
+	Unit      string         `json:"unit"`
+	Grams     float64        `json:"grams"`
+	Nutrition NutritionFacts `json:"nutrition"`
+}
+
+// UnresolvedIngredient is left out of the totals, with the reason why.
+type UnresolvedIngredient struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Reason   string  `json:"reason"`
+}
+
+type NutritionSummary struct {
+	Servings    int                    `json:"servings"`
+	Total       NutritionFacts         `json:"total"`
+	PerServing  NutritionFacts         `json:"per_serving"`
+	Ingredients []IngredientNutrition  `json:"ingredients"`
+	Unresolved  []UnresolvedIngredient `json:"unresolved"`
+}
+
+type RecipeNutritionResponse struct {
+	RecipeID uint   `json:"recipe_id"`
+	Name     string `json:"name"`
+	NutritionSummary
+}
+
+// Query parameters accepted by POST /api/ingredients/nutrition/import
+type NutritionImportQuery struct {
+	Overwrite bool `form:"overwrite"` // replace nutrition already recorded
+	Create    bool `form:"create"`    // add ingredients that don't exist yet
+}
+
+type NutritionImportResponse struct {
+	Updated  int      `json:"updated"`
+	Created  int      `json:"created"`
+	Kept     []string `json:"kept"`      // already had nutrition and overwrite was off
+	NotFound []string `json:"not_found"` // no such ingredient and create was off
+}
+
+// MealNutrition is one meal plan entry at its target servings. Source is
+// "recipe" when the recipe has per-serving values entered, "ingredients"
+// when they are worked out from its ingredients, and "none" when neither
+// gives anything.
+type MealNutrition struct {
+	MealPlanID uint                   `json:"meal_plan_id"`
+	MealType   string                 `json:"meal_type"`
+	RecipeID   uint                   `json:"recipe_id"`
+	RecipeName string                 `json:"recipe_name"`
+	Servings   int                    `json:"servings"`
+	Source     string                 `json:"source"`
+	Nutrition  NutritionFacts         `json:"nutrition"`
+	Unresolved []UnresolvedIngredient `json:"unresolved,omitempty"`
+}
+
+type DayNutrition struct {
+	Date      string                    `json:"date"`
+	Total     NutritionFacts            `json:"total"`
+	MealTypes map[string]NutritionFacts `json:"meal_types"`
+	Meals     []MealNutrition           `json:"meals"`
+	// Delta is total minus target for each nutrient with a target, keyed
+	// like NutritionFacts: negative is under, positive over.
+	Delta map[string]float64 `json:"delta,omitempty"`
+}
+
+type MealPlanNutritionResponse struct {
+	StartDate string                    `json:"start_date"`
+	EndDate   string                    `json:"end_date"`
+	Targets   *NutritionFacts           `json:"targets"`
+	Total     NutritionFacts            `json:"total"`
+	MealTypes map[string]NutritionFacts `json:"meal_types"`
+	Days      []DayNutrition            `json:"days"`
+}
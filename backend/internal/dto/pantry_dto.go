@@ -0,0 +1,26 @@
+package dto
+
+type CreatePantryItemRequest struct {
+	IngredientID uint    `json:"ingredient_id" binding:"required"`
+	Quantity     float64 `json:"quantity" binding:"gt=0"`
+	Unit         string  `json:"unit"`       // empty for pieces
+	ExpiresAt    string  `json:"expires_at"` // YYYY-MM-DD, optional
+}
+
+// UpdatePantryItemRequest changes only the fields that are set. An empty
+// expires_at clears the expiry date.
+type UpdatePantryItemRequest struct {
+	Quantity  *float64 `json:"quantity" binding:"omitempty,gte=0"`
+	Unit      *string  `json:"unit"`
+	ExpiresAt *string  `json:"expires_at"`
+}
+
+type PantryItemResponse struct {
+	ID           uint    `json:"id"`
+	IngredientID uint    `json:"ingredient_id"`
+	Ingredient   string  `json:"ingredient"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	ExpiresAt    string  `json:"expires_at,omitempty"`
+	Expired      bool    `json:"expired"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type PhotoResponse struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	// Thumbnails maps a width in pixels, e.g. "480", to a JPEG URL
+	Thumbnails map[string]string `json:"thumbnails"`
+}
@@ -0,0 +1,82 @@
+package dto
+
+type CreatePriceRequest struct {
+	IngredientID uint    `json:"ingredient_id" binding:"required"`
+	Price        float64 `json:"price" binding:"gte=0"`
+	Quantity     float64 `json:"quantity" binding:"omitempty,gt=0"` // defaults to 1
+	Unit         string  `json:"unit"`                              // kg, l, dozen...; empty for pieces
+	Store        string  `json:"store"`
+	Date         string  `json:"date"` // YYYY-MM-DD, defaults to today
+}
+
+// Query parameters accepted by GET /api/prices
+type PriceListQuery struct {
+	IngredientID uint   `form:"ingredient_id"`
+	Store        string `form:"store"`
+}
+
+type PriceResponse struct {
+	ID           uint    `json:"id"`
+	IngredientID uint    `json:"ingredient_id"`
+	Ingredient   string  `json:"ingredient"`
+	Price        float64 `json:"price"`
+	Quantity     float64 `json:"quantity"`
+	Unit         string  `json:"unit"`
+	Store        string  `json:"store"`
+	Date         string  `json:"date"`
+}
+
+// CostItem is an amount of an ingredient priced with its latest price.
+type CostItem struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Cost     float64 `json:"cost"`
+	PriceID  uint    `json:"price_id"`
+	Store    string  `json:"store,omitempty"`
+}
+
+// UnpricedItem is left out of a cost total, with the reason why.
+type UnpricedItem struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	Reason   string  `json:"reason"`
+}
+
+type RecipeCostResponse struct {
+	RecipeID   uint           `json:"recipe_id"`
+	Name       string         `json:"name"`
+	Servings   int            `json:"servings"`
+	Total      float64        `json:"total"`
+	PerServing float64        `json:"per_serving"`
+	Items      []CostItem     `json:"items"`
+	Unpriced   []UnpricedItem `json:"unpriced"`
+}
+
+type MealCost struct {
+	MealPlanID uint    `json:"meal_plan_id"`
+	MealType   string  `json:"meal_type"`
+	RecipeID   uint    `json:"recipe_id"`
+	RecipeName string  `json:"recipe_name"`
+	Servings   int     `json:"servings"`
+	Cost       float64 `json:"cost"`
+	Complete   bool    `json:"complete"` // false when some ingredients had no usable price
+}
+
+type DayCost struct {
+	Date  string     `json:"date"`
+	Total float64    `json:"total"`
+	Meals []MealCost `json:"meals"`
+}
+
+// MealPlanCostResponse prices the range's shopping, ingredients summed as on
+// a generated shopping list, and breaks the cost down by day and meal.
+type MealPlanCostResponse struct {
+	StartDate string         `json:"start_date"`
+	EndDate   string         `json:"end_date"`
+	Total     float64        `json:"total"`
+	Items     []CostItem     `json:"items"`
+	Unpriced  []UnpricedItem `json:"unpriced"`
+	Days      []DayCost      `json:"days"`
+}
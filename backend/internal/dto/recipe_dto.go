@@ -4,6 +4,7 @@ type RecipeIngredientRequest struct {
 	Name   string  `json:"name" binding:"required"`
 	Amount float64 `json:"amount" binding:"required"`
 	Unit   string  `json:"unit" binding:"required"`
+	Note   string  `json:"note"`
 }
 
 type CreateRecipeRequest struct {
@@ -14,17 +15,21 @@ type CreateRecipeRequest struct {
 	CookTime    int    `json:"cook_time"`
 	Category    string `json:"category" binding:"required"`
 
-	Ingredients  []RecipeIngredientRequest `json:"ingredients"`
-	Instructions []string                  `json:"instructions"`
+	Ingredients     []RecipeIngredientRequest `json:"ingredients"`
+	IngredientsText string                    `json:"ingredients_text"` // one ingredient per line, appended to ingredients
+	Instructions    []string                  `json:"instructions"`
+	Tags            []string                  `json:"tags"`
+	Nutrition       *NutritionFacts           `json:"nutrition"` // per serving, optional
 }
 
 type RecipeResponse struct {
-	ID          uint   `json:"id"`
-	Name        string `json:"name"`
-	Servings    int    `json:"servings"`
-	TotalTime   int    `json:"total_time"`
-	Category    string `json:"category"`
-	Description string `json:"description"`
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Servings    int      `json:"servings"`
+	TotalTime   int      `json:"total_time"`
+	Category    string   `json:"category"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
 }
 
 type UpdateRecipeRequest struct {
@@ -35,8 +40,11 @@ type UpdateRecipeRequest struct {
 	CookTime    int    `json:"cook_time"`
 	Category    string `json:"category" binding:"required"`
 
-	Ingredients  []RecipeIngredientRequest `json:"ingredients"`
-	Instructions []string                  `json:"instructions"`
+	Ingredients     []RecipeIngredientRequest `json:"ingredients"`
+	IngredientsText string                    `json:"ingredients_text"`
+	Instructions    []string                  `json:"instructions"`
+	Tags            []string                  `json:"tags"`      // omit to keep the current tags
+	Nutrition       *NutritionFacts           `json:"nutrition"` // per serving; omit to keep, all zeros to clear
 }
 
 type RecipeDetailResponse struct {
@@ -51,16 +59,44 @@ type RecipeDetailResponse struct {
 
 	Ingredients  []IngredientResponse  `json:"ingredients"`
 	Instructions []InstructionResponse `json:"instructions"`
+	Tags         []string              `json:"tags"`
+	Photo        *PhotoResponse        `json:"photo,omitempty"`
+	Nutrition    *NutritionFacts       `json:"nutrition,omitempty"` // per serving, as entered
+	Allergens    []string              `json:"allergens"`           // from the ingredients
+	Diets        []string              `json:"diets"`               // suited by every ingredient
+	LastCooked   string                `json:"last_cooked,omitempty"`
+	TimesCooked  int                   `json:"times_cooked"`
 }
 
 type IngredientResponse struct {
 	Name     string  `json:"name"`
 	Quantity float64 `json:"quantity"`
 	Unit     string  `json:"unit"`
+	Note     string  `json:"note,omitempty"`
 }
 
 type InstructionResponse struct {
-	ID         uint   `json:"id"`
-	StepNumber int    `json:"step_number"`
-	Text       string `json:"text"`
+	ID         uint           `json:"id"`
+	StepNumber int            `json:"step_number"`
+	Text       string         `json:"text"`
+	Photo      *PhotoResponse `json:"photo,omitempty"`
+}
+
+// Query parameters accepted by GET /api/recipes
+type RecipeListQuery struct {
+	Q                  string   `form:"q"`
+	Category           string   `form:"category"`
+	MaxTotalTime       int      `form:"max_total_time" binding:"omitempty,gte=0"`
+	MinServings        int      `form:"min_servings" binding:"omitempty,gte=0"`
+	MaxServings        int      `form:"max_servings" binding:"omitempty,gte=0"`
+	Ingredients        []string `form:"ingredient"`
+	ExcludeIngredients []string `form:"exclude_ingredient"`
+	Tags               []string `form:"tag"`
+	TagMatch           string   `form:"tag_match"` // any (default), all
+	Diets              []string `form:"diet"`
+	ExcludeAllergens   []string `form:"exclude_allergen"`
+	Sort               string   `form:"sort"`  // name, created, updated, total_time
+	Order              string   `form:"order"` // asc, desc
+	Page               int      `form:"page" binding:"omitempty,gte=1"`
+	Limit              int      `form:"limit" binding:"omitempty,gte=1,lte=100"`
 }
@@ -0,0 +1,15 @@
+package dto
+
+// Returned by POST /api/recipes/import. ID is zero for dry runs.
+type RecipeImportResponse struct {
+	ID       uint                `json:"id,omitempty"`
+	Recipe   CreateRecipeRequest `json:"recipe"`
+	Warnings []string            `json:"warnings"`
+}
+
+// A rendered recipe for GET /api/recipes/:id/export.
+type RecipeExport struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
@@ -13,4 +13,5 @@ type ScaledRecipeResponse struct {
 	OriginalServings int                        `json:"original_servings"`
 	ScaledServings   int                        `json:"scaled_servings"`
 	Ingredients      []ScaledIngredientResponse `json:"ingredients"`
+	Nutrition        NutritionSummary           `json:"nutrition"`
 }
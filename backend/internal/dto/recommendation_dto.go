@@ -0,0 +1,49 @@
+package dto
+
+// Query parameters accepted by GET /api/recipes/recommendations. Weights
+// left out use the defaults; they are relative, so only their ratio matters.
+type RecommendationQuery struct {
+	Date        string   `form:"date"`                                    // YYYY-MM-DD, today by default
+	ExcludeDays *int     `form:"exclude_days" binding:"omitempty,gte=0"`  // skip recipes planned this many days back or later that week, 7 by default; 0 keeps all
+	Limit       int      `form:"limit" binding:"omitempty,gte=1,lte=100"` // 10 by default
+	Recency     *float64 `form:"w_recency" binding:"omitempty,gte=0"`
+	Overlap     *float64 `form:"w_overlap" binding:"omitempty,gte=0"`
+	Variety     *float64 `form:"w_variety" binding:"omitempty,gte=0"`
+}
+
+type RecommendationWeights struct {
+	Recency float64 `json:"recency"`
+	Overlap float64 `json:"overlap"`
+	Variety float64 `json:"variety"`
+}
+
+// SignalScore is one signal's part of a recommendation score. Points are
+// the value times its share of the total weight, so they add up to the
+// score.
+type SignalScore struct {
+	Value  float64 `json:"value"` // 0 to 1
+	Weight float64 `json:"weight"`
+	Points float64 `json:"points"`
+}
+
+type RecommendationSignals struct {
+	Recency SignalScore `json:"recency"` // long since last planned
+	Overlap SignalScore `json:"overlap"` // reuses ingredients planned this week
+	Variety SignalScore `json:"variety"` // category is little planned this week
+}
+
+type RecipeRecommendation struct {
+	Recipe            RecipeResponse        `json:"recipe"`
+	Score             float64               `json:"score"`
+	Signals           RecommendationSignals `json:"signals"`
+	LastPlanned       string                `json:"last_planned,omitempty"`
+	SharedIngredients []string              `json:"shared_ingredients"`
+}
+
+type RecommendationResponse struct {
+	Date            string                 `json:"date"`
+	WeekStart       string                 `json:"week_start"`
+	WeekEnd         string                 `json:"week_end"`
+	Weights         RecommendationWeights  `json:"weights"`
+	Recommendations []RecipeRecommendation `json:"recommendations"`
+}
@@ -1,28 +1,156 @@
 package dto
 
+import "time"
+
 type GenerateShoppingListRequest struct {
 	StartDate string `json:"start_date" binding:"required"`
 	EndDate   string `json:"end_date" binding:"required"`
+	UsePantry bool   `json:"use_pantry"` // subtract pantry stock from what's needed
 }
 
 type ShoppingListResponse struct {
 	ID        uint                       `json:"id"`
 	StartDate string                     `json:"start_date"`
 	EndDate   string                     `json:"end_date"`
-	Items     []ShoppingListItemResponse `json:"items"`
+	Items     []ShoppingListItemResponse `json:"items"` // in aisle order, then by name
+
+	Store  string              `json:"store,omitempty"` // the layout the aisles follow, empty for the default
+	Aisles []ShoppingListAisle `json:"aisles"`          // the items grouped by aisle
+
+	EstimatedTotal float64 `json:"estimated_total"` // sum of the items that have a cost
+	UnpricedItems  int     `json:"unpriced_items"`
+
+	// Covered lists what the pantry fully covered when generating with
+	// use_pantry. These lines are not saved on the list.
+	Covered []ShoppingListItemResponse `json:"covered,omitempty"`
+
+	// Token is the change token to start syncing from.
+	Token uint `json:"token"`
+
+	// Changes is only set on refresh.
+	Changes *ShoppingListChanges `json:"changes,omitempty"`
 }
 
 type ShoppingListItemResponse struct {
-	ID           uint    `json:"id"`
-	IngredientID uint    `json:"ingredient_id"`
-	Name         string  `json:"name"`
-	Quantity     float64 `json:"quantity"`
-	Unit         string  `json:"unit"`
-	Checked      bool    `json:"checked"`
+	ID           uint     `json:"id"`
+	IngredientID *uint    `json:"ingredient_id"` // null for free-text items
+	Name         string   `json:"name"`
+	Quantity     float64  `json:"quantity"` // same as to_buy
+	Unit         string   `json:"unit"`
+	Aisle        string   `json:"aisle"`
+	Needed       float64  `json:"needed"`  // what the meal plan calls for
+	ToBuy        float64  `json:"to_buy"`  // needed less pantry stock
+	Display      string   `json:"display"` // to_buy with its unit, for reading: "1 ½ cups"
+	Note         string   `json:"note,omitempty"`
+	Manual       bool     `json:"manual"` // added by hand, kept when the list is regenerated
+	Checked      bool     `json:"checked"`
+	Stale        bool     `json:"stale,omitempty"` // checked off but no longer needed by the meal plan
+	Version      uint     `json:"version"`
+	ClientID     string   `json:"client_id,omitempty"` // the id the client gave an item it added offline
+	Cost         *float64 `json:"cost"`                // from the latest price, null when there is none or it can't be converted
+}
+
+// ShoppingListExport is a list written out in one of the export formats.
+type ShoppingListExport struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+type RefreshShoppingListRequest struct {
+	UsePantry bool `json:"use_pantry"`
+}
+
+// ShoppingListChanges summarises a refresh.
+type ShoppingListChanges struct {
+	Added     []ShoppingListItemChange `json:"added"`
+	Updated   []ShoppingListItemChange `json:"updated"`
+	Removed   []ShoppingListItemChange `json:"removed"`
+	Flagged   []ShoppingListItemChange `json:"flagged"` // already checked, so kept and marked stale
+	Unchanged int                      `json:"unchanged"`
+}
+
+type ShoppingListItemChange struct {
+	ID   uint    `json:"id"`
+	Name string  `json:"name"`
+	Unit string  `json:"unit"`
+	From float64 `json:"from"` // quantity before the refresh, 0 when added
+	To   float64 `json:"to"`   // 0 when removed
+}
+
+type ShoppingListAisle struct {
+	Aisle string                     `json:"aisle"`
+	Items []ShoppingListItemResponse `json:"items"`
 }
 
+// AddManualItemRequest adds an item by hand, either for an ingredient or,
+// without ingredient_id, as free text.
 type AddManualItemRequest struct {
-	Name     string  `json:"name" binding:"required"`
-	Quantity float64 `json:"quantity"`
-	Unit     string  `json:"unit"`
+	IngredientID *uint   `json:"ingredient_id"`
+	Name         string  `json:"name" binding:"required_without=IngredientID"`
+	Quantity     float64 `json:"quantity" binding:"gte=0"`
+	Unit         string  `json:"unit"`
+	Note         string  `json:"note"`
+}
+
+// UpdateShoppingListItemRequest changes the fields that are present.
+type UpdateShoppingListItemRequest struct {
+	Name     *string  `json:"name"` // free-text items only
+	Quantity *float64 `json:"quantity" binding:"omitempty,gte=0"`
+	Unit     *string  `json:"unit"`
+	Note     *string  `json:"note"`
+}
+
+type ShoppingListQuery struct {
+	Page  int `form:"page" binding:"omitempty,gte=1"`
+	Limit int `form:"limit" binding:"omitempty,gte=1,lte=100"` // default 20
+}
+
+type ShoppingListSummary struct {
+	ID        uint   `json:"id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Items     int    `json:"items"`
+	Checked   int    `json:"checked"`
+	CreatedAt string `json:"created_at"`
+}
+
+// SyncShoppingListRequest carries the operations a client queued while
+// offline and the change token from its last sync.
+type SyncShoppingListRequest struct {
+	Since      uint            `json:"since"` // 0 fetches the whole list
+	Operations []SyncOperation `json:"operations" binding:"dive"`
+}
+
+// SyncOperation is one change made on the client. Edits and toggles name
+// the item by item_id, or by client_item_id for items added offline.
+type SyncOperation struct {
+	OpID         string    `json:"op_id" binding:"required"` // unique per operation; a replayed op_id is not applied again
+	Type         string    `json:"type" binding:"required,oneof=add edit toggle delete"`
+	At           time.Time `json:"at" binding:"required"` // when the change was made on the client
+	ItemID       uint      `json:"item_id"`
+	ClientItemID string    `json:"client_item_id"`
+
+	Checked      *bool    `json:"checked"` // toggle: the state set; flips when missing
+	IngredientID *uint    `json:"ingredient_id"`
+	Name         *string  `json:"name"`
+	Quantity     *float64 `json:"quantity" binding:"omitempty,gte=0"`
+	Unit         *string  `json:"unit"`
+	Note         *string  `json:"note"`
+}
+
+type SyncShoppingListResponse struct {
+	Token   uint                       `json:"token"`   // send as since next time
+	Full    bool                       `json:"full"`    // changed holds the whole list; replace the local copy
+	Results []SyncOperationResult      `json:"results"` // one per operation, in order
+	Changed []ShoppingListItemResponse `json:"changed"` // items added or changed since the token
+	Deleted []uint                     `json:"deleted"` // items removed since the token
+}
+
+type SyncOperationResult struct {
+	OpID    string   `json:"op_id"`
+	Status  string   `json:"status"` // applied, duplicate, conflict, deleted or rejected
+	ItemID  uint     `json:"item_id,omitempty"`
+	Ignored []string `json:"ignored,omitempty"` // fields a newer write kept
+	Error   string   `json:"error,omitempty"`
 }
@@ -0,0 +1,12 @@
+package dto
+
+type StoreLayoutRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Aisles []string `json:"aisles" binding:"required,min=1"` // walked in this order; aisles left out come last
+}
+
+type StoreLayoutResponse struct {
+	ID     uint     `json:"id"`
+	Name   string   `json:"name"`
+	Aisles []string `json:"aisles"`
+}
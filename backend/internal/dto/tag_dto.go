@@ -0,0 +1,19 @@
+package dto
+
+type CreateTagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type UpdateTagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type TagResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type MigrateCategoriesResponse struct {
+	TagsCreated   int `json:"tags_created"`
+	RecipesTagged int `json:"recipes_tagged"`
+}
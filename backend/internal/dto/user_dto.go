@@ -23,3 +23,18 @@ type LoginResponse struct {
 	Token string       `json:"token"`
 	User  UserResponse `json:"user"`
 }
+
+type ProfileResponse struct {
+	ID         uint            `json:"id"`
+	Name       string          `json:"name"`
+	Email      string          `json:"email"`
+	Targets    *NutritionFacts `json:"targets"` // daily, null when not set
+	Exclusions Exclusions      `json:"exclusions"`
+}
+
+// Exclusions are allergens to avoid and diets to keep to when planning meals.
+type Exclusions struct {
+	Allergens []string `json:"allergens"`
+	Diets     []string `json:"diets"`
+	Refuse    bool     `json:"refuse"` // refuse to plan a recipe that breaks them, rather than warn
+}
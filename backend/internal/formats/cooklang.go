@@ -0,0 +1,274 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register(Format{
+		Name:        "cooklang",
+		Aliases:     []string{"cook"},
+		ContentType: "text/plain; charset=utf-8",
+		Extension:   "cook",
+		Encoder:     cooklangFormat{},
+		Decoder:     cooklangFormat{},
+	})
+}
+
+// cooklangFormat reads and writes https://cooklang.org recipes. Our recipes
+// keep ingredients apart from the steps, so the encoder writes them as a
+// leading paragraph made only of ingredient references; the decoder treats
+// any such paragraph as an ingredient list rather than a step. Text that
+// Cooklang would read as markup is escaped with a backslash.
+type cooklangFormat struct{}
+
+func (cooklangFormat) Encode(w io.Writer, recipe *Recipe) error {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, ">> title: %s\n", oneLine(recipe.Name))
+	if d := oneLine(recipe.Description); d != "" {
+		fmt.Fprintf(b, ">> description: %s\n", d)
+	}
+	fmt.Fprintf(b, ">> servings: %d\n", recipe.Servings)
+	fmt.Fprintf(b, ">> course: %s\n", oneLine(recipe.Category))
+	fmt.Fprintf(b, ">> prep time: %d minutes\n", recipe.PrepTime)
+	fmt.Fprintf(b, ">> cook time: %d minutes\n", recipe.CookTime)
+	if len(recipe.Tags) > 0 {
+		fmt.Fprintf(b, ">> tags: %s\n", strings.Join(recipe.Tags, ", "))
+	}
+
+	if len(recipe.Ingredients) > 0 {
+		b.WriteString("\n-- Ingredients\n")
+		for _, ing := range recipe.Ingredients {
+			b.WriteString(cooklangIngredient(ing) + "\n")
+		}
+	}
+
+	for _, step := range recipe.Instructions {
+		fmt.Fprintf(b, "\n%s\n", cooklangEscape(oneLine(step), "@#~", true))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func cooklangIngredient(ing Ingredient) string {
+	quantity := ""
+	switch {
+	case ing.Unit != "":
+		quantity = formatAmount(ing.Amount) + "%" + cooklangEscape(oneLine(ing.Unit), "{}", false)
+	case ing.Amount != 0:
+		quantity = formatAmount(ing.Amount)
+	}
+
+	ref := "@" + cooklangEscape(oneLine(ing.Name), "@#~{}", false) + "{" + quantity + "}"
+	if ing.Note != "" {
+		ref += "(" + cooklangEscape(oneLine(ing.Note), "()", false) + ")"
+	}
+	return ref
+}
+
+// cooklangEscapable are the characters a backslash escapes.
+const cooklangEscapable = `\@#~{}()[]-=>`
+
+// cooklangEscape escapes backslashes, the special characters and anything
+// that would start a comment. A line start also escapes the "=" of a
+// section and the ">>" of metadata.
+func cooklangEscape(s, special string, lineStart bool) string {
+	b := &strings.Builder{}
+	prev := rune(0)
+	for i, r := range s {
+		escape := r == '\\' || strings.ContainsRune(special, r) ||
+			(r == '-' && (prev == '-' || prev == '[')) ||
+			(lineStart && i == 0 && (r == '=' || r == '>'))
+		if escape {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// cooklangProtect swaps escaped characters for private use runes, so the
+// parser passes over them until cooklangRestore puts them back.
+func cooklangProtect(line string) string {
+	b := &strings.Builder{}
+	escaped := false
+	for _, r := range line {
+		if escaped {
+			escaped = false
+			if i := strings.IndexRune(cooklangEscapable, r); i >= 0 {
+				b.WriteRune(0xE000 + rune(i))
+				continue
+			}
+			b.WriteRune('\\')
+		} else if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	if escaped {
+		b.WriteRune('\\')
+	}
+	return b.String()
+}
+
+func cooklangRestore(s string) string {
+	return strings.Map(func(r rune) rune {
+		if i := int(r - 0xE000); i >= 0 && i < len(cooklangEscapable) {
+			return rune(cooklangEscapable[i])
+		}
+		return r
+	}, s)
+}
+
+var (
+	cookIngredient = regexp.MustCompile(`@(?:([^@#~{}\n]+?)\{([^}]*)\}|([^\s@#~{}.,;:!?()]+))(?:\(([^)]*)\))?`)
+	cookCookware   = regexp.MustCompile(`#(?:([^@#~{}\n]+?)\{[^}]*\}|([^\s@#~{}.,;:!?()]+))`)
+	cookTimer      = regexp.MustCompile(`~([^@#~{}\n]*?)\{([^}]*)\}`)
+	blockComment   = regexp.MustCompile(`(?s)\[-.*?-\]`)
+)
+
+func (cooklangFormat) Decode(r io.Reader) (*Recipe, []string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := blockComment.ReplaceAllString(string(data), "")
+
+	recipe := &Recipe{}
+	var warnings []string
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		step := strings.Join(paragraph, " ")
+		paragraph = nil
+
+		var refs []Ingredient
+		plain := cookIngredient.ReplaceAllStringFunc(step, func(ref string) string {
+			m := cookIngredient.FindStringSubmatch(ref)
+			name := strings.TrimSpace(m[1] + m[3])
+			ing := Ingredient{Name: cooklangRestore(name), Note: cooklangRestore(strings.TrimSpace(m[4]))}
+			if w := readCooklangQuantity(&ing, m[2]); w != "" {
+				warnings = append(warnings, w)
+			}
+			ing.Unit = cooklangRestore(ing.Unit)
+			refs = append(refs, ing)
+			return name
+		})
+
+		// a paragraph of nothing but references is an ingredient list, and
+		// each of its lines is an ingredient of its own
+		if strings.Trim(cookIngredient.ReplaceAllString(step, ""), " ,;") == "" {
+			recipe.Ingredients = append(recipe.Ingredients, refs...)
+			return
+		}
+		recipe.Ingredients = mergeIngredients(recipe.Ingredients, refs)
+
+		plain = cookCookware.ReplaceAllStringFunc(plain, func(ref string) string {
+			m := cookCookware.FindStringSubmatch(ref)
+			return strings.TrimSpace(m[1] + m[2])
+		})
+		plain = cookTimer.ReplaceAllStringFunc(plain, func(ref string) string {
+			m := cookTimer.FindStringSubmatch(ref)
+			return strings.TrimSpace(strings.Replace(m[2], "%", " ", 1))
+		})
+		recipe.Instructions = append(recipe.Instructions, cooklangRestore(oneLine(plain)))
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	frontMatter := false
+	lineNo := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lineNo++
+
+		if line == "---" && (lineNo == 1 || frontMatter) {
+			frontMatter = !frontMatter
+			continue
+		}
+		if frontMatter || strings.HasPrefix(line, ">>") {
+			key, value, ok := strings.Cut(strings.TrimPrefix(line, ">>"), ":")
+			if ok {
+				if w := applyMetadata(recipe, strings.ToLower(strings.TrimSpace(key)), value); w != "" {
+					warnings = append(warnings, w)
+				}
+			}
+			continue
+		}
+
+		line = cooklangProtect(line)
+		if i := strings.Index(line, "--"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+			if line == "" {
+				continue
+			}
+		}
+		if line == "" || strings.HasPrefix(line, "=") {
+			flush()
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	flush()
+
+	if recipe.Name == "" && len(recipe.Ingredients) == 0 {
+		return nil, warnings, ErrNoRecipe
+	}
+	return recipe, append(warnings, applyDefaults(recipe)...), nil
+}
+
+func readCooklangQuantity(ing *Ingredient, quantity string) string {
+	amount, unit, _ := strings.Cut(quantity, "%")
+	ing.Unit = strings.TrimSpace(unit)
+
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return ""
+	}
+	if num, den, ok := strings.Cut(amount, "/"); ok {
+		n, err1 := strconv.ParseFloat(strings.TrimSpace(num), 64)
+		d, err2 := strconv.ParseFloat(strings.TrimSpace(den), 64)
+		if err1 == nil && err2 == nil && d != 0 {
+			ing.Amount = n / d
+			return ""
+		}
+	} else if n, err := strconv.ParseFloat(amount, 64); err == nil {
+		ing.Amount = n
+		return ""
+	}
+	return fmt.Sprintf("could not read amount %q for %s", amount, ing.Name)
+}
+
+// mergeIngredients adds the refs made in a step, summing repeated
+// references to the same ingredient and unit as Cooklang does.
+func mergeIngredients(list, refs []Ingredient) []Ingredient {
+	for _, ref := range refs {
+		merged := false
+		for i := range list {
+			if strings.EqualFold(list[i].Name, ref.Name) && list[i].Unit == ref.Unit {
+				list[i].Amount += ref.Amount
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			list = append(list, ref)
+		}
+	}
+	return list
+}
@@ -0,0 +1,120 @@
+package formats
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"json", "YAML", "yml", "md", "cook", "html"} {
+		if _, err := Lookup(name); err != nil {
+			t.Errorf("Lookup(%q) failed: %v", name, err)
+		}
+	}
+	if _, err := Lookup("pdf"); err == nil {
+		t.Error("expected unknown format error")
+	}
+	if got := Names(); !reflect.DeepEqual(got, []string{"cooklang", "html", "json", "markdown", "yaml"}) {
+		t.Errorf("unexpected names %v", got)
+	}
+}
+
+func TestCooklangDecode_InlineReferences(t *testing.T) {
+	doc := `---
+title: Pancakes
+servings: 4
+tags: [breakfast, sweet]
+---
+-- a family favourite
+Crack @eggs{3} into a #bowl{} and add @plain flour{125%g} and @milk{250%ml}(cold).
+
+[- resting helps -]
+Rest for ~{30%minutes}. Fry in a #frying pan{} with a knob of @butter{1/2%tbsp}, adding @salt to taste.
+Serve with @butter{1/2%tbsp}.
+`
+	recipe, warnings, err := cooklangFormat{}.Decode(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Ingredient{
+		{Name: "eggs", Amount: 3},
+		{Name: "plain flour", Amount: 125, Unit: "g"},
+		{Name: "milk", Amount: 250, Unit: "ml", Note: "cold"},
+		{Name: "butter", Amount: 1, Unit: "tbsp"},
+		{Name: "salt"},
+	}
+	if !reflect.DeepEqual(recipe.Ingredients, want) {
+		t.Errorf("unexpected ingredients %+v", recipe.Ingredients)
+	}
+	steps := []string{
+		"Crack eggs into a bowl and add plain flour and milk.",
+		"Rest for 30 minutes. Fry in a frying pan with a knob of butter, adding salt to taste. Serve with butter.",
+	}
+	if !reflect.DeepEqual(recipe.Instructions, steps) {
+		t.Errorf("unexpected steps %q", recipe.Instructions)
+	}
+	if recipe.Name != "Pancakes" || recipe.Servings != 4 || !reflect.DeepEqual(recipe.Tags, []string{"breakfast", "sweet"}) {
+		t.Errorf("unexpected metadata %+v", recipe)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "category") {
+		t.Errorf("expected only the category default warning, got %v", warnings)
+	}
+}
+
+func TestMarkdownDecode_HandWritten(t *testing.T) {
+	doc := `# Tomato Salad
+
+Summer in a bowl.
+
+- **Serves:** 2
+- **Total time:** 1 hour 15 min
+- **Author:** Sam
+
+## Ingredients
+
+- 3 ripe tomatoes, sliced
+- 2 tbsp olive oil
+
+## Method
+
+1. Slice the tomatoes.
+2. Dress with oil
+   and season.
+
+## Notes
+
+Best with good bread.
+`
+	recipe, warnings, err := markdownFormat{}.Decode(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipe.Name != "Tomato Salad" || recipe.Description != "Summer in a bowl." || recipe.Servings != 2 || recipe.CookTime != 75 {
+		t.Errorf("unexpected recipe %+v", recipe)
+	}
+	if !reflect.DeepEqual(recipe.IngredientLines, []string{"3 ripe tomatoes, sliced", "2 tbsp olive oil"}) {
+		t.Errorf("unexpected ingredient lines %q", recipe.IngredientLines)
+	}
+	if !reflect.DeepEqual(recipe.Instructions, []string{"Slice the tomatoes.", "Dress with oil\nand season."}) {
+		t.Errorf("unexpected steps %q", recipe.Instructions)
+	}
+	if len(warnings) != 4 {
+		t.Errorf("expected warnings for total time, author, notes and category, got %v", warnings)
+	}
+}
+
+func TestParseMinutes(t *testing.T) {
+	cases := map[string]int{
+		"15 minutes": 15, "15": 15, "1 hour 30 min": 90, "1h30m": 90, "2 hrs": 120, "PT45M": 45, "0 min": 0,
+	}
+	for in, want := range cases {
+		if got, ok := parseMinutes(in); !ok || got != want {
+			t.Errorf("parseMinutes(%q) = %d, %v; want %d", in, got, ok, want)
+		}
+	}
+	if _, ok := parseMinutes("a while"); ok {
+		t.Error("expected failure for text without numbers")
+	}
+}
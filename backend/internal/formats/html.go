@@ -0,0 +1,422 @@
+package formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func init() {
+	Register(Format{
+		Name:        "html",
+		Aliases:     []string{"htm"},
+		ContentType: "text/html",
+		Extension:   "html",
+		Decoder:     htmlFormat{},
+	})
+}
+
+type htmlFormat struct{}
+
+func (htmlFormat) Decode(r io.Reader) (*Recipe, []string, error) {
+	return DecodeHTML(r)
+}
+
+// DecodeHTML extracts a schema.org Recipe from a saved web page, preferring
+// JSON-LD and falling back to microdata. Warnings list what couldn't be mapped.
+func DecodeHTML(r io.Reader) (*Recipe, []string, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	for _, block := range jsonLDBlocks(doc) {
+		var value any
+		if err := json.Unmarshal([]byte(block), &value); err != nil {
+			warnings = append(warnings, "skipped a JSON-LD block that is not valid JSON")
+			continue
+		}
+		if obj := findRecipe(value); obj != nil {
+			recipe, more := mapRecipe(obj)
+			return recipe, append(warnings, more...), nil
+		}
+	}
+
+	if node := findMicrodataRecipe(doc); node != nil {
+		recipe, more := mapRecipe(microdataItem(node))
+		return recipe, append(warnings, more...), nil
+	}
+
+	return nil, warnings, ErrNoRecipe
+}
+
+func jsonLDBlocks(n *html.Node) []string {
+	var blocks []string
+	walk(n, func(n *html.Node) bool {
+		if n.DataAtom == atom.Script {
+			kind := strings.ToLower(strings.TrimSpace(attr(n, "type")))
+			if strings.HasPrefix(kind, "application/ld+json") {
+				var b strings.Builder
+				for c := n.FirstChild; c != nil; c = c.NextSibling {
+					b.WriteString(c.Data)
+				}
+				blocks = append(blocks, b.String())
+			}
+			return false
+		}
+		return true
+	})
+	return blocks
+}
+
+// findRecipe looks through arrays, @graph and mainEntity for a Recipe object.
+func findRecipe(value any) map[string]any {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if found := findRecipe(item); found != nil {
+				return found
+			}
+		}
+	case map[string]any:
+		if hasType(v, "Recipe") {
+			return v
+		}
+		for _, key := range []string{"@graph", "mainEntity", "mainEntityOfPage"} {
+			if found := findRecipe(v[key]); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+func hasType(obj map[string]any, want string) bool {
+	for _, t := range values(obj["@type"]) {
+		if s, ok := t.(string); ok && schemaName(s) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaName strips a vocabulary prefix: "http://schema.org/Recipe" and
+// "schema:Recipe" both become "Recipe".
+func schemaName(t string) string {
+	t = strings.TrimSpace(t)
+	if i := strings.LastIndexAny(t, "/:#"); i >= 0 {
+		t = t[i+1:]
+	}
+	return t
+}
+
+func findMicrodataRecipe(doc *html.Node) *html.Node {
+	var found *html.Node
+	walk(doc, func(n *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if hasAttr(n, "itemscope") {
+			for _, t := range strings.Fields(attr(n, "itemtype")) {
+				if schemaName(t) == "Recipe" {
+					found = n
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// microdataItem converts an itemscope element into the same shape JSON-LD
+// decodes to, so both go through mapRecipe.
+func microdataItem(n *html.Node) map[string]any {
+	item := map[string]any{}
+	if t := strings.Fields(attr(n, "itemtype")); len(t) > 0 {
+		item["@type"] = schemaName(t[0])
+	}
+
+	var collect func(*html.Node)
+	collect = func(parent *html.Node) {
+		for c := parent.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			scoped := hasAttr(c, "itemscope")
+			if props := strings.Fields(attr(c, "itemprop")); len(props) > 0 {
+				var value any
+				if scoped {
+					value = microdataItem(c)
+				} else {
+					value = propValue(c)
+				}
+				for _, p := range props {
+					item[p] = appendValue(item[p], value)
+				}
+			}
+			if !scoped {
+				collect(c)
+			}
+		}
+	}
+	collect(n)
+
+	return item
+}
+
+func appendValue(existing, value any) any {
+	switch v := existing.(type) {
+	case nil:
+		return value
+	case []any:
+		return append(v, value)
+	default:
+		return []any{v, value}
+	}
+}
+
+func propValue(n *html.Node) string {
+	if hasAttr(n, "content") {
+		return attr(n, "content")
+	}
+	if n.DataAtom == atom.Time && hasAttr(n, "datetime") {
+		return attr(n, "datetime")
+	}
+	return textContent(n)
+}
+
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Li: true, atom.Br: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+}
+
+// textContent renders an element's text, starting a new line at block elements.
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(c *html.Node) bool {
+		switch c.Type {
+		case html.TextNode:
+			b.WriteString(c.Data)
+		case html.ElementNode:
+			if c.DataAtom == atom.Script || c.DataAtom == atom.Style {
+				return false
+			}
+			if blockElements[c.DataAtom] {
+				b.WriteString("\n")
+			}
+		}
+		return true
+	})
+	return b.String()
+}
+
+func walk(n *html.Node, visit func(*html.Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, visit)
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func mapRecipe(obj map[string]any) (*Recipe, []string) {
+	recipe := &Recipe{}
+	var warnings []string
+
+	recipe.Name = inlineText(obj["name"])
+	recipe.Description = inlineText(obj["description"])
+
+	for _, y := range values(obj["recipeYield"]) {
+		if n, ok := y.(float64); ok && n >= 1 {
+			recipe.Servings = int(n)
+			break
+		}
+		if n, ok := parseYield(inlineText(y)); ok {
+			recipe.Servings = n
+			break
+		}
+	}
+
+	prep, prepOK := duration(obj, "prepTime", &warnings)
+	cook, cookOK := duration(obj, "cookTime", &warnings)
+	recipe.PrepTime, recipe.CookTime = prep, cook
+	if !prepOK && !cookOK {
+		if total, ok := duration(obj, "totalTime", &warnings); ok {
+			recipe.CookTime = total
+			warnings = append(warnings, "only totalTime was given; it was stored as cook time")
+		}
+	}
+
+	ingredients := obj["recipeIngredient"]
+	if ingredients == nil {
+		ingredients = obj["ingredients"]
+	}
+	for _, v := range values(ingredients) {
+		if _, ok := v.(map[string]any); ok {
+			warnings = append(warnings, "skipped a structured recipeIngredient entry")
+			continue
+		}
+		recipe.IngredientLines = append(recipe.IngredientLines, textLines(v)...)
+	}
+
+	sections := instructionSteps(obj["recipeInstructions"], recipe, &warnings)
+	if len(sections) > 0 {
+		warnings = append(warnings, fmt.Sprintf("instruction section headings were dropped: %s", strings.Join(sections, ", ")))
+	}
+	if len(recipe.Instructions) == 0 {
+		warnings = append(warnings, "no recipeInstructions found")
+	}
+
+	var categories []string
+	for _, key := range []string{"recipeCategory", "recipeCuisine"} {
+		for _, v := range values(obj[key]) {
+			for _, part := range strings.Split(inlineText(v), ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					categories = append(categories, part)
+				}
+			}
+		}
+	}
+	if len(categories) > 0 && len(values(obj["recipeCategory"])) > 0 {
+		recipe.Category = categories[0]
+		recipe.Tags = categories[1:]
+	} else {
+		recipe.Tags = categories
+	}
+
+	return recipe, append(warnings, applyDefaults(recipe)...)
+}
+
+func duration(obj map[string]any, key string, warnings *[]string) (int, bool) {
+	raw := inlineText(obj[key])
+	if raw == "" {
+		return 0, false
+	}
+	minutes, ok := ParseDuration(strings.ToUpper(raw))
+	if !ok {
+		*warnings = append(*warnings, fmt.Sprintf("could not read %s %q", key, raw))
+	}
+	return minutes, ok
+}
+
+// instructionSteps flattens strings, HowToStep, HowToSection and ItemList
+// values into recipe.Instructions and returns the section names it dropped.
+func instructionSteps(value any, recipe *Recipe, warnings *[]string) []string {
+	var sections []string
+	for _, v := range values(value) {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			recipe.Instructions = append(recipe.Instructions, textLines(v)...)
+			continue
+		}
+
+		switch {
+		case hasType(obj, "HowToSection"):
+			if name := inlineText(obj["name"]); name != "" {
+				sections = append(sections, name)
+			}
+			sections = append(sections, instructionSteps(obj["itemListElement"], recipe, warnings)...)
+		case hasType(obj, "ItemList"):
+			sections = append(sections, instructionSteps(obj["itemListElement"], recipe, warnings)...)
+		case hasType(obj, "ListItem"):
+			sections = append(sections, instructionSteps(obj["item"], recipe, warnings)...)
+		case hasType(obj, "HowToTip"):
+			// tips aren't steps
+		default:
+			text := textLines(obj["text"])
+			if len(text) == 0 {
+				text = textLines(obj["name"])
+			}
+			if len(text) == 0 && obj["itemListElement"] != nil {
+				sections = append(sections, instructionSteps(obj["itemListElement"], recipe, warnings)...)
+				continue
+			}
+			if len(text) == 0 {
+				*warnings = append(*warnings, "skipped an instruction step without text")
+				continue
+			}
+			recipe.Instructions = append(recipe.Instructions, strings.Join(text, " "))
+		}
+	}
+	return sections
+}
+
+func values(v any) []any {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return t
+	default:
+		return []any{t}
+	}
+}
+
+var tag = regexp.MustCompile(`<[^>]*>`)
+var blockTag = regexp.MustCompile(`(?i)<\s*(br|/p|/li|/div)\b[^>]*>`)
+
+// textLines turns a scalar value into trimmed, non-empty lines with any
+// embedded markup and entities removed.
+func textLines(v any) []string {
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = t
+	case float64:
+		s = strconv.FormatFloat(t, 'f', -1, 64)
+	case []any:
+		var lines []string
+		for _, item := range t {
+			lines = append(lines, textLines(item)...)
+		}
+		return lines
+	case map[string]any:
+		return textLines(t["@value"])
+	default:
+		return nil
+	}
+
+	s = blockTag.ReplaceAllString(s, "\n")
+	s = html.UnescapeString(tag.ReplaceAllString(s, ""))
+
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func inlineText(v any) string {
+	if list, ok := v.([]any); ok && len(list) > 0 {
+		v = list[0]
+	}
+	return strings.Join(textLines(v), " ")
+}
@@ -0,0 +1,118 @@
+package formats
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func decodeFile(t *testing.T, name string) (*Recipe, []string) {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	recipe, warnings, err := DecodeHTML(f)
+	if err != nil {
+		t.Fatalf("DecodeHTML(%s) returned %v", name, err)
+	}
+	return recipe, warnings
+}
+
+func TestDecodeHTML_JSONLD(t *testing.T) {
+	recipe, warnings := decodeFile(t, "jsonld.html")
+
+	if recipe.Name != "Best Lentil Soup" || recipe.Description != "Hearty & warming." {
+		t.Errorf("unexpected name/description %q %q", recipe.Name, recipe.Description)
+	}
+	if recipe.Servings != 6 || recipe.PrepTime != 15 || recipe.CookTime != 60 {
+		t.Errorf("unexpected servings/times %d %d %d", recipe.Servings, recipe.PrepTime, recipe.CookTime)
+	}
+	if recipe.Category != "Dinner" || !reflect.DeepEqual(recipe.Tags, []string{"Soup", "Indian"}) {
+		t.Errorf("unexpected category/tags %q %v", recipe.Category, recipe.Tags)
+	}
+	if len(recipe.IngredientLines) != 4 || recipe.IngredientLines[0] != "1 cup red lentils, rinsed" {
+		t.Errorf("unexpected ingredients %v", recipe.IngredientLines)
+	}
+
+	want := []string{"Rinse the lentils.", "Dice the onion.", "Fry the onion in oil.", "Simmer everything for 45 minutes."}
+	if !reflect.DeepEqual(recipe.Instructions, want) {
+		t.Errorf("unexpected instructions %v", recipe.Instructions)
+	}
+
+	joined := strings.Join(warnings, "; ")
+	if !strings.Contains(joined, "not valid JSON") || !strings.Contains(joined, "Prep, Cook") {
+		t.Errorf("expected warnings for the broken block and dropped sections, got %v", warnings)
+	}
+}
+
+func TestDecodeHTML_Microdata(t *testing.T) {
+	recipe, warnings := decodeFile(t, "microdata.html")
+
+	if recipe.Name != "Simple Pancakes" || recipe.Servings != 8 || recipe.Category != "Breakfast" {
+		t.Errorf("unexpected recipe %+v", recipe)
+	}
+	if recipe.PrepTime != 10 || recipe.CookTime != 20 {
+		t.Errorf("unexpected times %d %d", recipe.PrepTime, recipe.CookTime)
+	}
+	if !reflect.DeepEqual(recipe.IngredientLines, []string{"1 ½ cups flour", "2 eggs", "1 cup milk"}) {
+		t.Errorf("unexpected ingredients %q", recipe.IngredientLines)
+	}
+	if !reflect.DeepEqual(recipe.Instructions, []string{"Whisk everything together.", "Cook on a hot griddle."}) {
+		t.Errorf("unexpected instructions %q", recipe.Instructions)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestDecodeHTML_Fallbacks(t *testing.T) {
+	page := `<script type="application/ld+json">
+	{"@type": "http://schema.org/Recipe", "recipeIngredient": "2 eggs",
+	 "totalTime": "PT45M", "prepTime": "soon", "recipeInstructions": "Mix.\nBake."}
+	</script>`
+
+	recipe, warnings, err := DecodeHTML(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipe.Name != "Untitled recipe" || recipe.Servings != 1 || recipe.Category != "General" {
+		t.Errorf("expected defaults, got %+v", recipe)
+	}
+	if recipe.PrepTime != 0 || recipe.CookTime != 45 {
+		t.Errorf("expected totalTime as cook time, got %d %d", recipe.PrepTime, recipe.CookTime)
+	}
+	if len(recipe.Instructions) != 2 {
+		t.Errorf("expected instructions split by line, got %q", recipe.Instructions)
+	}
+	if len(warnings) != 5 {
+		t.Errorf("expected 5 warnings, got %q", warnings)
+	}
+}
+
+func TestDecodeHTML_NoRecipe(t *testing.T) {
+	_, _, err := DecodeHTML(strings.NewReader(`<html><body><p>Just a blog post.</p></body></html>`))
+	if err != ErrNoRecipe {
+		t.Fatalf("expected ErrNoRecipe, got %v", err)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]int{
+		"PT15M": 15, "PT1H": 60, "PT1H30M": 90, "P0DT2H": 120, "P1D": 1440,
+		"PT90S": 2, "PT0.5H": 30, "PT1H0M30S": 61,
+	}
+	for in, want := range cases {
+		if got, ok := ParseDuration(in); !ok || got != want {
+			t.Errorf("ParseDuration(%q) = %d, %v; want %d", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "P", "PT", "15 minutes", "1H"} {
+		if _, ok := ParseDuration(in); ok {
+			t.Errorf("ParseDuration(%q) should fail", in)
+		}
+	}
+}
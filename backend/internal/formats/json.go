@@ -0,0 +1,32 @@
+package formats
+
+import (
+	"encoding/json"
+	"io"
+)
+
+func init() {
+	Register(Format{
+		Name:        "json",
+		ContentType: "application/json",
+		Extension:   "json",
+		Encoder:     jsonFormat{},
+		Decoder:     jsonFormat{},
+	})
+}
+
+type jsonFormat struct{}
+
+func (jsonFormat) Encode(w io.Writer, recipe *Recipe) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(recipe)
+}
+
+func (jsonFormat) Decode(r io.Reader) (*Recipe, []string, error) {
+	var recipe Recipe
+	if err := json.NewDecoder(r).Decode(&recipe); err != nil {
+		return nil, nil, err
+	}
+	return &recipe, applyDefaults(&recipe), nil
+}
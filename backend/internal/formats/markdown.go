@@ -0,0 +1,283 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register(Format{
+		Name:        "markdown",
+		Aliases:     []string{"md"},
+		ContentType: "text/markdown; charset=utf-8",
+		Extension:   "md",
+		Encoder:     markdownFormat{},
+		Decoder:     markdownFormat{},
+	})
+}
+
+// markdownFormat writes a title, a metadata list, an ingredient table and a
+// numbered list of steps. The table keeps amounts and units exact; plain
+// bullet lists of ingredients are also read, through the ingredient parser.
+type markdownFormat struct{}
+
+func (markdownFormat) Encode(w io.Writer, recipe *Recipe) error {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "# %s\n\n", oneLine(recipe.Name))
+	if d := strings.TrimSpace(recipe.Description); d != "" {
+		fmt.Fprintf(b, "%s\n\n", d)
+	}
+
+	fmt.Fprintf(b, "- **Servings:** %d\n", recipe.Servings)
+	fmt.Fprintf(b, "- **Category:** %s\n", oneLine(recipe.Category))
+	fmt.Fprintf(b, "- **Prep time:** %d min\n", recipe.PrepTime)
+	fmt.Fprintf(b, "- **Cook time:** %d min\n", recipe.CookTime)
+	if len(recipe.Tags) > 0 {
+		fmt.Fprintf(b, "- **Tags:** %s\n", strings.Join(recipe.Tags, ", "))
+	}
+
+	b.WriteString("\n## Ingredients\n\n")
+	b.WriteString("| Amount | Unit | Ingredient | Note |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, ing := range recipe.Ingredients {
+		amount := ""
+		if ing.Amount != 0 {
+			amount = formatAmount(ing.Amount)
+		}
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n", amount, tableCell(ing.Unit), tableCell(ing.Name), tableCell(ing.Note))
+	}
+
+	b.WriteString("\n## Instructions\n\n")
+	for i, step := range recipe.Instructions {
+		lines := strings.Split(strings.TrimSpace(step), "\n")
+		fmt.Fprintf(b, "%d. %s\n", i+1, lines[0])
+		for _, line := range lines[1:] {
+			fmt.Fprintf(b, "   %s\n", line)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var (
+	metadataLine = regexp.MustCompile(`^[-*]\s+\*\*(.+?):\*\*\s*(.*)$`)
+	numberedItem = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	bulletItem   = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	tableRule    = regexp.MustCompile(`^\|?\s*:?-{3,}`)
+)
+
+func (markdownFormat) Decode(r io.Reader) (*Recipe, []string, error) {
+	recipe := &Recipe{}
+	var warnings []string
+	var description []string
+	var columns map[string]int
+
+	section := ""
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		raw := strings.TrimRight(scanner.Text(), " \t\r")
+		line := strings.TrimSpace(raw)
+
+		switch {
+		case strings.HasPrefix(line, "# ") && recipe.Name == "" && section == "":
+			recipe.Name = strings.TrimSpace(line[2:])
+			continue
+		case strings.HasPrefix(line, "## "):
+			title := strings.ToLower(strings.TrimSpace(line[3:]))
+			switch {
+			case strings.Contains(title, "ingredient"):
+				section = "ingredients"
+			case strings.Contains(title, "instruction"), strings.Contains(title, "method"),
+				strings.Contains(title, "step"), strings.Contains(title, "direction"):
+				section = "instructions"
+			default:
+				section = "other"
+				warnings = append(warnings, fmt.Sprintf("ignored section %q", strings.TrimSpace(line[3:])))
+			}
+			continue
+		}
+
+		switch section {
+		case "":
+			if m := metadataLine.FindStringSubmatch(line); m != nil {
+				if w := applyMetadata(recipe, strings.ToLower(m[1]), m[2]); w != "" {
+					warnings = append(warnings, w)
+				}
+				continue
+			}
+			description = append(description, raw)
+
+		case "ingredients":
+			switch {
+			case strings.HasPrefix(line, "|"):
+				cells := tableCells(line)
+				if tableRule.MatchString(line) {
+					continue
+				}
+				if columns == nil {
+					columns = tableColumns(cells)
+					continue
+				}
+				ing, w := tableIngredient(cells, columns)
+				if w != "" {
+					warnings = append(warnings, w)
+					continue
+				}
+				recipe.Ingredients = append(recipe.Ingredients, ing)
+			case bulletItem.MatchString(line):
+				recipe.IngredientLines = append(recipe.IngredientLines, bulletItem.FindStringSubmatch(line)[1])
+			}
+
+		case "instructions":
+			if line == "" {
+				continue
+			}
+			if m := numberedItem.FindStringSubmatch(line); m != nil {
+				recipe.Instructions = append(recipe.Instructions, m[1])
+			} else if m := bulletItem.FindStringSubmatch(line); m != nil {
+				recipe.Instructions = append(recipe.Instructions, m[1])
+			} else if n := len(recipe.Instructions); n > 0 && raw != line {
+				recipe.Instructions[n-1] += "\n" + line
+			} else {
+				recipe.Instructions = append(recipe.Instructions, line)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if recipe.Name == "" && len(recipe.Ingredients) == 0 && len(recipe.IngredientLines) == 0 {
+		return nil, warnings, ErrNoRecipe
+	}
+
+	recipe.Description = strings.TrimSpace(strings.Join(description, "\n"))
+	return recipe, append(warnings, applyDefaults(recipe)...), nil
+}
+
+// applyMetadata sets one "key: value" field shared by the Markdown and
+// Cooklang formats. It returns a warning when the field isn't understood.
+func applyMetadata(recipe *Recipe, key, value string) string {
+	value = strings.TrimSpace(value)
+	switch key {
+	case "title", "name":
+		recipe.Name = value
+	case "description", "introduction":
+		recipe.Description = value
+	case "servings", "serves", "yield":
+		n, ok := parseYield(value)
+		if !ok {
+			return fmt.Sprintf("could not read %s %q", key, value)
+		}
+		recipe.Servings = n
+	case "category", "course":
+		recipe.Category = value
+	case "tags":
+		value = strings.Trim(value, "[]")
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.Trim(strings.TrimSpace(t), `"'`); t != "" {
+				recipe.Tags = append(recipe.Tags, t)
+			}
+		}
+	case "prep time", "cook time", "total time", "time":
+		minutes, ok := parseMinutes(value)
+		if !ok {
+			return fmt.Sprintf("could not read %s %q", key, value)
+		}
+		switch key {
+		case "prep time":
+			recipe.PrepTime = minutes
+		case "cook time":
+			recipe.CookTime = minutes
+		default:
+			if recipe.PrepTime != 0 || recipe.CookTime != 0 {
+				return ""
+			}
+			recipe.CookTime = minutes
+			return fmt.Sprintf("only %s was given; it was stored as cook time", key)
+		}
+	default:
+		return fmt.Sprintf("ignored metadata %q", key)
+	}
+	return ""
+}
+
+func tableColumns(header []string) map[string]int {
+	columns := map[string]int{}
+	for i, h := range header {
+		switch strings.ToLower(h) {
+		case "amount", "quantity", "qty":
+			columns["amount"] = i
+		case "unit":
+			columns["unit"] = i
+		case "ingredient", "name", "item":
+			columns["name"] = i
+		case "note", "notes":
+			columns["note"] = i
+		}
+	}
+	return columns
+}
+
+func tableIngredient(cells []string, columns map[string]int) (Ingredient, string) {
+	cell := func(key string) string {
+		if i, ok := columns[key]; ok && i < len(cells) {
+			return cells[i]
+		}
+		return ""
+	}
+
+	ing := Ingredient{Name: cell("name"), Unit: cell("unit"), Note: cell("note")}
+	if ing.Name == "" {
+		return ing, "skipped an ingredient row without a name"
+	}
+	if amount := cell("amount"); amount != "" {
+		n, err := strconv.ParseFloat(amount, 64)
+		if err != nil {
+			return ing, fmt.Sprintf("skipped ingredient %q: could not read amount %q", ing.Name, amount)
+		}
+		ing.Amount = n
+	}
+	return ing, ""
+}
+
+// tableCells splits a table row on unescaped pipes.
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var current strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			current.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, strings.TrimSpace(current.String()))
+			current.Reset()
+			continue
+		}
+		current.WriteByte(line[i])
+	}
+	return append(cells, strings.TrimSpace(current.String()))
+}
+
+func tableCell(s string) string {
+	return strings.ReplaceAll(oneLine(s), "|", `\|`)
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
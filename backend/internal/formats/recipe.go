@@ -0,0 +1,183 @@
+package formats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrNoRecipe = errors.New("no recipe found in document")
+var ErrUnknownFormat = errors.New("unknown recipe format")
+
+// Recipe is the format-neutral shape that every format encodes from and
+// decodes into. Its JSON and YAML tags are the on-disk layout of those formats.
+type Recipe struct {
+	Name         string       `json:"name" yaml:"name"`
+	Description  string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Servings     int          `json:"servings" yaml:"servings"`
+	PrepTime     int          `json:"prep_time" yaml:"prep_time"` // minutes
+	CookTime     int          `json:"cook_time" yaml:"cook_time"` // minutes
+	Category     string       `json:"category" yaml:"category"`
+	Tags         []string     `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Ingredients  []Ingredient `json:"ingredients" yaml:"ingredients"`
+	Instructions []string     `json:"instructions" yaml:"instructions"`
+
+	// IngredientLines holds free-text lines from documents that don't
+	// structure their ingredients; importers run them through the parser.
+	IngredientLines []string `json:"-" yaml:"-"`
+}
+
+type Ingredient struct {
+	Name   string  `json:"name" yaml:"name"`
+	Amount float64 `json:"amount" yaml:"amount"`
+	Unit   string  `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Note   string  `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+// Encoder writes a recipe in one format.
+type Encoder interface {
+	Encode(w io.Writer, recipe *Recipe) error
+}
+
+// Decoder reads a recipe, returning warnings for anything it couldn't map.
+type Decoder interface {
+	Decode(r io.Reader) (*Recipe, []string, error)
+}
+
+// Format is a registered interchange format. Encoder or Decoder is nil for
+// one-way formats such as HTML.
+type Format struct {
+	Name        string
+	Aliases     []string
+	ContentType string
+	Extension   string
+	Encoder     Encoder
+	Decoder     Decoder
+}
+
+var registry = map[string]Format{}
+
+// Register adds a format under its name and aliases, replacing any previous one.
+func Register(f Format) {
+	registry[f.Name] = f
+	for _, alias := range f.Aliases {
+		registry[alias] = f
+	}
+}
+
+// Lookup finds a format by name or alias.
+func Lookup(name string) (Format, error) {
+	f, ok := registry[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Format{}, fmt.Errorf("%w %q", ErrUnknownFormat, name)
+	}
+	return f, nil
+}
+
+// Names lists the registered format names, without aliases.
+func Names() []string {
+	var names []string
+	for key, f := range registry {
+		if key == f.Name {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyDefaults fills the fields a recipe can't be saved without.
+func applyDefaults(recipe *Recipe) []string {
+	var warnings []string
+	if strings.TrimSpace(recipe.Name) == "" {
+		recipe.Name = "Untitled recipe"
+		warnings = append(warnings, "recipe has no name; using \"Untitled recipe\"")
+	}
+	if recipe.Servings <= 0 {
+		recipe.Servings = 1
+		warnings = append(warnings, "recipe has no servings; using 1")
+	}
+	if strings.TrimSpace(recipe.Category) == "" {
+		recipe.Category = "General"
+		warnings = append(warnings, "recipe has no category; using \"General\"")
+	}
+	return warnings
+}
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// ParseDuration converts an ISO-8601 duration such as "PT1H30M" into whole
+// minutes, rounding seconds to the nearest minute.
+func ParseDuration(value string) (int, bool) {
+	m := isoDuration.FindStringSubmatch(value)
+	if m == nil || value == "P" || value == "PT" {
+		return 0, false
+	}
+
+	minutes := 0.0
+	for i, scale := range []float64{24 * 60, 60, 1, 1.0 / 60} {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(m[i+1], 64)
+		if err != nil {
+			return 0, false
+		}
+		minutes += n * scale
+	}
+
+	return int(math.Round(minutes)), true
+}
+
+var timePart = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(days?|d|hours?|hrs?|h|minutes?|mins?|m)?`)
+
+// parseMinutes reads human durations such as "15 minutes", "1 hour 30 min"
+// or "1h30m". A bare number is taken as minutes.
+func parseMinutes(value string) (int, bool) {
+	value = strings.TrimSpace(value)
+	if minutes, ok := ParseDuration(strings.ToUpper(value)); ok {
+		return minutes, true
+	}
+
+	parts := timePart.FindAllStringSubmatch(value, -1)
+	if len(parts) == 0 {
+		return 0, false
+	}
+
+	minutes := 0.0
+	for _, p := range parts {
+		n, err := strconv.ParseFloat(p[1], 64)
+		if err != nil {
+			return 0, false
+		}
+		switch unit := strings.ToLower(p[2]); {
+		case strings.HasPrefix(unit, "d"):
+			minutes += n * 24 * 60
+		case strings.HasPrefix(unit, "h"):
+			minutes += n * 60
+		default:
+			minutes += n
+		}
+	}
+	return int(math.Round(minutes)), true
+}
+
+var firstNumber = regexp.MustCompile(`\d+`)
+
+// parseYield reads servings from yields such as "4", "Serves 4-6" or "12 cookies".
+func parseYield(value string) (int, bool) {
+	n, err := strconv.Atoi(firstNumber.FindString(value))
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
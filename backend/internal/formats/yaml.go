@@ -0,0 +1,42 @@
+package formats
+
+import (
+	"io"
+
+	"github.com/goccy/go-yaml"
+)
+
+func init() {
+	Register(Format{
+		Name:        "yaml",
+		Aliases:     []string{"yml"},
+		ContentType: "application/yaml",
+		Extension:   "yaml",
+		Encoder:     yamlFormat{},
+		Decoder:     yamlFormat{},
+	})
+}
+
+type yamlFormat struct{}
+
+func (yamlFormat) Encode(w io.Writer, recipe *Recipe) error {
+	data, err := yaml.Marshal(recipe)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (yamlFormat) Decode(r io.Reader) (*Recipe, []string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var recipe Recipe
+	if err := yaml.Unmarshal(data, &recipe); err != nil {
+		return nil, nil, err
+	}
+	return &recipe, applyDefaults(&recipe), nil
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/archive"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const maxArchiveSize = 100 << 20
+
+type AccountHandler struct {
+	Service services.AccountService
+}
+
+func NewAccountHandler(service services.AccountService) *AccountHandler {
+	return &AccountHandler{Service: service}
+}
+
+func (h *AccountHandler) ExportArchive(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var buf bytes.Buffer
+	if err := h.Service.ExportArchive(userID, &buf); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := "mealmate-export-" + time.Now().Format("2006-01-02") + ".zip"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// ImportArchive accepts the zip as a multipart "file" upload or as the raw body.
+func (h *AccountHandler) ImportArchive(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var query dto.AccountImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxArchiveSize)
+
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "archive is too large"})
+		return
+	}
+
+	resp, err := h.Service.ImportArchive(userID, bytes.NewReader(data), int64(len(data)), query)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImportConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflicts": resp.Conflicts})
+		case errors.Is(err, archive.ErrInvalidArchive), errors.Is(err, archive.ErrUnsupportedVersion),
+			errors.Is(err, services.ErrInvalidConflictPolicy):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CookLogHandler struct {
+	Service services.CookLogService
+}
+
+func NewCookLogHandler(service services.CookLogService) *CookLogHandler {
+	return &CookLogHandler{Service: service}
+}
+
+// bindCookRequest reads the optional body of a "cooked" request.
+func bindCookRequest(c *gin.Context) (dto.CookRequest, bool) {
+	var req dto.CookRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+	return req, true
+}
+
+func cookLogError(c *gin.Context, err error, notFound string) {
+	switch {
+	case err == services.ErrInvalidCookDate, err == services.ErrInvalidServings:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err == services.ErrAlreadyCooked:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err == services.ErrUnauthorized:
+		c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *CookLogHandler) CookMealPlan(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	mealPlanID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid meal plan id"})
+		return
+	}
+
+	req, ok := bindCookRequest(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.Service.CookMealPlan(uint(mealPlanID), userID, req)
+	if err != nil {
+		cookLogError(c, err, "meal plan not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
+
+// CookRecipe takes an optional ?servings= when the recipe was scaled.
+func (h *CookLogHandler) CookRecipe(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	recipeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe id"})
+		return
+	}
+	servings := 0
+	if s := c.Query("servings"); s != "" {
+		if servings, err = strconv.Atoi(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid servings"})
+			return
+		}
+	}
+
+	req, ok := bindCookRequest(c)
+	if !ok {
+		return
+	}
+
+	event, err := h.Service.CookRecipe(uint(recipeID), userID, servings, req)
+	if err != nil {
+		cookLogError(c, err, "recipe not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, event)
+}
+
+func (h *CookLogHandler) RecipeHistory(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	recipeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe id"})
+		return
+	}
+
+	history, err := h.Service.RecipeHistory(uint(recipeID), userID)
+	if err != nil {
+		cookLogError(c, err, "recipe not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *CookLogHandler) GetCookLog(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	events, err := h.Service.GetCookLog(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func (h *CookLogHandler) CookStats(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	stats, err := h.Service.CookStats(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *CookLogHandler) DeleteEvent(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cook event id"})
+		return
+	}
+
+	if err := h.Service.DeleteEvent(uint(eventID), userID); err != nil {
+		cookLogError(c, err, "cook event not found")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,12 +1,19 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/aisle"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/diet"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/nutrition"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type IngredientHandler struct {
@@ -25,6 +32,14 @@ func (h *IngredientHandler) CreateIngredient(c *gin.Context) {
 	}
 
 	if err := h.Service.CreateIngredient(req); err != nil {
+		if isLabelError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err == services.ErrIngredientExists {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -41,6 +56,205 @@ func (h *IngredientHandler) GetIngredients(c *gin.Context) {
 	c.JSON(http.StatusOK, items)
 }
 
+func (h *IngredientHandler) SearchIngredients(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var query dto.IngredientSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.Service.SearchIngredients(userID, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+func (h *IngredientHandler) UpdateIngredient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ingredient id"})
+		return
+	}
+
+	var req dto.UpdateIngredientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.UpdateIngredient(uint(id), req); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "ingredient not found"})
+			return
+		}
+		if isLabelError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err == services.ErrIngredientExists {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *IngredientHandler) AddAlias(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ingredient id"})
+		return
+	}
+
+	var req dto.AliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	alias, err := h.Service.AddAlias(uint(id), req)
+	if err != nil {
+		switch {
+		case err == services.ErrAliasTaken:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "ingredient not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, alias)
+}
+
+func (h *IngredientHandler) DeleteAlias(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alias id"})
+		return
+	}
+
+	if err := h.Service.DeleteAlias(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "alias not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *IngredientHandler) MergeIngredients(c *gin.Context) {
+	var req dto.MergeIngredientsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.Service.MergeIngredients(req)
+	if err != nil {
+		switch {
+		case err == services.ErrInvalidMerge:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "ingredient not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *IngredientHandler) SuggestDuplicates(c *gin.Context) {
+	var query dto.DuplicateQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suggestions, err := h.Service.SuggestDuplicates(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// isLabelError reports an allergen, diet or aisle name that could not be
+// understood.
+func isLabelError(err error) bool {
+	return errors.Is(err, diet.ErrInvalidAllergen) || errors.Is(err, diet.ErrUnknownDiet) ||
+		errors.Is(err, aisle.ErrUnknownAisle)
+}
+
+func (h *IngredientHandler) ParseIngredients(c *gin.Context) {
+	var req dto.ParseIngredientsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.Service.ParseIngredients(req))
+}
+
+const maxNutritionCSVSize = 20 << 20
+
+// ImportNutrition accepts a CSV as a multipart "file" upload or as the raw body.
+func (h *IngredientHandler) ImportNutrition(c *gin.Context) {
+	var query dto.NutritionImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxNutritionCSVSize)
+
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	resp, err := h.Service.ImportNutrition(body, query)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		case errors.Is(err, nutrition.ErrInvalidCSV):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *IngredientHandler) AddIngredientToRecipe(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
 	recipeID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
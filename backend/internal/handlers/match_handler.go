@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type MatchHandler struct {
+	Service services.MatchService
+}
+
+func NewMatchHandler(service services.MatchService) *MatchHandler {
+	return &MatchHandler{Service: service}
+}
+
+func (h *MatchHandler) MatchRecipes(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req dto.MatchRecipesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.Service.MatchRecipes(userID, req)
+	if err != nil {
+		if err == services.ErrNoIngredientsGiven {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -26,16 +27,21 @@ func (h *MealPlanHandler) Create(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.Create(userID, req); err != nil {
+	warnings, err := h.Service.Create(userID, req)
+	if err != nil {
 		if err == services.ErrMealExists {
 			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 			return
 		}
+		if errors.Is(err, services.ErrMealExcluded) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusCreated, dto.CreateMealPlanResponse{Warnings: warnings})
 }
 
 func (h *MealPlanHandler) GetByDate(c *gin.Context) {
@@ -74,12 +80,17 @@ func (h *MealPlanHandler) Update(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.Update(uint(id), userID, req); err != nil {
+	warnings, err := h.Service.Update(uint(id), userID, req)
+	if err != nil {
+		if errors.Is(err, services.ErrMealExcluded) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.Status(http.StatusOK)
+	c.JSON(http.StatusOK, dto.CreateMealPlanResponse{Warnings: warnings})
 }
 
 func (h *MealPlanHandler) Delete(c *gin.Context) {
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type NutritionHandler struct {
+	Service services.NutritionService
+}
+
+func NewNutritionHandler(service services.NutritionService) *NutritionHandler {
+	return &NutritionHandler{Service: service}
+}
+
+func (h *NutritionHandler) RecipeNutrition(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	recipeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe id"})
+		return
+	}
+
+	resp, err := h.Service.RecipeNutrition(uint(recipeID), userID)
+	if err != nil {
+		switch {
+		case err == services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "recipe not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *NutritionHandler) MealPlanNutrition(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date are required"})
+		return
+	}
+
+	resp, err := h.Service.MealPlanNutrition(userID, startDate, endDate)
+	if err != nil {
+		var parseErr *time.ParseError
+		switch {
+		case errors.As(err, &parseErr), err == services.ErrInvalidDateRange:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
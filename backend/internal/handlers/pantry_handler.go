@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PantryHandler struct {
+	Service services.PantryService
+}
+
+func NewPantryHandler(service services.PantryService) *PantryHandler {
+	return &PantryHandler{Service: service}
+}
+
+func (h *PantryHandler) AddItem(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req dto.CreatePantryItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.Service.AddItem(userID, req)
+	if err != nil {
+		switch {
+		case err == services.ErrInvalidExpiryDate:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "ingredient not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *PantryHandler) GetItems(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	items, err := h.Service.GetItems(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *PantryHandler) UpdateItem(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pantry item id"})
+		return
+	}
+
+	var req dto.UpdatePantryItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.Service.UpdateItem(uint(itemID), userID, req)
+	if err != nil {
+		switch {
+		case err == services.ErrInvalidExpiryDate:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err == services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "pantry item not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *PantryHandler) DeleteItem(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pantry item id"})
+		return
+	}
+
+	if err := h.Service.DeleteItem(uint(itemID), userID); err != nil {
+		switch {
+		case err == services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "pantry item not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/images"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// room for the multipart envelope around the largest accepted image
+const maxPhotoRequestSize = images.MaxSize + 1<<20
+
+type PhotoHandler struct {
+	Service services.PhotoService
+}
+
+func NewPhotoHandler(service services.PhotoService) *PhotoHandler {
+	return &PhotoHandler{Service: service}
+}
+
+// UploadPhoto stores the multipart "photo" file as the recipe's cover, or as
+// a step photo when the route has a :step.
+func (h *PhotoHandler) UploadPhoto(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	recipeID, step, ok := photoTarget(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxPhotoRequestSize)
+	file, err := c.FormFile("photo")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": images.ErrTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "photo is required"})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	photo, err := h.Service.SetPhoto(recipeID, step, userID, f)
+	if err != nil {
+		writePhotoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, photo)
+}
+
+func (h *PhotoHandler) DeletePhoto(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	recipeID, step, ok := photoTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeletePhoto(recipeID, step, userID); err != nil {
+		writePhotoError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "photo deleted"})
+}
+
+// photoTarget reads :id and the optional :step, answering 400 when invalid.
+func photoTarget(c *gin.Context) (uint, int, bool) {
+	recipeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe id"})
+		return 0, 0, false
+	}
+
+	step := 0
+	if param := c.Param("step"); param != "" {
+		step, err = strconv.Atoi(param)
+		if err != nil || step < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid step number"})
+			return 0, 0, false
+		}
+	}
+
+	return uint(recipeID), step, true
+}
+
+func writePhotoError(c *gin.Context, err error) {
+	switch {
+	case err == services.ErrUnauthorized:
+		c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "recipe not found"})
+	case err == services.ErrStepNotFound, err == services.ErrPhotoNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err == images.ErrTooLarge:
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case err == images.ErrUnsupportedType, err == images.ErrTooManyPixels, errors.Is(err, images.ErrInvalidImage):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
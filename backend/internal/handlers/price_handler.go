@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PriceHandler struct {
+	Service     services.PriceService
+	CostService services.CostService
+}
+
+func NewPriceHandler(service services.PriceService, costService services.CostService) *PriceHandler {
+	return &PriceHandler{Service: service, CostService: costService}
+}
+
+func (h *PriceHandler) AddPrice(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req dto.CreatePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	price, err := h.Service.AddPrice(userID, req)
+	if err != nil {
+		switch {
+		case err == services.ErrInvalidPriceDate:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "ingredient not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, price)
+}
+
+func (h *PriceHandler) GetPrices(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var query dto.PriceListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prices, err := h.Service.GetPrices(userID, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prices)
+}
+
+func (h *PriceHandler) DeletePrice(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	priceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid price id"})
+		return
+	}
+
+	if err := h.Service.DeletePrice(uint(priceID), userID); err != nil {
+		switch {
+		case err == services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "price not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RecipeCost takes an optional ?servings= to price the recipe scaled.
+func (h *PriceHandler) RecipeCost(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	recipeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe id"})
+		return
+	}
+	servings := 0
+	if s := c.Query("servings"); s != "" {
+		if servings, err = strconv.Atoi(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid servings"})
+			return
+		}
+	}
+
+	resp, err := h.CostService.RecipeCost(uint(recipeID), userID, servings)
+	if err != nil {
+		switch {
+		case err == services.ErrInvalidServings:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err == services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "recipe not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PriceHandler) MealPlanCost(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	if startDate == "" || endDate == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date are required"})
+		return
+	}
+
+	resp, err := h.CostService.MealPlanCost(userID, startDate, endDate)
+	if err != nil {
+		var parseErr *time.ParseError
+		switch {
+		case errors.As(err, &parseErr), err == services.ErrInvalidDateRange:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/diet"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ProfileHandler struct {
+	Service services.ProfileService
+}
+
+func NewProfileHandler(service services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{Service: service}
+}
+
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	profile, err := h.Service.GetProfile(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *ProfileHandler) SetTargets(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req dto.NutritionFacts
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.Service.SetTargets(userID, req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *ProfileHandler) SetExclusions(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req dto.Exclusions
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.Service.SetExclusions(userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, diet.ErrInvalidAllergen), errors.Is(err, diet.ErrUnknownDiet):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
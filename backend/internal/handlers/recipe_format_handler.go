@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/formats"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxImportSize = 5 << 20
+
+type RecipeFormatHandler struct {
+	Service services.RecipeFormatService
+}
+
+func NewRecipeFormatHandler(service services.RecipeFormatService) *RecipeFormatHandler {
+	return &RecipeFormatHandler{Service: service}
+}
+
+// ImportRecipe accepts a document as a multipart "file" upload or as the raw
+// body. Without ?format= an upload's extension picks the format, else HTML.
+func (h *RecipeFormatHandler) ImportRecipe(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	format := c.Query("format")
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		body = f
+
+		if format == "" {
+			format = strings.TrimPrefix(filepath.Ext(file.Filename), ".")
+		}
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	resp, err := h.Service.ImportRecipe(userID, format, body, dryRun)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "document is too large"})
+		case errors.Is(err, formats.ErrUnknownFormat), errors.Is(err, services.ErrFormatNotSupported),
+			errors.Is(err, services.ErrInvalidDocument), errors.Is(err, formats.ErrNoRecipe),
+			errors.Is(err, services.ErrImportNoIngredients):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *RecipeFormatHandler) ExportRecipe(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	recipeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipe id"})
+		return
+	}
+
+	export, err := h.Service.ExportRecipe(uint(recipeID), userID, c.DefaultQuery("format", "json"))
+	if err != nil {
+		switch {
+		case err == services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+		case errors.Is(err, formats.ErrUnknownFormat), errors.Is(err, services.ErrFormatNotSupported):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "recipe not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+export.Filename+`"`)
+	c.Data(http.StatusOK, export.ContentType, export.Data)
+}
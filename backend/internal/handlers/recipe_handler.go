@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -28,6 +29,10 @@ func (h *RecipeHandler) CreateRecipe(c *gin.Context) {
 
 	recipeID, err := h.Service.CreateRecipe(userID, req)
 	if err != nil {
+		if errors.Is(err, services.ErrIngredientsText) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -40,12 +45,23 @@ func (h *RecipeHandler) CreateRecipe(c *gin.Context) {
 func (h *RecipeHandler) GetMyRecipes(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
 
-	recipes, err := h.Service.GetMyRecipes(userID)
+	var query dto.RecipeListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recipes, total, err := h.Service.GetMyRecipes(userID, query)
 	if err != nil {
+		if err == services.ErrInvalidRecipeQuery {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
 	c.JSON(http.StatusOK, recipes)
 }
 
@@ -71,6 +87,10 @@ func (h *RecipeHandler) UpdateRecipe(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
 			return
 		}
+		if errors.Is(err, services.ErrIngredientsText) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -31,7 +31,7 @@ func (h *RecipeScaleHandler) ScaleRecipe(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.Service.ScaleRecipe(uint(recipeID), userID, servings)
+	resp, err := h.Service.ScaleRecipe(uint(recipeID), userID, servings, c.Query("system"))
 	if err != nil {
 		if err == services.ErrUnauthorized {
 			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	Service services.RecommendationService
+}
+
+func NewRecommendationHandler(service services.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{Service: service}
+}
+
+func (h *RecommendationHandler) Recommend(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var query dto.RecommendationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.Service.Recommend(userID, query)
+	if err != nil {
+		var parseErr *time.ParseError
+		switch {
+		case errors.As(err, &parseErr), err == services.ErrInvalidWeights:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/listexport"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/realtime"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type ShoppingListHandler struct {
@@ -30,6 +36,7 @@ func (h *ShoppingListHandler) GenerateShoppingList(c *gin.Context) {
 		userID,
 		req.StartDate,
 		req.EndDate,
+		req.UsePantry,
 	)
 	if err != nil {
 		if err == services.ErrUnauthorized {
@@ -51,13 +58,22 @@ func (h *ShoppingListHandler) GetShoppingList(c *gin.Context) {
 		return
 	}
 
-	list, err := h.Service.GetShoppingListByID(uint(listID), userID)
+	storeID, ok := storeQuery(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.Service.GetShoppingListByID(uint(listID), userID, storeID)
 
 	if err != nil {
 		if err == services.ErrUnauthorized {
 			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
 			return
 		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "shopping list or store not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -65,6 +81,138 @@ func (h *ShoppingListHandler) GetShoppingList(c *gin.Context) {
 	c.JSON(http.StatusOK, list)
 }
 
+func (h *ShoppingListHandler) ExportShoppingList(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	listID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shopping list id"})
+		return
+	}
+	storeID, ok := storeQuery(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.Service.ExportShoppingList(uint(listID), userID, storeID, c.DefaultQuery("format", "txt"))
+	if err != nil {
+		writeShoppingListError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+export.Filename+`"`)
+	c.Data(http.StatusOK, export.ContentType, export.Data)
+}
+
+// heartbeatInterval keeps idle event streams from being cut by proxies.
+const heartbeatInterval = 25 * time.Second
+
+// StreamEvents sends the list's changes as server-sent events until the
+// client goes away or the list is deleted.
+func (h *ShoppingListHandler) StreamEvents(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	listID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shopping list id"})
+		return
+	}
+
+	events, stop, err := h.Service.Subscribe(uint(listID), userID)
+	if err != nil {
+		writeShoppingListError(c, err)
+		return
+	}
+	defer stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("subscribed", gin.H{"list_id": listID})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return event.Type != realtime.ListDeleted
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{})
+			return true
+		}
+	})
+}
+
+// storeQuery reads the optional ?store= layout id, answering 400 when it
+// isn't a number.
+func storeQuery(c *gin.Context) (uint, bool) {
+	store := c.Query("store")
+	if store == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(store, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store id"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (h *ShoppingListHandler) RefreshShoppingList(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	listID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shopping list id"})
+		return
+	}
+
+	// the body is optional
+	var req dto.RefreshShoppingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list, err := h.Service.Refresh(uint(listID), userID, req.UsePantry)
+	if err != nil {
+		writeShoppingListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// SyncShoppingList applies changes queued by an offline client and answers
+// with what changed on the server since the client's last token.
+func (h *ShoppingListHandler) SyncShoppingList(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	listID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shopping list id"})
+		return
+	}
+
+	var req dto.SyncShoppingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.Service.Sync(uint(listID), userID, req)
+	if err != nil {
+		writeShoppingListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *ShoppingListHandler) ToggleItem(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
 	itemID, err := strconv.ParseUint(c.Param("id"), 10, 64)
@@ -85,3 +233,113 @@ func (h *ShoppingListHandler) ToggleItem(c *gin.Context) {
 
 	c.Status(http.StatusOK)
 }
+
+func (h *ShoppingListHandler) ListShoppingLists(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var query dto.ShoppingListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lists, total, err := h.Service.ListShoppingLists(userID, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.JSON(http.StatusOK, lists)
+}
+
+func (h *ShoppingListHandler) DeleteShoppingList(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	listID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shopping list id"})
+		return
+	}
+
+	if err := h.Service.DeleteShoppingList(uint(listID), userID); err != nil {
+		writeShoppingListError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ShoppingListHandler) AddItem(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	listID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shopping list id"})
+		return
+	}
+
+	var req dto.AddManualItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.Service.AddItem(uint(listID), userID, req)
+	if err != nil {
+		writeShoppingListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *ShoppingListHandler) UpdateItem(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	var req dto.UpdateShoppingListItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.Service.UpdateItem(uint(itemID), userID, req)
+	if err != nil {
+		writeShoppingListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *ShoppingListHandler) DeleteItem(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	itemID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item id"})
+		return
+	}
+
+	if err := h.Service.DeleteItem(uint(itemID), userID); err != nil {
+		writeShoppingListError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func writeShoppingListError(c *gin.Context, err error) {
+	switch {
+	case err == services.ErrUnauthorized:
+		c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+	case err == services.ErrItemNameFixed, err == services.ErrItemNameRequired, errors.Is(err, listexport.ErrUnknownFormat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "shopping list, item, ingredient or store not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/aisle"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type StoreHandler struct {
+	Service services.StoreService
+}
+
+func NewStoreHandler(service services.StoreService) *StoreHandler {
+	return &StoreHandler{Service: service}
+}
+
+// ListAisles returns the aisles a layout can order, in the default order.
+func (h *StoreHandler) ListAisles(c *gin.Context) {
+	c.JSON(http.StatusOK, aisle.Default)
+}
+
+func (h *StoreHandler) CreateStore(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req dto.StoreLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	store, err := h.Service.CreateStore(userID, req)
+	if err != nil {
+		if errors.Is(err, aisle.ErrUnknownAisle) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, store)
+}
+
+func (h *StoreHandler) GetStores(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	stores, err := h.Service.GetStores(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stores)
+}
+
+func (h *StoreHandler) UpdateStore(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store id"})
+		return
+	}
+
+	var req dto.StoreLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	store, err := h.Service.UpdateStore(uint(storeID), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, aisle.ErrUnknownAisle):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err == services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "store not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, store)
+}
+
+func (h *StoreHandler) DeleteStore(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid store id"})
+		return
+	}
+
+	if err := h.Service.DeleteStore(uint(storeID), userID); err != nil {
+		switch {
+		case err == services.ErrUnauthorized:
+			c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "store not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
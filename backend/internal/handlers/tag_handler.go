@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TagHandler struct {
+	Service services.TagService
+}
+
+func NewTagHandler(service services.TagService) *TagHandler {
+	return &TagHandler{Service: service}
+}
+
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.Service.CreateTag(userID, req)
+	if err != nil {
+		writeTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+func (h *TagHandler) GetTags(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	tags, err := h.Service.GetTags(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	tagID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id"})
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.UpdateTag(uint(tagID), userID, req); err != nil {
+		writeTagError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+	tagID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id"})
+		return
+	}
+
+	if err := h.Service.DeleteTag(uint(tagID), userID); err != nil {
+		writeTagError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TagHandler) MigrateCategories(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	result, err := h.Service.MigrateCategories(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func writeTagError(c *gin.Context, err error) {
+	switch {
+	case err == services.ErrUnauthorized:
+		c.JSON(http.StatusForbidden, gin.H{"error": "not authorized"})
+	case err == services.ErrTagExists:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err == services.ErrInvalidTag:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "tag not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,171 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// MaxSize is the largest upload accepted, in bytes.
+const MaxSize = 10 << 20
+
+// MaxPixels bounds decoded size so a small, highly compressed file can't
+// expand into gigabytes of memory.
+const MaxPixels = 40_000_000
+
+// ThumbnailWidths are generated for every upload. Images narrower than a
+// width are not enlarged, so that thumbnail keeps the original size.
+var ThumbnailWidths = []int{160, 480, 960}
+
+var ErrUnsupportedType = errors.New("unsupported image type; use JPEG, PNG or GIF")
+var ErrTooLarge = fmt.Errorf("image is larger than %d MB", MaxSize>>20)
+var ErrTooManyPixels = errors.New("image dimensions are too large")
+var ErrInvalidImage = errors.New("image could not be decoded")
+
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Image is a validated upload with its thumbnails, all JPEG encoded.
+type Image struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+	Thumbnails  map[int][]byte
+}
+
+// Process reads an upload, checks its real type from the content rather than
+// the client's claim, and renders ThumbnailWidths.
+func Process(r io.Reader) (*Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	img := &Image{
+		Data:        data,
+		ContentType: contentType,
+		Extension:   ext,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Thumbnails:  map[int][]byte{},
+	}
+
+	flat := flatten(src)
+	for _, width := range ThumbnailWidths {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resize(flat, width), &jpeg.Options{Quality: 82}); err != nil {
+			return nil, err
+		}
+		img.Thumbnails[width] = buf.Bytes()
+	}
+
+	return img, nil
+}
+
+// ThumbnailKey derives a thumbnail's storage key from the original's,
+// e.g. "recipes/1/ab.png" -> "recipes/1/ab_w160.jpg".
+func ThumbnailKey(key string, width int) string {
+	if dot := strings.LastIndex(key, "."); dot > strings.LastIndex(key, "/") {
+		key = key[:dot]
+	}
+	return fmt.Sprintf("%s_w%d.jpg", key, width)
+}
+
+// Keys lists an original and all of its thumbnails.
+func Keys(key string) []string {
+	keys := []string{key}
+	for _, width := range ThumbnailWidths {
+		keys = append(keys, ThumbnailKey(key, width))
+	}
+	return keys
+}
+
+// flatten draws src onto white, since JPEG has no transparency.
+func flatten(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, b.Min, draw.Over)
+	return dst
+}
+
+// resize scales src down to width with a box filter, averaging every source
+// pixel that falls inside each destination pixel.
+func resize(src *image.RGBA, width int) *image.RGBA {
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	if width >= sw {
+		return src
+	}
+	height := (sh*width + sw/2) / sw
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for dy := 0; dy < height; dy++ {
+		y0, y1 := dy*sh/height, (dy+1)*sh/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for dx := 0; dx < width; dx++ {
+			x0, x1 := dx*sw/width, (dx+1)*sw/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint32
+			for y := y0; y < y1; y++ {
+				row := src.Pix[y*src.Stride+x0*4 : y*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint32(row[i])
+					g += uint32(row[i+1])
+					b += uint32(row[i+2])
+					a += uint32(row[i+3])
+					n++
+				}
+			}
+			i := dst.PixOffset(dx, dy)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
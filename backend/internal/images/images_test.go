@@ -0,0 +1,86 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcess_Thumbnails(t *testing.T) {
+	img, err := Process(bytes.NewReader(testPNG(t, 600, 300)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.ContentType != "image/png" || img.Extension != ".png" || img.Width != 600 || img.Height != 300 {
+		t.Fatalf("unexpected image %+v", img)
+	}
+
+	want := map[int]image.Point{160: {160, 80}, 480: {480, 240}, 960: {600, 300}}
+	for width, size := range want {
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(img.Thumbnails[width]))
+		if err != nil {
+			t.Fatalf("w%d: %v", width, err)
+		}
+		if cfg.Width != size.X || cfg.Height != size.Y {
+			t.Errorf("w%d: got %dx%d, want %v", width, cfg.Width, cfg.Height, size)
+		}
+	}
+
+	thumb, _ := jpeg.Decode(bytes.NewReader(img.Thumbnails[160]))
+	if r, g, _, _ := thumb.At(80, 40).RGBA(); r>>8 < 180 || g>>8 > 30 {
+		t.Errorf("thumbnail colour drifted: r=%d g=%d", r>>8, g>>8)
+	}
+}
+
+func TestProcess_Rejects(t *testing.T) {
+	// a GIF header claiming 65535x65535 pixels
+	huge := []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00")
+
+	cases := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"text", []byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>"), ErrUnsupportedType},
+		{"too large", append(testPNG(t, 1, 1), make([]byte, MaxSize)...), ErrTooLarge},
+		{"too many pixels", huge, ErrTooManyPixels},
+		{"truncated", testPNG(t, 20, 20)[:60], ErrInvalidImage},
+	}
+	for _, c := range cases {
+		if _, err := Process(bytes.NewReader(c.data)); !errors.Is(err, c.want) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, err)
+		}
+	}
+}
+
+func TestThumbnailKey(t *testing.T) {
+	if got := ThumbnailKey("recipes/1/ab.png", 160); got != "recipes/1/ab_w160.jpg" {
+		t.Errorf("got %q", got)
+	}
+	if got := ThumbnailKey("recipes/1.5/ab", 480); got != "recipes/1.5/ab_w480.jpg" {
+		t.Errorf("got %q", got)
+	}
+	if keys := Keys("a.gif"); len(keys) != 1+len(ThumbnailWidths) || !strings.HasSuffix(keys[3], "_w960.jpg") {
+		t.Errorf("unexpected keys %v", keys)
+	}
+}
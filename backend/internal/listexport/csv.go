@@ -0,0 +1,36 @@
+package listexport
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+func init() {
+	Register(Format{
+		Name:        "csv",
+		ContentType: "text/csv; charset=utf-8",
+		Extension:   "csv",
+		Encoder:     csvFormat{},
+	})
+}
+
+// csvFormat writes one row per item for spreadsheets.
+type csvFormat struct{}
+
+func (csvFormat) Encode(w io.Writer, list *List) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"aisle", "item", "amount", "note", "checked"}); err != nil {
+		return err
+	}
+	for _, aisle := range list.Aisles {
+		for _, item := range aisle.Items {
+			row := []string{heading(aisle.Name), oneLine(item.Name), item.Amount(), oneLine(item.Note()), strconv.FormatBool(item.Checked)}
+			if err := out.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
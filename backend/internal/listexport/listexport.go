@@ -0,0 +1,112 @@
+// Package listexport writes shopping lists for people who don't use the app:
+// plain text, Markdown, CSV and a printable PDF.
+package listexport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var ErrUnknownFormat = errors.New("unknown shopping list format")
+
+// List is a shopping list ready to print. Items are grouped by aisle in
+// walking order, and each ingredient appears once.
+type List struct {
+	Title  string
+	Period string // the dates the list covers
+	Store  string // the store layout the aisles follow, empty for the default
+	Aisles []Aisle
+}
+
+type Aisle struct {
+	Name  string
+	Items []Item
+}
+
+type Item struct {
+	Name    string
+	Amounts []string // formatted for reading, one per unit the item is bought in
+	Notes   []string
+	Checked bool // every line for the item is checked off
+}
+
+// Amount joins the item's amounts: "500 g + 2 cups".
+func (i Item) Amount() string {
+	return strings.Join(i.Amounts, " + ")
+}
+
+// Note joins the item's notes.
+func (i Item) Note() string {
+	return strings.Join(i.Notes, "; ")
+}
+
+// Encoder writes a list in one format.
+type Encoder interface {
+	Encode(w io.Writer, list *List) error
+}
+
+type Format struct {
+	Name        string
+	Aliases     []string
+	ContentType string
+	Extension   string
+	Encoder     Encoder
+}
+
+var registry = map[string]Format{}
+
+// Register adds a format under its name and aliases, replacing any previous one.
+func Register(f Format) {
+	registry[f.Name] = f
+	for _, alias := range f.Aliases {
+		registry[alias] = f
+	}
+}
+
+// Lookup finds a format by name or alias.
+func Lookup(name string) (Format, error) {
+	f, ok := registry[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Format{}, fmt.Errorf("%w %q", ErrUnknownFormat, name)
+	}
+	return f, nil
+}
+
+// Names lists the registered formats by their canonical names.
+func Names() []string {
+	var names []string
+	for key, f := range registry {
+		if key == f.Name {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// heading capitalises an aisle name for printing.
+func heading(aisle string) string {
+	if aisle == "" {
+		return "Other"
+	}
+	return strings.ToUpper(aisle[:1]) + aisle[1:]
+}
+
+// subtitle is the line under the title: the dates and the store.
+func subtitle(list *List) string {
+	parts := []string{}
+	if list.Period != "" {
+		parts = append(parts, list.Period)
+	}
+	if list.Store != "" {
+		parts = append(parts, list.Store)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,154 @@
+package listexport
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func sampleList() *List {
+	return &List{
+		Title:  "Shopping list",
+		Period: "2026-06-01 to 2026-06-07",
+		Store:  "Corner shop",
+		Aisles: []Aisle{
+			{Name: "produce", Items: []Item{
+				{Name: "Tomato", Amounts: []string{"4"}, Checked: true},
+				{Name: "Basil (fresh)", Amounts: []string{"1 bunch"}, Notes: []string{"the big one"}},
+			}},
+			{Name: "baking", Items: []Item{
+				{Name: "Flour", Amounts: []string{"500 g", "1 ½ cups"}},
+				{Name: "Sugar_cubes", Amounts: []string{"⅓ cup"}},
+			}},
+		},
+	}
+}
+
+func encode(t *testing.T, format string, list *List) string {
+	t.Helper()
+	f, err := Lookup(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := f.Encoder.Encode(&buf, list); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"txt", "Text", "md", "csv", "PDF"} {
+		if _, err := Lookup(name); err != nil {
+			t.Errorf("Lookup(%q) failed: %v", name, err)
+		}
+	}
+	if _, err := Lookup("docx"); err == nil {
+		t.Error("expected unknown format error")
+	}
+	if got := strings.Join(Names(), ","); got != "csv,markdown,pdf,text" {
+		t.Errorf("unexpected names %s", got)
+	}
+}
+
+func TestTextEncode(t *testing.T) {
+	want := `Shopping list
+2026-06-01 to 2026-06-07 · Corner shop
+
+PRODUCE
+[x] Tomato - 4
+[ ] Basil (fresh) - 1 bunch (the big one)
+
+BAKING
+[ ] Flour - 500 g + 1 ½ cups
+[ ] Sugar_cubes - ⅓ cup
+`
+	if got := encode(t, "txt", sampleList()); got != want {
+		t.Errorf("got:\n%s", got)
+	}
+}
+
+func TestMarkdownEncode(t *testing.T) {
+	got := encode(t, "md", sampleList())
+	for _, line := range []string{
+		"# Shopping list\n",
+		"## Produce\n\n- [x] **Tomato** — 4\n- [ ] **Basil (fresh)** — 1 bunch _(the big one)_\n",
+		"## Baking\n",
+		`- [ ] **Sugar\_cubes** — ⅓ cup`,
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("missing %q in:\n%s", line, got)
+		}
+	}
+}
+
+func TestCSVEncode(t *testing.T) {
+	want := `aisle,item,amount,note,checked
+Produce,Tomato,4,,true
+Produce,Basil (fresh),1 bunch,the big one,false
+Baking,Flour,500 g + 1 ½ cups,,false
+Baking,Sugar_cubes,⅓ cup,,false
+`
+	if got := encode(t, "csv", sampleList()); got != want {
+		t.Errorf("got:\n%s", got)
+	}
+}
+
+func TestPDFEncode(t *testing.T) {
+	got := encode(t, "pdf", sampleList())
+	if !strings.HasPrefix(got, "%PDF-1.4") || !strings.HasSuffix(got, "%%EOF\n") {
+		t.Fatal("not a PDF file")
+	}
+	// WinAnsi dashes and halves, spelled-out thirds, escaped parentheses
+	for _, text := range []string{"(Basil \\(fresh\\) \x97 1 bunch)", "(Flour \x97 500 g + 1 \xbd cups)", "(Sugar_cubes \x97 1/3 cup)", "(Page 1 of 1)"} {
+		if !strings.Contains(got, text) {
+			t.Errorf("missing %q", text)
+		}
+	}
+
+	// every cross-reference entry points at its object
+	start := strings.LastIndex(got, "\nxref\n")
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(got[start:], -1)
+	if len(entries) != 6 {
+		t.Fatalf("expected 6 objects, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if !strings.HasPrefix(got[offset:], strconv.Itoa(i+1)+" 0 obj") {
+			t.Errorf("xref entry %d points at %q", i+1, got[offset:offset+10])
+		}
+	}
+}
+
+func TestPDFFlowsOntoMorePages(t *testing.T) {
+	aisle := Aisle{Name: "pantry"}
+	for i := 0; i < 200; i++ {
+		aisle.Items = append(aisle.Items, Item{Name: "Item " + strconv.Itoa(i), Amounts: []string{"1"}})
+	}
+	got := encode(t, "pdf", &List{Title: "Big shop", Aisles: []Aisle{aisle}})
+	if !strings.Contains(got, "/Count 2 ") || !strings.Contains(got, "(Page 2 of 2)") {
+		t.Error("expected two pages")
+	}
+	// the first page fills the right-hand column before breaking
+	if !strings.Contains(got, fmt.Sprintf("%.2f", margin+columnWidth+gutter+textIndent)) {
+		t.Error("second column never used")
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	lines := wrapText("Extra virgin olive oil from the good shop — 2 bottles", itemSize, 100)
+	for _, line := range lines {
+		if textWidth(line, itemSize) > 100 {
+			t.Errorf("line %q is too wide", line)
+		}
+	}
+	if strings.Join(lines, " ") != "Extra virgin olive oil from the good shop — 2 bottles" || len(lines) < 3 {
+		t.Errorf("unexpected wrap %q", lines)
+	}
+	if got := wrapText(strings.Repeat("x", 80), itemSize, 100); len(got) < 2 {
+		t.Errorf("long word not split: %q", got)
+	}
+}
@@ -0,0 +1,57 @@
+package listexport
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(Format{
+		Name:        "markdown",
+		Aliases:     []string{"md"},
+		ContentType: "text/markdown; charset=utf-8",
+		Extension:   "md",
+		Encoder:     markdownFormat{},
+	})
+}
+
+// markdownFormat writes a task list per aisle, so checked items render
+// ticked in anything that understands GitHub-style checkboxes.
+type markdownFormat struct{}
+
+func (markdownFormat) Encode(w io.Writer, list *List) error {
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "# %s\n", markdownText(list.Title))
+	if sub := subtitle(list); sub != "" {
+		fmt.Fprintf(b, "\n%s\n", markdownText(sub))
+	}
+
+	for _, aisle := range list.Aisles {
+		fmt.Fprintf(b, "\n## %s\n\n", markdownText(heading(aisle.Name)))
+		for _, item := range aisle.Items {
+			box := "[ ]"
+			if item.Checked {
+				box = "[x]"
+			}
+			line := fmt.Sprintf("- %s **%s**", box, markdownText(item.Name))
+			if amount := item.Amount(); amount != "" {
+				line += " — " + markdownText(amount)
+			}
+			if note := item.Note(); note != "" {
+				line += " _(" + markdownText(note) + ")_"
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`")
+
+func markdownText(s string) string {
+	return markdownEscaper.Replace(oneLine(s))
+}
@@ -0,0 +1,287 @@
+package listexport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(Format{
+		Name:        "pdf",
+		ContentType: "application/pdf",
+		Extension:   "pdf",
+		Encoder:     pdfFormat{},
+	})
+}
+
+// Page geometry in points, for A4 paper.
+const (
+	pageWidth   = 595.28
+	pageHeight  = 841.89
+	margin      = 48.0
+	gutter      = 24.0
+	columnWidth = (pageWidth - 2*margin - gutter) / 2
+
+	titleSize   = 18.0
+	headingSize = 11.0
+	itemSize    = 10.0
+	noteSize    = 8.5
+	lineHeight  = 14.0
+	boxSize     = 8.0
+	textIndent  = 14.0 // from the column edge to the item text, past the box
+)
+
+// pdfFormat lays the list out as a printable page in two columns: aisle
+// headings with a rule under them, then one box per item to tick in pen.
+// It writes a bare PDF 1.4 file using the built-in Helvetica fonts, so no
+// font files or libraries are needed.
+type pdfFormat struct{}
+
+func (pdfFormat) Encode(w io.Writer, list *List) error {
+	l := &pdfLayout{}
+	l.newPage()
+
+	l.text("F2", titleSize, margin, l.y-titleSize, oneLine(list.Title))
+	l.y -= titleSize + 6
+	if sub := subtitle(list); sub != "" {
+		l.text("F1", itemSize, margin, l.y-itemSize, sub)
+		l.y -= itemSize + 6
+	}
+	fmt.Fprintf(l.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, l.y-4, pageWidth-margin, l.y-4)
+	l.y -= 20
+	l.top = l.y
+
+	for _, aisle := range list.Aisles {
+		l.aisle(aisle)
+	}
+	return writePDF(w, l.pages)
+}
+
+type pdfLayout struct {
+	pages  []*bytes.Buffer
+	page   *bytes.Buffer
+	column int
+	top    float64 // where columns start on the current page
+	y      float64 // baseline of the next line, counting down
+}
+
+func (l *pdfLayout) newPage() {
+	l.page = &bytes.Buffer{}
+	l.pages = append(l.pages, l.page)
+	l.column = 0
+	l.top = pageHeight - margin
+	l.y = l.top
+}
+
+// fit moves to the next column, or page, unless height more points fit
+// in the current one.
+func (l *pdfLayout) fit(height float64) {
+	if l.y-height >= margin+lineHeight {
+		return
+	}
+	if l.column == 0 {
+		l.column = 1
+		l.y = l.top
+		return
+	}
+	l.newPage()
+}
+
+func (l *pdfLayout) x() float64 {
+	return margin + float64(l.column)*(columnWidth+gutter)
+}
+
+func (l *pdfLayout) aisle(aisle Aisle) {
+	// keep a heading with its first item
+	l.fit(headingSize + 8 + lineHeight)
+	if l.y != l.top {
+		l.y -= 8
+	}
+	x := l.x()
+	l.text("F2", headingSize, x, l.y-headingSize, heading(aisle.Name))
+	l.y -= headingSize + 4
+	fmt.Fprintf(l.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x, l.y, x+columnWidth, l.y)
+	l.y -= 4
+
+	for _, item := range aisle.Items {
+		l.item(item)
+	}
+}
+
+func (l *pdfLayout) item(item Item) {
+	line := oneLine(item.Name)
+	if amount := item.Amount(); amount != "" {
+		line += " — " + amount
+	}
+	lines := wrapText(line, itemSize, columnWidth-textIndent)
+	var notes []string
+	if note := item.Note(); note != "" {
+		notes = wrapText(oneLine(note), noteSize, columnWidth-textIndent)
+	}
+	height := float64(len(lines))*lineHeight + float64(len(notes))*(noteSize+3)
+	if height > l.top-margin-lineHeight {
+		height = lineHeight
+	}
+	l.fit(height)
+
+	x := l.x()
+	baseline := l.y - lineHeight + 3
+	fmt.Fprintf(l.page, "0.8 w %.2f %.2f %.2f %.2f re S\n", x, baseline-1, boxSize, boxSize)
+	if item.Checked {
+		fmt.Fprintf(l.page, "1.2 w %.2f %.2f m %.2f %.2f l %.2f %.2f l S\n",
+			x+1.5, baseline+3, x+3.5, baseline+0.5, x+7, baseline+6.5)
+		l.page.WriteString("0.5 g\n")
+	}
+	for _, text := range lines {
+		l.text("F1", itemSize, x+textIndent, l.y-lineHeight+3, text)
+		l.y -= lineHeight
+	}
+	for _, text := range notes {
+		l.text("F1", noteSize, x+textIndent, l.y-noteSize, text)
+		l.y -= noteSize + 3
+	}
+	if item.Checked {
+		l.page.WriteString("0 g\n")
+	}
+}
+
+func (l *pdfLayout) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(l.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+// writePDF writes the catalog, the page tree, the two fonts and a page and
+// content stream per page, followed by the cross-reference table.
+func writePDF(w io.Writer, pages []*bytes.Buffer) error {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	count := 4 + 2*len(pages)
+	offsets := make([]int, count+1)
+	object := func(id int, body string) {
+		offsets[id] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", id, body)
+	}
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object(3, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object(4, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, content := range pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(pages))
+		fmt.Fprintf(content, "0.5 g BT /F1 8.0 Tf %.2f %.2f Td (%s) Tj ET 0 g\n",
+			(pageWidth-textWidth(footer, 8))/2, margin/2, pdfString(footer))
+
+		object(5+2*i, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		object(6+2*i, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", count+1)
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", count+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// winAnsi maps the characters outside Latin-1 that lists are likely to
+// contain onto WinAnsiEncoding. Fractions the encoding lacks are spelled out.
+var winAnsi = map[rune]string{
+	'€': "\x80", '…': "\x85", '‘': "\x91", '’': "\x92", '“': "\x93", '”': "\x94",
+	'•': "\x95", '–': "\x96", '—': "\x97",
+	'⅓': "1/3", '⅔': "2/3", '⅛': "1/8", '⅜': "3/8", '⅝': "5/8", '⅞': "7/8",
+}
+
+// pdfString encodes s for a literal string in a content stream.
+func pdfString(s string) string {
+	b := &strings.Builder{}
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case winAnsi[r] != "":
+			b.WriteString(winAnsi[r])
+		case r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// helveticaWidths are the advance widths of printable ASCII in Helvetica,
+// in thousandths of the font size.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth measures s set in Helvetica, in points. Characters outside
+// ASCII count as a digit, which is close enough for wrapping.
+func textWidth(s string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r < 0x7f:
+			total += helveticaWidths[r-0x20]
+		case r == '—':
+			total += 1000
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// wrapText breaks s into lines no wider than width, splitting words only
+// when a single word is wider than a line.
+func wrapText(s string, size, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if textWidth(candidate, size) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = word
+		for textWidth(line, size) > width {
+			runes := []rune(line)
+			cut := len(runes) - 1
+			for cut > 1 && textWidth(string(runes[:cut]), size) > width {
+				cut--
+			}
+			lines = append(lines, string(runes[:cut]))
+			line = string(runes[cut:])
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
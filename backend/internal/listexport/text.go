@@ -0,0 +1,51 @@
+package listexport
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() {
+	Register(Format{
+		Name:        "text",
+		Aliases:     []string{"txt"},
+		ContentType: "text/plain; charset=utf-8",
+		Extension:   "txt",
+		Encoder:     textFormat{},
+	})
+}
+
+// textFormat writes a list that reads well in a text message or email:
+// aisle headings in capitals and a [ ] box in front of every item.
+type textFormat struct{}
+
+func (textFormat) Encode(w io.Writer, list *List) error {
+	b := &strings.Builder{}
+
+	b.WriteString(oneLine(list.Title) + "\n")
+	if sub := subtitle(list); sub != "" {
+		b.WriteString(sub + "\n")
+	}
+
+	for _, aisle := range list.Aisles {
+		fmt.Fprintf(b, "\n%s\n", strings.ToUpper(heading(aisle.Name)))
+		for _, item := range aisle.Items {
+			box := "[ ]"
+			if item.Checked {
+				box = "[x]"
+			}
+			line := box + " " + oneLine(item.Name)
+			if amount := item.Amount(); amount != "" {
+				line += " - " + amount
+			}
+			if note := item.Note(); note != "" {
+				line += " (" + oneLine(note) + ")"
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
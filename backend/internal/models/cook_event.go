@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// CookEvent records one time a recipe was cooked, from a meal plan or ad hoc.
+// BaseServings keeps the recipe's own servings at the time, so scaled
+// cooking still reads right after the recipe changes.
+type CookEvent struct {
+	ID           uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"not null;index"`
+	RecipeID     uint      `gorm:"not null;index"`
+	Recipe       Recipe    `gorm:"foreignKey:RecipeID;constraint:OnDelete:CASCADE"`
+	MealPlanID   *uint     `gorm:"uniqueIndex:idx_cook_events_meal_plan"` // nil when cooked ad hoc or once the plan is deleted
+	Date         time.Time `gorm:"not null"`
+	Servings     int
+	BaseServings int
+	Notes        string
+	Rating       *int // 1-5
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -6,6 +6,30 @@ type Ingredient struct {
 	ID   uint   `gorm:"primaryKey"`
 	Name string `gorm:"unique;not null"`
 
+	Density     *float64 // grams per millilitre, for volume to weight conversion
+	PieceWeight *float64 // grams per piece, e.g. one egg
+
+	Nutrition *Nutrition `gorm:"type:text;serializer:json"` // per 100 g, nil when unknown
+
+	Allergens []string `gorm:"type:text;serializer:json"` // names from package diet, standard or custom
+	Diets     []string `gorm:"type:text;serializer:json"` // diets it suits, nil when unknown
+
+	Aisle string // from package aisle, empty when not assigned
+
+	Aliases []IngredientAlias `gorm:"foreignKey:IngredientID"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// Nutrition is energy in kcal, sodium in mg and the rest in grams. It is per
+// 100 g on ingredients, per serving on recipes and per day for user targets.
+type Nutrition struct {
+	Kcal    float64 `json:"kcal"`
+	Protein float64 `json:"protein"`
+	Fat     float64 `json:"fat"`
+	Carbs   float64 `json:"carbs"`
+	Fiber   float64 `json:"fiber"`
+	Sugar   float64 `json:"sugar"`
+	Sodium  float64 `json:"sodium"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// IngredientAlias is another name an ingredient goes by, such as
+// "garbanzo beans" for chickpeas, or the name of a duplicate merged into it.
+// Names are stored cleaned and lower-cased.
+type IngredientAlias struct {
+	ID           uint       `gorm:"primaryKey"`
+	Name         string     `gorm:"unique;not null"`
+	IngredientID uint       `gorm:"not null;index"`
+	Ingredient   Ingredient `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE"`
+
+	CreatedAt time.Time
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PantryItem is an amount of an ingredient the user has in stock.
+type PantryItem struct {
+	ID           uint       `gorm:"primaryKey"`
+	UserID       uint       `gorm:"not null;index"`
+	IngredientID uint       `gorm:"not null"`
+	Ingredient   Ingredient `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE"`
+	Quantity     float64    `gorm:"not null"`
+	Unit         string     // empty for pieces
+	ExpiresAt    *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
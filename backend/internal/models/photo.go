@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Photo is an uploaded image of a recipe. Step 0 is the cover photo, any
+// other value is the instruction step it illustrates; photos follow the step
+// number rather than the instruction row because recipe updates recreate
+// the instructions. Renumbering or deleting an instruction moves or drops
+// its photo.
+type Photo struct {
+	ID          uint   `gorm:"primaryKey"`
+	RecipeID    uint   `gorm:"not null;uniqueIndex:idx_photos_recipe_step"`
+	Step        int    `gorm:"not null;uniqueIndex:idx_photos_recipe_step"`
+	Key         string `gorm:"not null"` // storage key of the original
+	ContentType string
+	Width       int
+	Height      int
+
+	CreatedAt time.Time
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// IngredientPrice is one observed price, Price for Quantity of Unit, such as
+// 2.49 for 1 kg at Store A. Prices are kept as a history; estimates use the
+// most recent one.
+type IngredientPrice struct {
+	ID           uint       `gorm:"primaryKey"`
+	UserID       uint       `gorm:"not null;index:idx_prices_user_ingredient"`
+	IngredientID uint       `gorm:"not null;index:idx_prices_user_ingredient"`
+	Ingredient   Ingredient `gorm:"foreignKey:IngredientID;constraint:OnDelete:CASCADE"`
+	Price        float64    `gorm:"not null"`
+	Quantity     float64    `gorm:"not null"`
+	Unit         string     // empty for pieces
+	Store        string
+	Date         time.Time `gorm:"not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
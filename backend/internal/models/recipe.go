@@ -13,8 +13,12 @@ type Recipe struct {
 	CookTime    int    // minutes
 	Category    string `gorm:"not null"`
 
+	Nutrition *Nutrition `gorm:"type:text;serializer:json"` // per serving as entered, nil when not given
+
 	Ingredients  []RecipeIngredient `gorm:"foreignKey:RecipeID"`
 	Instructions []Instruction      `gorm:"foreignKey:RecipeID"`
+	Tags         []Tag              `gorm:"many2many:recipe_tags;"`
+	Photos       []Photo            `gorm:"foreignKey:RecipeID"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
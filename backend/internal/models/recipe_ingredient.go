@@ -12,6 +12,7 @@ type RecipeIngredient struct {
 
 	Quantity float64 `gorm:"not null"`
 	Unit     string  `gorm:"not null"`
+	Note     string  // preparation or size, e.g. "finely chopped"
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
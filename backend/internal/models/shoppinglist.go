@@ -9,6 +9,7 @@ type ShoppingList struct {
 	StartDate time.Time          `gorm:"not null"`
 	EndDate   time.Time          `gorm:"not null"`
 	Items     []ShoppingListItem `gorm:"foreignKey:ShoppingListID"`
+	Revision  uint               `gorm:"not null;default:0"` // counts item changes; the token clients sync from
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
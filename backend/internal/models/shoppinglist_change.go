@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ShoppingListChange logs one change to an item on a list. Token is the
+// list's revision after the change.
+type ShoppingListChange struct {
+	ID             uint `gorm:"primaryKey"`
+	ShoppingListID uint `gorm:"not null;uniqueIndex:idx_list_changes_token"`
+	Token          uint `gorm:"not null;uniqueIndex:idx_list_changes_token"`
+	ItemID         uint `gorm:"not null"`
+	Version        uint // the item's version after the change
+	Deleted        bool `gorm:"default:false"`
+
+	CreatedAt time.Time
+}
+
+// ShoppingListSyncOp is the receipt for an operation a client synced, so a
+// batch sent twice is only applied once.
+type ShoppingListSyncOp struct {
+	ID             uint   `gorm:"primaryKey"`
+	ShoppingListID uint   `gorm:"not null;uniqueIndex:idx_sync_ops_list_op"`
+	OpID           string `gorm:"not null;uniqueIndex:idx_sync_ops_list_op"`
+	ItemID         uint
+	Status         string `gorm:"not null"`
+
+	CreatedAt time.Time
+}
@@ -6,12 +6,56 @@ type ShoppingListItem struct {
 	ID             uint         `gorm:"primaryKey"`
 	ShoppingListID uint         `gorm:"not null"`
 	ShoppingList   ShoppingList `gorm:"foreignKey:ShoppingListID;constraint:OnDelete:CASCADE"`
-	IngredientID   uint         `gorm:"not null"`
+	IngredientID   *uint        // nil for free-text items such as "paper towels"
 	Ingredient     Ingredient   `gorm:"foreignKey:IngredientID;constraint:OnDelete:RESTRICT"`
-	Quantity       float64      `gorm:"not null"`
+	Name           string       // free-text items only
+	Quantity       float64      `gorm:"not null"` // to buy
+	Needed         float64      // before pantry stock was taken off; 0 on older lists
 	Unit           string       `gorm:"not null"`
-	Checked        bool         `gorm:"default:false"`
+	Note           string
+	Manual         bool `gorm:"default:false"` // added by the user, kept when the list is regenerated
+	Checked        bool `gorm:"default:false"`
+	Stale          bool `gorm:"default:false"` // checked off, then dropped from the meal plan by a refresh
+
+	Version  uint       `gorm:"not null;default:1"` // bumped on every change
+	ClientID string     `gorm:"index"`              // the id a client gave an item it added offline
+	Clock    FieldClock `gorm:"type:text;serializer:json"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// Fields of an item that offline clients can change, as named in its Clock.
+const (
+	FieldChecked  = "checked"
+	FieldName     = "name"
+	FieldQuantity = "quantity"
+	FieldUnit     = "unit"
+	FieldNote     = "note"
+)
+
+// FieldClock holds when each field of an item was last written, so edits
+// made offline can be merged field by field.
+type FieldClock map[string]time.Time
+
+// Touch records that the fields were written at the given time.
+func (i *ShoppingListItem) Touch(at time.Time, fields ...string) {
+	if i.Clock == nil {
+		i.Clock = FieldClock{}
+	}
+	for _, field := range fields {
+		i.Clock[field] = at
+	}
+}
+
+// ItemName is what the list calls the item: its ingredient, or the name
+// given to a free-text item.
+func (i ShoppingListItem) ItemName() string {
+	if i.IngredientID != nil && i.Ingredient.Name != "" {
+		return i.Ingredient.Name
+	}
+	if i.Name != "" {
+		return i.Name
+	}
+	return "Unknown"
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// StoreLayout is the order a user walks the aisles of a store in, used to
+// sort shopping lists.
+type StoreLayout struct {
+	ID     uint     `gorm:"primaryKey"`
+	UserID uint     `gorm:"not null;index"`
+	Name   string   `gorm:"not null"`
+	Aisles []string `gorm:"type:text;serializer:json"` // names from package aisle, first walked first
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
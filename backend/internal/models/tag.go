@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type Tag struct {
+	ID     uint   `gorm:"primaryKey"`
+	UserID uint   `gorm:"not null;uniqueIndex:idx_tags_user_name"`
+	User   User   `gorm:"foreignKey:UserID"`
+	Name   string `gorm:"not null;uniqueIndex:idx_tags_user_name"`
+
+	Recipes []Recipe `gorm:"many2many:recipe_tags;"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
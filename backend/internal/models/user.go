@@ -8,6 +8,18 @@ type User struct {
 	Email    string `gorm:"unique"`
 	Password string `gorm:"not null"`
 
+	Targets    *Nutrition  `gorm:"type:text;serializer:json"` // daily nutrition targets, nil when not set
+	Exclusions *Exclusions `gorm:"type:text;serializer:json"` // nil when none declared
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+// Exclusions are what a user's household can't eat: allergens to avoid and
+// diets every planned recipe must suit. Planning a recipe that breaks them
+// gives a warning, or is refused when Refuse is set.
+type Exclusions struct {
+	Allergens []string `json:"allergens"`
+	Diets     []string `json:"diets"`
+	Refuse    bool     `json:"refuse"`
+}
@@ -0,0 +1,134 @@
+package nutrition
+
+import (
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidCSV = errors.New("invalid nutrition csv")
+
+// Record is one CSV row: an ingredient name and its facts per 100 g.
+type Record struct {
+	Name  string
+	Facts Facts
+}
+
+//go:embed data/*.csv
+var data embed.FS
+
+// Bundled returns the dataset shipped with the server, approximate values
+// for common ingredients taken from USDA FoodData Central (SR Legacy).
+func Bundled() ([]Record, error) {
+	f, err := data.Open("data/usda_common.csv")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadCSV(f)
+}
+
+// Header names accepted for each column, after normalising, so USDA-style
+// extracts ("Description", "Energy (kcal)", "Total lipid (fat)") load as-is.
+var columns = map[string][]string{
+	"name":    {"name", "description", "food", "ingredient"},
+	"kcal":    {"kcal", "calories", "energy", "energy_kcal"},
+	"protein": {"protein"},
+	"fat":     {"fat", "total_fat", "total_lipid_fat", "lipid"},
+	"carbs":   {"carbs", "carbohydrate", "carbohydrates", "carbohydrate_by_difference"},
+	"fiber":   {"fiber", "fibre", "dietary_fiber", "fiber_total_dietary"},
+	"sugar":   {"sugar", "sugars", "total_sugars", "sugars_total"},
+	"sodium":  {"sodium", "sodium_na"},
+}
+
+var required = []string{"name", "kcal", "protein", "fat", "carbs"}
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+func normalizeHeader(h string) string {
+	h = strings.Trim(nonWord.ReplaceAllString(strings.ToLower(h), "_"), "_")
+	for _, suffix := range []string{"_mg", "_g"} {
+		h = strings.TrimSuffix(h, suffix)
+	}
+	return h
+}
+
+// ReadCSV reads a header row followed by one ingredient per row. Lines
+// starting with # are comments and blank nutrient cells count as zero.
+func ReadCSV(r io.Reader) ([]Record, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+
+	index := map[string]int{}
+	for i, h := range header {
+		h = normalizeHeader(h)
+		for column, names := range columns {
+			for _, name := range names {
+				if _, seen := index[column]; h == name && !seen {
+					index[column] = i
+				}
+			}
+		}
+	}
+	for _, column := range required {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("%w: no %s column", ErrInvalidCSV, column)
+		}
+	}
+
+	var records []Record
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		cell := func(column string) string {
+			i, ok := index[column]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		record := Record{Name: cell("name")}
+		if record.Name == "" {
+			continue
+		}
+		targets := map[string]*float64{
+			"kcal": &record.Facts.Kcal, "protein": &record.Facts.Protein, "fat": &record.Facts.Fat,
+			"carbs": &record.Facts.Carbs, "fiber": &record.Facts.Fiber, "sugar": &record.Facts.Sugar,
+			"sodium": &record.Facts.Sodium,
+		}
+		for column, target := range targets {
+			value := cell(column)
+			if value == "" {
+				continue
+			}
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%w: line %d: bad %s value %q", ErrInvalidCSV, line, column, value)
+			}
+			*target = n
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,107 @@
+package nutrition
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/units"
+)
+
+var ErrNoDensity = errors.New("volume unit but no density for the ingredient")
+var ErrNoPieceWeight = errors.New("count unit but no piece weight for the ingredient")
+
+// Facts are nutrients in kcal, grams, and milligrams for sodium. Ingredient
+// data is per 100 g; recipe totals are for the whole amount.
+type Facts struct {
+	Kcal    float64
+	Protein float64
+	Fat     float64
+	Carbs   float64
+	Fiber   float64
+	Sugar   float64
+	Sodium  float64
+}
+
+func (f Facts) Add(o Facts) Facts {
+	return Facts{
+		Kcal:    f.Kcal + o.Kcal,
+		Protein: f.Protein + o.Protein,
+		Fat:     f.Fat + o.Fat,
+		Carbs:   f.Carbs + o.Carbs,
+		Fiber:   f.Fiber + o.Fiber,
+		Sugar:   f.Sugar + o.Sugar,
+		Sodium:  f.Sodium + o.Sodium,
+	}
+}
+
+func (f Facts) Scale(factor float64) Facts {
+	return Facts{
+		Kcal:    f.Kcal * factor,
+		Protein: f.Protein * factor,
+		Fat:     f.Fat * factor,
+		Carbs:   f.Carbs * factor,
+		Fiber:   f.Fiber * factor,
+		Sugar:   f.Sugar * factor,
+		Sodium:  f.Sodium * factor,
+	}
+}
+
+// ForGrams scales per-100 g facts to a weight.
+func (f Facts) ForGrams(grams float64) Facts {
+	return f.Scale(grams / 100)
+}
+
+// Round rounds every value to one decimal for display.
+func (f Facts) Round() Facts {
+	r := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return Facts{r(f.Kcal), r(f.Protein), r(f.Fat), r(f.Carbs), r(f.Fiber), r(f.Sugar), r(f.Sodium)}
+}
+
+// Volumes too small for the units catalog, in millilitres.
+var pinchUnits = map[string]float64{
+	"pinch":    0.3,
+	"pinches":  0.3,
+	"dash":     0.6,
+	"dashes":   0.6,
+	"smidgen":  0.15,
+	"drop":     0.05,
+	"drops":    0.05,
+	"splash":   5,
+	"splashes": 5,
+}
+
+// Grams converts a recipe quantity to grams. Mass units convert directly,
+// volumes go through density (g/ml), and anything counted, including no
+// unit or units like "clove" or "slice", through the weight of one piece.
+// density and pieceWeight are 0 when unknown.
+func Grams(quantity float64, unit string, density, pieceWeight float64) (float64, error) {
+	if quantity == 0 {
+		return 0, nil
+	}
+
+	ml, isPinch := pinchUnits[strings.ToLower(strings.TrimSpace(unit))]
+	base, u, err := units.ToBase(quantity, unit)
+	switch {
+	case isPinch:
+		base, u.Dimension = quantity*ml, units.Volume
+	case err != nil:
+		// "2 cloves", "3 slices", "1" all count pieces
+		base, u.Dimension = quantity, units.Count
+	}
+
+	switch u.Dimension {
+	case units.Mass:
+		return base, nil
+	case units.Volume:
+		if density <= 0 {
+			return 0, ErrNoDensity
+		}
+		return base * density, nil
+	default:
+		if pieceWeight <= 0 {
+			return 0, ErrNoPieceWeight
+		}
+		return base * pieceWeight, nil
+	}
+}
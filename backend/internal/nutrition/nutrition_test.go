@@ -0,0 +1,92 @@
+package nutrition
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGrams(t *testing.T) {
+	cases := []struct {
+		quantity    float64
+		unit        string
+		density     float64
+		pieceWeight float64
+		want        float64
+		err         error
+	}{
+		{200, "g", 0, 0, 200, nil},
+		{1.5, "kg", 0, 0, 1500, nil},
+		{2, "oz", 0, 0, 56.699, nil},
+		{1, "cup", 0.53, 0, 125.39, nil},
+		{2, "tbsp", 0, 0, 0, ErrNoDensity},
+		{2, "", 0, 50, 100, nil},
+		{3, "cloves", 0, 4, 12, nil},
+		{1, "dozen", 0, 50, 600, nil},
+		{2, "slices", 0, 0, 0, ErrNoPieceWeight},
+		{1, "pinch", 1.22, 0, 0.366, nil},
+		{0, "", 0, 0, 0, nil},
+	}
+	for _, c := range cases {
+		got, err := Grams(c.quantity, c.unit, c.density, c.pieceWeight)
+		if !errors.Is(err, c.err) || math.Abs(got-c.want) > 0.01 {
+			t.Errorf("Grams(%v %q) = %v, %v; want %v, %v", c.quantity, c.unit, got, err, c.want, c.err)
+		}
+	}
+}
+
+func TestReadCSV_USDAHeaders(t *testing.T) {
+	csv := `# exported from FoodData Central
+Description,Energy (kcal),Energy (kJ),Protein (g),Total lipid (fat) (g),"Carbohydrate, by difference (g)","Fiber, total dietary (g)","Sugars, total (g)","Sodium, Na (mg)"
+"Onions, raw",40,166,1.1,0.1,9.34,1.7,4.24,4
+Butter,717,3000,0.85,81.11,0.06,,0.06,643
+`
+	records, err := ReadCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %+v", records)
+	}
+	onion := records[0]
+	if onion.Name != "Onions, raw" || onion.Facts != (Facts{40, 1.1, 0.1, 9.34, 1.7, 4.24, 4}) {
+		t.Errorf("unexpected onion %+v", onion)
+	}
+	if records[1].Facts.Fiber != 0 || records[1].Facts.Sodium != 643 {
+		t.Errorf("unexpected butter %+v", records[1])
+	}
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	for _, csv := range []string{
+		"",
+		"name,kcal,protein\nEgg,143,12.6\n",
+		"name,kcal,protein,fat,carbs\nEgg,lots,12.6,9.5,0.7\n",
+		"name,kcal,protein,fat,carbs\nEgg,-1,12.6,9.5,0.7\n",
+	} {
+		if _, err := ReadCSV(strings.NewReader(csv)); !errors.Is(err, ErrInvalidCSV) {
+			t.Errorf("%q: expected ErrInvalidCSV, got %v", csv, err)
+		}
+	}
+}
+
+func TestBundled(t *testing.T) {
+	records, err := Bundled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for _, r := range records {
+		if seen[r.Name] {
+			t.Errorf("duplicate row %q", r.Name)
+		}
+		seen[r.Name] = true
+		if r.Facts.Sugar > r.Facts.Carbs || r.Facts.Fiber > r.Facts.Carbs {
+			t.Errorf("%s: sugar or fiber exceeds carbohydrate", r.Name)
+		}
+	}
+	if !seen["Egg"] || !seen["Flour"] || len(records) < 50 {
+		t.Fatalf("bundled dataset looks incomplete: %d rows", len(records))
+	}
+}
@@ -0,0 +1,374 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/units"
+)
+
+var ErrEmptyLine = errors.New("empty ingredient line")
+var ErrNoIngredient = errors.New("no ingredient name found")
+
+// Ingredient is one parsed ingredient line. Amount is zero when the line has
+// no quantity ("salt to taste"); AmountMax is set only for ranges ("2-3").
+type Ingredient struct {
+	Original  string
+	Amount    float64
+	AmountMax float64
+	Unit      string
+	Size      string
+	Name      string
+	Note      string
+}
+
+var unicodeFractions = map[rune]string{
+	'½': "1/2", '⅓': "1/3", '⅔': "2/3", '¼': "1/4", '¾': "3/4",
+	'⅕': "1/5", '⅖': "2/5", '⅗': "3/5", '⅘': "4/5", '⅙': "1/6", '⅚': "5/6",
+	'⅛': "1/8", '⅜': "3/8", '⅝': "5/8", '⅞': "7/8",
+}
+
+// Units that can't be converted but are common enough to split from the name.
+var descriptiveUnits = map[string]string{
+	"can": "can", "cans": "can", "tin": "tin", "tins": "tin",
+	"clove": "clove", "cloves": "clove",
+	"pinch": "pinch", "pinches": "pinch", "dash": "dash", "dashes": "dash",
+	"slice": "slice", "slices": "slice", "stick": "stick", "sticks": "stick",
+	"sprig": "sprig", "sprigs": "sprig", "bunch": "bunch", "bunches": "bunch",
+	"handful": "handful", "handfuls": "handful", "head": "head", "heads": "head",
+	"package": "package", "packages": "package", "pkg": "package",
+	"packet": "packet", "packets": "packet", "jar": "jar", "jars": "jar",
+	"bottle": "bottle", "bottles": "bottle", "bag": "bag", "bags": "bag",
+	"sheet": "sheet", "sheets": "sheet", "leaf": "leaf", "leaves": "leaf",
+	"stalk": "stalk", "stalks": "stalk", "drop": "drop", "drops": "drop",
+}
+
+var sizeWords = map[string]bool{
+	"small": true, "medium": true, "large": true, "extra-large": true, "big": true, "jumbo": true,
+}
+
+var prepAdverbs = map[string]bool{
+	"finely": true, "roughly": true, "coarsely": true, "thinly": true, "thickly": true,
+	"freshly": true, "lightly": true, "very": true, "well": true,
+}
+
+var prepWords = map[string]bool{
+	"chopped": true, "diced": true, "minced": true, "sliced": true, "grated": true,
+	"shredded": true, "crushed": true, "peeled": true, "melted": true, "softened": true,
+	"beaten": true, "cubed": true, "julienned": true, "halved": true, "quartered": true,
+	"rinsed": true, "drained": true, "toasted": true, "sifted": true, "packed": true,
+	"trimmed": true, "deseeded": true, "seeded": true, "pitted": true, "zested": true,
+	"mashed": true, "cooked": true, "uncooked": true, "squeezed": true, "torn": true,
+}
+
+var trailingNotes = []string{"to taste", "for garnish", "for serving", "as needed", "optional", "or more"}
+
+var (
+	gluedUnit  = regexp.MustCompile(`^(\d+(?:[.,]\d+)?(?:/\d+)?)([A-Za-z]+\.?)$`)
+	gluedRange = regexp.MustCompile(`^([\d./]+)-([\d./]+)$`)
+	number     = regexp.MustCompile(`^\d+(?:[.,]\d+)?$`)
+	fraction   = regexp.MustCompile(`^(\d+)/(\d+)$`)
+	bullet     = regexp.MustCompile(`^(?:[-*•▢□☐]|\[\s?\]|\d+[.)](?:\s|$))\s*`)
+)
+
+// ParseLines parses a block of text, one ingredient per line, skipping blanks.
+func ParseLines(text string) ([]Ingredient, []error) {
+	var result []Ingredient
+	var errs []error
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		ing, err := ParseLine(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %w", strings.TrimSpace(line), err))
+			continue
+		}
+		result = append(result, ing)
+	}
+	return result, errs
+}
+
+// ParseLine splits a free-text ingredient line such as
+// "2 1/2 cups finely chopped onions" into amount, unit, name and note.
+func ParseLine(line string) (Ingredient, error) {
+	ing := Ingredient{Original: strings.TrimSpace(line)}
+
+	text := normalize(line)
+	if text == "" {
+		return ing, ErrEmptyLine
+	}
+
+	text, notes := splitNotes(text)
+	tokens := tokenize(text)
+
+	pos := 0
+	ing.Amount, pos = readAmount(tokens, pos)
+	if ing.Amount > 0 && pos < len(tokens) && (tokens[pos] == "-" || tokens[pos] == "to") {
+		if upper, next := readAmount(tokens, pos+1); upper > 0 {
+			ing.AmountMax = upper
+			pos = next
+		}
+	}
+
+	if pos < len(tokens) && isParenthetical(tokens[pos]) {
+		ing.Size = strings.Trim(tokens[pos], "()")
+		pos++
+	}
+	if pos < len(tokens) && sizeWords[strings.ToLower(tokens[pos])] && ing.Size == "" {
+		ing.Size = strings.ToLower(tokens[pos])
+		pos++
+	}
+
+	if unit, next := readUnit(tokens, pos); next > pos {
+		if next == len(tokens) {
+			// "2 cups" names nothing, while "2 cans" may be the ingredient itself
+			if _, measured := units.Lookup(unit); measured {
+				return ing, ErrNoIngredient
+			}
+		} else {
+			ing.Unit = unit
+			pos = next
+			if ing.Amount == 0 {
+				ing.Amount = 1
+			}
+		}
+	}
+
+	if pos < len(tokens) && strings.EqualFold(tokens[pos], "of") {
+		pos++
+	}
+
+	var prep, name []string
+	for _, tok := range tokens[pos:] {
+		lower := strings.ToLower(tok)
+		switch {
+		case isParenthetical(tok):
+			notes = append(notes, strings.Trim(tok, "()"))
+		case len(name) == 0 && (prepAdverbs[lower] || prepWords[lower]):
+			prep = append(prep, lower)
+		default:
+			name = append(name, tok)
+		}
+	}
+	if len(prep) > 0 {
+		notes = append([]string{strings.Join(prep, " ")}, notes...)
+	}
+
+	ing.Name = strings.TrimSpace(strings.Join(name, " "))
+	ing.Note = strings.Join(notes, ", ")
+	if ing.Name == "" {
+		return ing, ErrNoIngredient
+	}
+
+	return ing, nil
+}
+
+// normalize expands unicode fractions and dashes and strips list bullets.
+func normalize(line string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(line) {
+		if frac, ok := unicodeFractions[r]; ok {
+			b.WriteString(" " + frac + " ")
+			continue
+		}
+		switch r {
+		case '–', '—', '‒':
+			b.WriteRune('-')
+		case '⁄':
+			b.WriteRune('/')
+		case '\t':
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	text := strings.Join(strings.Fields(b.String()), " ")
+	text = bullet.ReplaceAllString(text, "")
+	return strings.TrimSpace(text)
+}
+
+// splitNotes removes the text after the first top-level comma and any
+// trailing phrases such as "to taste", returning them as notes.
+func splitNotes(text string) (string, []string) {
+	var notes []string
+
+	if i := topLevelComma(text); i >= 0 {
+		if note := strings.TrimSpace(text[i+1:]); note != "" {
+			notes = append(notes, note)
+		}
+		text = strings.TrimSpace(text[:i])
+	}
+
+	for {
+		lower := strings.ToLower(text)
+		found := false
+		for _, phrase := range trailingNotes {
+			if strings.HasSuffix(lower, " "+phrase) {
+				text = strings.TrimSpace(text[:len(text)-len(phrase)])
+				notes = append([]string{phrase}, notes...)
+				found = true
+			}
+		}
+		if !found {
+			break
+		}
+	}
+
+	return text, notes
+}
+
+// topLevelComma finds the first comma outside parentheses that isn't a
+// decimal separator, or -1.
+func topLevelComma(text string) int {
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			decimal := i > 0 && i+1 < len(text) && isDigit(text[i-1]) && isDigit(text[i+1])
+			if depth == 0 && !decimal {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// tokenize splits on spaces, keeping parenthesised groups together and
+// separating numbers glued to units ("200g") or ranges ("2-3").
+func tokenize(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	depth := 0
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case r == '(':
+			if depth == 0 {
+				flush()
+			}
+			depth++
+			current.WriteRune(r)
+		case r == ')':
+			current.WriteRune(r)
+			if depth > 0 {
+				depth--
+			}
+			if depth == 0 {
+				flush()
+			}
+		case r == ' ' && depth == 0:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	var result []string
+	for _, tok := range tokens {
+		if m := gluedRange.FindStringSubmatch(tok); m != nil {
+			result = append(result, m[1], "-", m[2])
+			continue
+		}
+		if m := gluedUnit.FindStringSubmatch(tok); m != nil {
+			if _, ok := lookupUnit(m[2]); ok {
+				result = append(result, m[1], m[2])
+				continue
+			}
+		}
+		result = append(result, tok)
+	}
+	return result
+}
+
+// readAmount reads a whole number, decimal, fraction or mixed number.
+func readAmount(tokens []string, pos int) (float64, int) {
+	if pos >= len(tokens) {
+		return 0, pos
+	}
+
+	lower := strings.ToLower(tokens[pos])
+	if (lower == "a" || lower == "an") && pos+1 < len(tokens) {
+		return 1, pos + 1
+	}
+
+	value, ok := parseNumber(tokens[pos])
+	if !ok {
+		return 0, pos
+	}
+	pos++
+
+	// mixed number: "2 1/2"
+	if pos < len(tokens) && fraction.MatchString(tokens[pos]) && number.MatchString(tokens[pos-1]) {
+		if frac, ok := parseNumber(tokens[pos]); ok {
+			value += frac
+			pos++
+		}
+	}
+
+	return value, pos
+}
+
+func parseNumber(tok string) (float64, bool) {
+	if m := fraction.FindStringSubmatch(tok); m != nil {
+		num, _ := strconv.ParseFloat(m[1], 64)
+		den, _ := strconv.ParseFloat(m[2], 64)
+		if den == 0 {
+			return 0, false
+		}
+		return num / den, true
+	}
+	if number.MatchString(tok) {
+		v, err := strconv.ParseFloat(strings.Replace(tok, ",", ".", 1), 64)
+		return v, err == nil
+	}
+	return 0, false
+}
+
+// readUnit matches a two-word unit ("fl oz") before a one-word one.
+func readUnit(tokens []string, pos int) (string, int) {
+	if pos+1 < len(tokens) {
+		if unit, ok := lookupUnit(tokens[pos] + " " + tokens[pos+1]); ok {
+			return unit, pos + 2
+		}
+	}
+	if pos < len(tokens) {
+		if unit, ok := lookupUnit(tokens[pos]); ok {
+			return unit, pos + 1
+		}
+	}
+	return "", pos
+}
+
+func lookupUnit(word string) (string, bool) {
+	if u, ok := units.Lookup(word); ok {
+		return u.Name, true
+	}
+	if unit, ok := descriptiveUnits[strings.ToLower(strings.TrimSuffix(word, "."))]; ok {
+		return unit, true
+	}
+	return "", false
+}
+
+func isParenthetical(tok string) bool {
+	return strings.HasPrefix(tok, "(") && strings.HasSuffix(tok, ")")
+}
@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	cases := []struct {
+		line      string
+		amount    float64
+		amountMax float64
+		unit      string
+		size      string
+		name      string
+		note      string
+	}{
+		// plain quantities
+		{"2 cups flour", 2, 0, "cup", "", "flour", ""},
+		{"1 cup sugar", 1, 0, "cup", "", "sugar", ""},
+		{"3 eggs", 3, 0, "", "", "eggs", ""},
+		{"200 g butter", 200, 0, "g", "", "butter", ""},
+		{"1.5 kg potatoes", 1.5, 0, "kg", "", "potatoes", ""},
+		{"1,5 kg potatoes", 1.5, 0, "kg", "", "potatoes", ""},
+		{"0.25 tsp salt", 0.25, 0, "tsp", "", "salt", ""},
+		{"10 ml vanilla extract", 10, 0, "ml", "", "vanilla extract", ""},
+
+		// fractions
+		{"1/2 cup milk", 0.5, 0, "cup", "", "milk", ""},
+		{"2 1/2 cups finely chopped onions", 2.5, 0, "cup", "", "onions", "finely chopped"},
+		{"1 3/4 cups water", 1.75, 0, "cup", "", "water", ""},
+		{"3/4 tsp baking soda", 0.75, 0, "tsp", "", "baking soda", ""},
+		{"½ cup milk", 0.5, 0, "cup", "", "milk", ""},
+		{"¾ cup sugar", 0.75, 0, "cup", "", "sugar", ""},
+		{"1½ cups rice", 1.5, 0, "cup", "", "rice", ""},
+		{"1 ½ cups rice", 1.5, 0, "cup", "", "rice", ""},
+		{"2¼ tsp yeast", 2.25, 0, "tsp", "", "yeast", ""},
+		{"⅓ cup olive oil", 1.0 / 3, 0, "cup", "", "olive oil", ""},
+		{"1⁄2 tsp cumin", 0.5, 0, "tsp", "", "cumin", ""},
+
+		// ranges
+		{"2-3 cloves garlic", 2, 3, "clove", "", "garlic", ""},
+		{"2 - 3 tbsp soy sauce", 2, 3, "tbsp", "", "soy sauce", ""},
+		{"2–3 tbsp soy sauce", 2, 3, "tbsp", "", "soy sauce", ""},
+		{"2 to 3 cups broth", 2, 3, "cup", "", "broth", ""},
+		{"1/2-1 tsp chili flakes", 0.5, 1, "tsp", "", "chili flakes", ""},
+		{"4-6 chicken thighs", 4, 6, "", "", "chicken thighs", ""},
+
+		// unit aliases and spellings
+		{"1 tablespoon honey", 1, 0, "tbsp", "", "honey", ""},
+		{"2 Tbsp butter", 2, 0, "tbsp", "", "butter", ""},
+		{"1 T sugar", 1, 0, "tbsp", "", "sugar", ""},
+		{"1 t salt", 1, 0, "tsp", "", "salt", ""},
+		{"2 teaspoons paprika", 2, 0, "tsp", "", "paprika", ""},
+		{"1 lb ground beef", 1, 0, "lb", "", "ground beef", ""},
+		{"2 pounds chicken", 2, 0, "lb", "", "chicken", ""},
+		{"8 oz cream cheese", 8, 0, "oz", "", "cream cheese", ""},
+		{"4 fl oz cream", 4, 0, "fl oz", "", "cream", ""},
+		{"1 litre stock", 1, 0, "l", "", "stock", ""},
+		{"500 grams pasta", 500, 0, "g", "", "pasta", ""},
+		{"200g butter", 200, 0, "g", "", "butter", ""},
+		{"1.5kg lamb shoulder", 1.5, 0, "kg", "", "lamb shoulder", ""},
+		{"250ml milk", 250, 0, "ml", "", "milk", ""},
+		{"2 tsp. vanilla", 2, 0, "tsp", "", "vanilla", ""},
+		{"1 dozen eggs", 1, 0, "dozen", "", "eggs", ""},
+
+		// descriptive units
+		{"3 cloves garlic, minced", 3, 0, "clove", "", "garlic", "minced"},
+		{"a pinch of salt", 1, 0, "pinch", "", "salt", ""},
+		{"pinch of nutmeg", 1, 0, "pinch", "", "nutmeg", ""},
+		{"1 bunch cilantro", 1, 0, "bunch", "", "cilantro", ""},
+		{"2 slices bread", 2, 0, "slice", "", "bread", ""},
+		{"1 stick butter, softened", 1, 0, "stick", "", "butter", "softened"},
+		{"4 sprigs thyme", 4, 0, "sprig", "", "thyme", ""},
+
+		// parenthetical sizes and sizes
+		{"1 (14 oz) can diced tomatoes", 1, 0, "can", "14 oz", "tomatoes", "diced"},
+		{"2 (400 g) tins chickpeas, drained", 2, 0, "tin", "400 g", "chickpeas", "drained"},
+		{"1 (8-ounce) package cream cheese", 1, 0, "package", "8-ounce", "cream cheese", ""},
+		{"2 large eggs", 2, 0, "", "large", "eggs", ""},
+		{"1 medium onion, diced", 1, 0, "", "medium", "onion", "diced"},
+		{"3 small potatoes", 3, 0, "", "small", "potatoes", ""},
+
+		// preparation notes
+		{"1 cup chopped walnuts", 1, 0, "cup", "", "walnuts", "chopped"},
+		{"2 carrots, peeled and grated", 2, 0, "", "", "carrots", "peeled and grated"},
+		{"1 cup packed brown sugar", 1, 0, "cup", "", "brown sugar", "packed"},
+		{"100 g butter (melted)", 100, 0, "g", "", "butter", "melted"},
+		{"3 tbsp freshly squeezed lemon juice", 3, 0, "tbsp", "", "lemon juice", "freshly squeezed"},
+		{"2 cups cooked rice", 2, 0, "cup", "", "rice", "cooked"},
+		{"1 onion, thinly sliced", 1, 0, "", "", "onion", "thinly sliced"},
+
+		// no quantity and trailing notes
+		{"Salt to taste", 0, 0, "", "", "Salt", "to taste"},
+		{"salt and pepper, to taste", 0, 0, "", "", "salt and pepper", "to taste"},
+		{"Fresh parsley for garnish", 0, 0, "", "", "Fresh parsley", "for garnish"},
+		{"1 tbsp sesame seeds, optional", 1, 0, "tbsp", "", "sesame seeds", "optional"},
+		{"Olive oil as needed", 0, 0, "", "", "Olive oil", "as needed"},
+		{"vegetable oil", 0, 0, "", "", "vegetable oil", ""},
+
+		// "of" and articles
+		{"2 cups of flour", 2, 0, "cup", "", "flour", ""},
+		{"a cup of coffee", 1, 0, "cup", "", "coffee", ""},
+		{"an onion", 1, 0, "", "", "onion", ""},
+
+		// list formatting pasted from web pages
+		{"- 2 cups flour", 2, 0, "cup", "", "flour", ""},
+		{"• 1 tsp salt", 1, 0, "tsp", "", "salt", ""},
+		{"* 3 eggs", 3, 0, "", "", "eggs", ""},
+		{"▢ 1 cup milk", 1, 0, "cup", "", "milk", ""},
+		{"1. 2 cups flour", 2, 0, "cup", "", "flour", ""},
+		{"   2   cups   flour   ", 2, 0, "cup", "", "flour", ""},
+		{"2\tcups\tflour", 2, 0, "cup", "", "flour", ""},
+
+		// unit words that are also the whole ingredient stay as the name
+		{"2 cans", 2, 0, "", "", "cans", ""},
+	}
+
+	for _, c := range cases {
+		got, err := ParseLine(c.line)
+		if err != nil {
+			t.Errorf("ParseLine(%q) returned error %v", c.line, err)
+			continue
+		}
+		if math.Abs(got.Amount-c.amount) > 1e-9 || math.Abs(got.AmountMax-c.amountMax) > 1e-9 ||
+			got.Unit != c.unit || got.Size != c.size || got.Name != c.name || got.Note != c.note {
+			t.Errorf("ParseLine(%q)\n got  amount=%v max=%v unit=%q size=%q name=%q note=%q\n want amount=%v max=%v unit=%q size=%q name=%q note=%q",
+				c.line, got.Amount, got.AmountMax, got.Unit, got.Size, got.Name, got.Note,
+				c.amount, c.amountMax, c.unit, c.size, c.name, c.note)
+		}
+	}
+}
+
+func TestParseLine_Errors(t *testing.T) {
+	cases := []struct {
+		line string
+		err  error
+	}{
+		{"", ErrEmptyLine},
+		{"   ", ErrEmptyLine},
+		{"- ", ErrEmptyLine},
+		{"2 cups", ErrNoIngredient},
+		{"3", ErrNoIngredient},
+	}
+	for _, c := range cases {
+		if _, err := ParseLine(c.line); err != c.err {
+			t.Errorf("ParseLine(%q) error = %v, want %v", c.line, err, c.err)
+		}
+	}
+}
+
+func TestParseLine_KeepsOriginal(t *testing.T) {
+	got, _ := ParseLine("  1 ½ cups milk ")
+	if got.Original != "1 ½ cups milk" {
+		t.Errorf("expected trimmed original line, got %q", got.Original)
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	text := "2 cups flour\n\n1 tsp salt\n   \n3 eggs\n2 cups\n"
+	got, errs := ParseLines(text)
+	if len(got) != 3 || len(errs) != 1 {
+		t.Fatalf("expected 3 parsed lines and 1 error, got %d and %d", len(got), len(errs))
+	}
+	if got[2].Name != "eggs" {
+		t.Errorf("expected order to be kept, got %+v", got)
+	}
+	if !errors.Is(errs[0], ErrNoIngredient) || !strings.Contains(errs[0].Error(), `"2 cups"`) {
+		t.Errorf("expected error to name the line, got %v", errs[0])
+	}
+}
@@ -0,0 +1,167 @@
+package parser
+
+import "strings"
+
+// CleanName trims a name and collapses the spaces inside it, keeping its
+// case.
+func CleanName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// NameForms returns the lower-cased ingredient name followed by the forms
+// its last word could take in the singular: "Cherry Tomatoes" gives
+// "cherry tomatoes", "cherry tomatoe" and "cherry tomato". Guessing several
+// forms, rather than one, lets "cookies" reach "cookie" as well as
+// "berries" reach "berry".
+func NameForms(name string) []string {
+	name = strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	forms := []string{name}
+	if name == "" {
+		return forms
+	}
+
+	head, last := "", name
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		head, last = name[:i+1], name[i+1:]
+	}
+	// "hummus", "couscous" and "glass" aren't plurals
+	if len(last) < 3 || !strings.HasSuffix(last, "s") || strings.HasSuffix(last, "ss") || strings.HasSuffix(last, "us") {
+		return forms
+	}
+
+	stem := strings.TrimSuffix(last, "s")
+	forms = append(forms, head+stem)
+	if strings.HasSuffix(stem, "e") {
+		forms = append(forms, head+strings.TrimSuffix(stem, "e"))
+	}
+	if strings.HasSuffix(last, "ies") {
+		forms = append(forms, head+strings.TrimSuffix(last, "ies")+"y")
+	}
+	if strings.HasSuffix(last, "ves") {
+		forms = append(forms, head+strings.TrimSuffix(last, "ves")+"f", head+strings.TrimSuffix(last, "ves")+"fe")
+	}
+	return forms
+}
+
+// SameName reports whether two ingredient names are the same ignoring case,
+// spacing and a plural last word.
+func SameName(a, b string) bool {
+	aForms, bForms := NameForms(a), NameForms(b)
+	for _, f := range aForms {
+		if f == bForms[0] {
+			return true
+		}
+	}
+	for _, f := range bForms {
+		if f == aForms[0] {
+			return true
+		}
+	}
+	return false
+}
+
+// NameCandidates returns the lower-cased forms a catalog entry for name
+// could have, singular or plural, for looking it up.
+func NameCandidates(name string) []string {
+	forms := NameForms(name)
+	base := forms[0]
+	if base == "" {
+		return forms
+	}
+
+	head, last := "", base
+	if i := strings.LastIndex(base, " "); i >= 0 {
+		head, last = base[:i+1], base[i+1:]
+	}
+	plurals := []string{head + last + "s", head + last + "es"}
+	if strings.HasSuffix(last, "y") && len(last) > 1 && !strings.ContainsAny(last[len(last)-2:len(last)-1], "aeiou") {
+		plurals = append(plurals, head+strings.TrimSuffix(last, "y")+"ies")
+	}
+	if strings.HasSuffix(last, "fe") {
+		plurals = append(plurals, head+strings.TrimSuffix(last, "fe")+"ves")
+	} else if strings.HasSuffix(last, "f") {
+		plurals = append(plurals, head+strings.TrimSuffix(last, "f")+"ves")
+	}
+
+	seen := make(map[string]bool)
+	var candidates []string
+	for _, c := range append(forms, plurals...) {
+		if !seen[c] {
+			seen[c] = true
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates
+}
+
+// Similarity scores how alike two ingredient names are, from 0 to 1: 1 when
+// SameName holds, otherwise by edit distance between their closest forms,
+// so "tomatoe" and "tomato" score high and "tomato" and "potato" don't.
+func Similarity(a, b string) float64 {
+	if SameName(a, b) {
+		return 1
+	}
+	best := 0.0
+	for _, x := range NameForms(a) {
+		for _, y := range NameForms(b) {
+			longest := max(len([]rune(x)), len([]rune(y)))
+			if longest == 0 {
+				continue
+			}
+			score := 1 - float64(levenshtein(x, y))/float64(longest)
+			best = max(best, score)
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Trigrams returns the set of three-letter runs in a lower-cased name, each
+// word padded as pg_trgm does so that word starts count for more.
+func Trigrams(name string) map[string]bool {
+	grams := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(name)) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			grams[string(padded[i:i+3])] = true
+		}
+	}
+	return grams
+}
+
+// TrigramSimilarity is the share of trigrams two names have in common, from
+// 0 to 1. Unlike Similarity it tolerates typos anywhere and partial words,
+// which suits text typed so far.
+func TrigramSimilarity(a, b string) float64 {
+	ga, gb := Trigrams(a), Trigrams(b)
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+	shared := 0
+	for g := range ga {
+		if gb[g] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ga)+len(gb)-shared)
+}
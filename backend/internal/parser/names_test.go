@@ -0,0 +1,79 @@
+package parser
+
+import "testing"
+
+func TestSameName(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"Tomatoes", "tomato", true},
+		{"tomato", "Tomatoes", true},
+		{"Blueberries", "blueberry", true},
+		{"cookies", "Cookie", true},
+		{"Bay Leaves", "bay leaf", true},
+		{"cherry  tomatoes", "Cherry Tomato", true},
+		{"Chickpeas", "chickpea", true},
+		{"Peaches", "peach", true},
+		{"Couscous", "couscou", false},
+		{"Hummus", "hummu", false},
+		{"Egg", "Eggplant", false},
+		{"Onion", "Green Onion", false},
+	}
+	for _, c := range cases {
+		if got := SameName(c.a, c.b); got != c.want {
+			t.Errorf("SameName(%q, %q) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestNameCandidates(t *testing.T) {
+	got := NameCandidates("  Roma   Tomato ")
+	want := map[string]bool{"roma tomato": true, "roma tomatos": true, "roma tomatoes": true}
+	if len(got) != len(want) {
+		t.Fatalf("NameCandidates = %v", got)
+	}
+	for _, c := range got {
+		if !want[c] {
+			t.Errorf("unexpected candidate %q", c)
+		}
+	}
+
+	for _, c := range [][2]string{{"Blueberry", "blueberries"}, {"leaf", "leaves"}, {"Mushrooms", "mushroom"}} {
+		found := false
+		for _, cand := range NameCandidates(c[0]) {
+			found = found || cand == c[1]
+		}
+		if !found {
+			t.Errorf("NameCandidates(%q) = %v, missing %q", c[0], NameCandidates(c[0]), c[1])
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if s := Similarity("Tomatoes ", "tomato"); s != 1 {
+		t.Errorf("plural scored %v", s)
+	}
+	if s := Similarity("Parmesan", "Parmesean"); s < 0.8 {
+		t.Errorf("misspelling scored %v", s)
+	}
+	if s := Similarity("Tomato", "Potato"); s >= 0.8 {
+		t.Errorf("different ingredients scored %v", s)
+	}
+}
+
+func TestTrigramSimilarity(t *testing.T) {
+	if got := len(Trigrams("Egg")); got != 4 {
+		t.Errorf("Trigrams(egg) has %d, want 4", got)
+	}
+	if s := TrigramSimilarity("tomato", "Tomato"); s != 1 {
+		t.Errorf("same name scored %v", s)
+	}
+	typo, other := TrigramSimilarity("tomtao", "tomato"), TrigramSimilarity("tomtao", "potato")
+	if typo <= other || typo < 0.2 {
+		t.Errorf("typo scored %v against %v for a different word", typo, other)
+	}
+	if s := TrigramSimilarity("", "tomato"); s != 0 {
+		t.Errorf("empty name scored %v", s)
+	}
+}
@@ -0,0 +1,103 @@
+// Package pricing estimates what an amount of an ingredient costs from a
+// price recorded for some other amount, such as "2.49 per kg".
+package pricing
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/nutrition"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/units"
+)
+
+var ErrIncompatibleUnits = errors.New("no way to convert between the recipe and price units")
+var ErrInvalidPrice = errors.New("price quantity must be positive")
+
+// Price is Amount for Quantity of Unit. An empty unit means pieces.
+type Price struct {
+	Amount   float64
+	Quantity float64
+	Unit     string
+}
+
+// measure puts a quantity in the base unit of its dimension. Units the
+// catalog doesn't know, such as "clove" or "bunch", only compare with the
+// same unit, so they get a dimension of their own.
+func measure(quantity float64, unit string) (float64, units.Dimension) {
+	if strings.TrimSpace(unit) == "" {
+		return quantity, units.Count
+	}
+	if base, u, err := units.ToBase(quantity, unit); err == nil {
+		return base, u.Dimension
+	}
+	return quantity, units.Dimension("unit:" + singular(units.Normalize(unit)))
+}
+
+// singular folds the plural of an unknown unit, "cloves" or "bunches".
+func singular(unit string) string {
+	for _, suffix := range []string{"ches", "shes", "xes"} {
+		if strings.HasSuffix(unit, suffix) {
+			return strings.TrimSuffix(unit, "es")
+		}
+	}
+	if strings.HasSuffix(unit, "s") && !strings.HasSuffix(unit, "ss") {
+		return strings.TrimSuffix(unit, "s")
+	}
+	return unit
+}
+
+// Cost prices quantity of unit, converted into the price's unit with
+// Convert, so "2 cups" of flour can be priced per kg.
+func Cost(quantity float64, unit string, price Price, density, pieceWeight float64) (float64, error) {
+	if price.Quantity <= 0 {
+		return 0, ErrInvalidPrice
+	}
+	if quantity == 0 {
+		return 0, nil
+	}
+
+	have, err := Convert(quantity, unit, price.Unit, density, pieceWeight)
+	if err != nil {
+		return 0, err
+	}
+	return price.Amount * have / price.Quantity, nil
+}
+
+// Convert expresses quantity of one unit in another. Amounts in the same
+// dimension convert directly; otherwise both sides are weighed with the
+// ingredient's density (g/ml) and piece weight (g), 0 when unknown.
+func Convert(quantity float64, from, to string, density, pieceWeight float64) (float64, error) {
+	have, haveDim := measure(quantity, from)
+	per, perDim := measure(1, to)
+	if haveDim == perDim {
+		return have / per, nil
+	}
+
+	// weighing pieces on both sides would price a clove as a head of garlic
+	if !weighable(haveDim) && !weighable(perDim) {
+		return 0, ErrIncompatibleUnits
+	}
+
+	haveGrams, err := nutrition.Grams(quantity, from, density, pieceWeight)
+	if err != nil {
+		return 0, errors.Join(ErrIncompatibleUnits, err)
+	}
+	perGrams, err := nutrition.Grams(1, to, density, pieceWeight)
+	if err != nil {
+		return 0, errors.Join(ErrIncompatibleUnits, err)
+	}
+	if perGrams <= 0 {
+		return 0, ErrIncompatibleUnits
+	}
+	return haveGrams / perGrams, nil
+}
+
+func weighable(dim units.Dimension) bool {
+	return dim == units.Mass || dim == units.Volume
+}
+
+// Round rounds to cents for display.
+func Round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
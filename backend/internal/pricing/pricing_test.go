@@ -0,0 +1,59 @@
+package pricing
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCost(t *testing.T) {
+	cases := []struct {
+		quantity    float64
+		unit        string
+		price       Price
+		density     float64
+		pieceWeight float64
+		want        float64
+		err         error
+	}{
+		{500, "g", Price{2.49, 1, "kg"}, 0, 0, 1.245, nil},
+		{2, "lb", Price{2.49, 1, "kg"}, 0, 0, 2.259, nil},
+		{3, "", Price{4.20, 1, "dozen"}, 0, 0, 1.05, nil},
+		{2, "cups", Price{1.80, 1, "kg"}, 0.53, 0, 0.4515, nil},
+		{2, "cups", Price{1.80, 1, "kg"}, 0, 0, 0, ErrIncompatibleUnits},
+		{4, "", Price{3.00, 1, "kg"}, 0, 150, 1.80, nil},
+		{3, "cloves", Price{0.50, 1, "head"}, 0, 4, 0, ErrIncompatibleUnits},
+		{3, "cloves", Price{0.10, 1, "clove"}, 0, 0, 0.30, nil},
+		{2, "bunches", Price{1.50, 1, "bunch"}, 0, 0, 3.00, nil},
+		{1, "l", Price{1.10, 2, "pints"}, 0, 0, 1.162, nil},
+		{1, "kg", Price{1, 0, "kg"}, 0, 0, 0, ErrInvalidPrice},
+	}
+	for _, c := range cases {
+		got, err := Cost(c.quantity, c.unit, c.price, c.density, c.pieceWeight)
+		if !errors.Is(err, c.err) || math.Abs(got-c.want) > 0.001 {
+			t.Errorf("Cost(%v %q at %+v) = %v, %v; want %v, %v", c.quantity, c.unit, c.price, got, err, c.want, c.err)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		quantity float64
+		from, to string
+		density  float64
+		want     float64
+		err      error
+	}{
+		{1500, "g", "kg", 0, 1.5, nil},
+		{1, "dozen", "", 0, 12, nil},
+		{250, "g", "cup", 0.53, 1.994, nil},
+		{4, "cloves", "clove", 0, 4, nil},
+		{1, "cup", "g", 0, 0, ErrIncompatibleUnits},
+	}
+	for _, c := range cases {
+		got, err := Convert(c.quantity, c.from, c.to, c.density, 0)
+		if !errors.Is(err, c.err) || math.Abs(got-c.want) > 0.001 {
+			t.Errorf("Convert(%v %q to %q) = %v, %v; want %v, %v", c.quantity, c.from, c.to, got, err, c.want, c.err)
+		}
+	}
+}
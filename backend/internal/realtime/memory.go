@@ -0,0 +1,80 @@
+package realtime
+
+import "sync"
+
+// subscriberBuffer is how many events a subscriber can fall behind by
+// before it starts missing them.
+const subscriberBuffer = 32
+
+// Memory is a hub for a single process.
+type Memory struct {
+	mu     sync.Mutex
+	lists  map[uint]map[chan Event]struct{}
+	closed bool
+}
+
+func NewMemory() *Memory {
+	return &Memory{lists: map[uint]map[chan Event]struct{}{}}
+}
+
+func (m *Memory) Publish(event Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for ch := range m.lists[event.ListID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Subscribe(listID uint) (<-chan Event, func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	if m.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if m.lists[listID] == nil {
+		m.lists[listID] = map[chan Event]struct{}{}
+	}
+	m.lists[listID][ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { m.unsubscribe(listID, ch) })
+	}
+}
+
+func (m *Memory) unsubscribe(listID uint, ch chan Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.lists[listID][ch]; !ok {
+		return // already closed with the hub
+	}
+	delete(m.lists[listID], ch)
+	if len(m.lists[listID]) == 0 {
+		delete(m.lists, listID)
+	}
+	close(ch)
+}
+
+// Close ends every subscription.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, subscribers := range m.lists {
+		for ch := range subscribers {
+			close(ch)
+		}
+	}
+	m.lists = map[uint]map[chan Event]struct{}{}
+	m.closed = true
+	return nil
+}
@@ -0,0 +1,138 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// notifyChannel is the Postgres channel every replica listens on.
+const notifyChannel = "shopping_list_events"
+
+// maxPayload keeps NOTIFY payloads under Postgres' 8000 byte limit.
+const maxPayload = 7900
+
+// Postgres shares events between replicas through LISTEN/NOTIFY. Each
+// replica holds one listening connection and hands what arrives to its own
+// subscribers, including the events it published itself. Events sent while
+// a replica is reconnecting are lost to its subscribers.
+type Postgres struct {
+	pool   *pgxpool.Pool
+	local  *Memory
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+func NewPostgres(dsn string) (*Postgres, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	conn, err := listen(ctx, pool)
+	if err != nil {
+		cancel()
+		pool.Close()
+		return nil, err
+	}
+
+	p := &Postgres{pool: pool, local: NewMemory(), cancel: cancel}
+	p.done.Add(1)
+	go p.run(ctx, conn)
+	return p, nil
+}
+
+func listen(ctx context.Context, pool *pgxpool.Pool) (*pgxpool.Conn, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// run forwards notifications to local subscribers until the hub is
+// closed, reconnecting when the listening connection drops.
+func (p *Postgres) run(ctx context.Context, conn *pgxpool.Conn) {
+	defer p.done.Done()
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err == nil {
+			var event Event
+			if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+				log.Printf("realtime: dropping malformed notification: %v", err)
+				continue
+			}
+			p.local.Publish(event)
+			continue
+		}
+
+		// the connection may be broken, so don't put it back in the pool
+		conn.Hijack().Close(context.Background())
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("realtime: listening connection lost: %v", err)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			if conn, err = listen(ctx, p.pool); err == nil {
+				break
+			}
+			log.Printf("realtime: reconnecting: %v", err)
+		}
+	}
+}
+
+func (p *Postgres) Publish(event Event) error {
+	payload, err := notifyPayload(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = p.pool.Exec(ctx, "SELECT pg_notify($1, $2)", notifyChannel, payload)
+	return err
+}
+
+// notifyPayload encodes an event for NOTIFY, leaving out its data when the
+// whole would be too large. Clients reload the list for such events.
+func notifyPayload(event Event) (string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	if len(payload) > maxPayload {
+		event.Data = nil
+		if payload, err = json.Marshal(event); err != nil {
+			return "", err
+		}
+	}
+	return string(payload), nil
+}
+
+func (p *Postgres) Subscribe(listID uint) (<-chan Event, func()) {
+	return p.local.Subscribe(listID)
+}
+
+// Close stops listening, ends every subscription and closes the pool.
+func (p *Postgres) Close() error {
+	p.cancel()
+	p.done.Wait()
+	p.pool.Close()
+	return p.local.Close()
+}
@@ -0,0 +1,72 @@
+// Package realtime fans shopping list changes out to the clients watching
+// the list, so people shopping together see each other's checkmarks.
+package realtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownDriver = errors.New("unknown realtime driver")
+
+// Event types sent to subscribers.
+const (
+	ItemChecked   = "item-checked"
+	ItemAdded     = "item-added"
+	ItemUpdated   = "item-updated"
+	ItemRemoved   = "item-removed"
+	ListRefreshed = "list-refreshed"
+	ListDeleted   = "list-deleted"
+)
+
+// Event is one change to a shopping list. Data carries the changed item or
+// the refresh summary; it can be missing, in which case clients should
+// reload the list.
+type Event struct {
+	Type   string          `json:"type"`
+	ListID uint            `json:"list_id"`
+	ItemID uint            `json:"item_id,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// NewEvent builds an event, encoding data as JSON. Data that can't be
+// encoded is left out.
+func NewEvent(eventType string, listID, itemID uint, data any) Event {
+	event := Event{Type: eventType, ListID: listID, ItemID: itemID}
+	if data != nil {
+		if raw, err := json.Marshal(data); err == nil {
+			event.Data = raw
+		}
+	}
+	return event
+}
+
+// Hub delivers events to the subscribers of a list.
+type Hub interface {
+	// Publish sends the event to every subscriber of its list, on every
+	// replica sharing the hub.
+	Publish(event Event) error
+	// Subscribe returns the list's events and a function that stops them and
+	// closes the channel. A subscriber that falls behind misses events
+	// rather than holding up the others.
+	Subscribe(listID uint) (<-chan Event, func())
+	Close() error
+}
+
+type Config struct {
+	Driver string // memory (default) or postgres
+	DSN    string // postgres connection string
+}
+
+func New(cfg Config) (Hub, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", "memory":
+		return NewMemory(), nil
+	case "postgres":
+		return NewPostgres(cfg.DSN)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownDriver, cfg.Driver)
+	}
+}
@@ -0,0 +1,109 @@
+package realtime
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-ch:
+		return event
+	default:
+		t.Fatal("no event waiting")
+		return Event{}
+	}
+}
+
+func TestMemoryFansOutPerList(t *testing.T) {
+	hub := NewMemory()
+	first, stopFirst := hub.Subscribe(1)
+	second, stopSecond := hub.Subscribe(1)
+	other, stopOther := hub.Subscribe(2)
+	defer stopSecond()
+	defer stopOther()
+
+	hub.Publish(NewEvent(ItemChecked, 1, 7, map[string]bool{"checked": true}))
+	for _, ch := range []<-chan Event{first, second} {
+		event := receive(t, ch)
+		if event.Type != ItemChecked || event.ItemID != 7 || string(event.Data) != `{"checked":true}` {
+			t.Errorf("unexpected event %+v", event)
+		}
+	}
+	if len(other) != 0 {
+		t.Error("event leaked to another list")
+	}
+
+	stopFirst()
+	stopFirst()
+	if _, ok := <-first; ok {
+		t.Error("expected the channel to be closed")
+	}
+	hub.Publish(NewEvent(ItemRemoved, 1, 7, nil))
+	if event := receive(t, second); event.Type != ItemRemoved || event.Data != nil {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
+
+func TestMemoryDropsForSlowSubscribers(t *testing.T) {
+	hub := NewMemory()
+	slow, stop := hub.Subscribe(1)
+	defer stop()
+
+	for i := 0; i < subscriberBuffer+10; i++ {
+		hub.Publish(NewEvent(ItemAdded, 1, uint(i), nil))
+	}
+	if len(slow) != subscriberBuffer {
+		t.Errorf("expected %d buffered events, got %d", subscriberBuffer, len(slow))
+	}
+	if event := receive(t, slow); event.ItemID != 0 {
+		t.Errorf("expected the oldest event first, got %+v", event)
+	}
+}
+
+func TestMemoryClose(t *testing.T) {
+	hub := NewMemory()
+	ch, stop := hub.Subscribe(1)
+	hub.Close()
+	if _, ok := <-ch; ok {
+		t.Error("expected Close to end subscriptions")
+	}
+	stop()
+
+	late, _ := hub.Subscribe(1)
+	if _, ok := <-late; ok {
+		t.Error("expected a closed channel after Close")
+	}
+}
+
+func TestNotifyPayload(t *testing.T) {
+	small, err := notifyPayload(NewEvent(ItemChecked, 3, 4, map[string]bool{"checked": false}))
+	if err != nil || small != `{"type":"item-checked","list_id":3,"item_id":4,"data":{"checked":false}}` {
+		t.Errorf("unexpected payload %s (%v)", small, err)
+	}
+
+	big, err := notifyPayload(NewEvent(ListRefreshed, 3, 0, map[string]string{"note": strings.Repeat("x", maxPayload)}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(big), &event); err != nil || event.Data != nil || event.Type != ListRefreshed {
+		t.Errorf("expected the data to be dropped, got %s", big)
+	}
+}
+
+func TestNew(t *testing.T) {
+	hub, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := hub.(*Memory); !ok {
+		t.Errorf("expected the memory hub by default, got %T", hub)
+	}
+	if _, err := New(Config{Driver: "redis"}); !errors.Is(err, ErrUnknownDriver) {
+		t.Errorf("expected ErrUnknownDriver, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type CookLogRepository interface {
+	Create(event *models.CookEvent) error
+	FindByID(id uint) (*models.CookEvent, error)
+	// FindByUser lists the user's cook events newest first, optionally for
+	// one recipe (0 for all).
+	FindByUser(userID uint, recipeID uint) ([]models.CookEvent, error)
+	FindByMealPlan(mealPlanID uint) (*models.CookEvent, error)
+	// RecipeStats counts the recipe's cook events and finds the latest date,
+	// nil when it was never cooked.
+	RecipeStats(recipeID uint) (int64, *time.Time, error)
+	Delete(event *models.CookEvent) error
+}
+
+type cookLogRepository struct {
+	DB *gorm.DB
+}
+
+func NewCookLogRepository(db *gorm.DB) CookLogRepository {
+	return &cookLogRepository{DB: db}
+}
+
+func (r *cookLogRepository) Create(event *models.CookEvent) error {
+	return r.DB.Omit("Recipe").Create(event).Error
+}
+
+func (r *cookLogRepository) FindByID(id uint) (*models.CookEvent, error) {
+	var event models.CookEvent
+	err := r.DB.Preload("Recipe").First(&event, id).Error
+	return &event, err
+}
+
+func (r *cookLogRepository) FindByUser(userID uint, recipeID uint) ([]models.CookEvent, error) {
+	var events []models.CookEvent
+
+	query := r.DB.Preload("Recipe").Where("user_id = ?", userID)
+	if recipeID != 0 {
+		query = query.Where("recipe_id = ?", recipeID)
+	}
+
+	err := query.Order("date desc").Order("id desc").Find(&events).Error
+	return events, err
+}
+
+func (r *cookLogRepository) FindByMealPlan(mealPlanID uint) (*models.CookEvent, error) {
+	var event models.CookEvent
+	err := r.DB.Where("meal_plan_id = ?", mealPlanID).First(&event).Error
+	return &event, err
+}
+
+func (r *cookLogRepository) RecipeStats(recipeID uint) (int64, *time.Time, error) {
+	var count int64
+	err := r.DB.Model(&models.CookEvent{}).Where("recipe_id = ?", recipeID).Count(&count).Error
+	if err != nil || count == 0 {
+		return count, nil, err
+	}
+
+	// read as a column rather than MAX(date), which SQLite hands back as text
+	var latest models.CookEvent
+	err = r.DB.Select("date").Where("recipe_id = ?", recipeID).Order("date desc").First(&latest).Error
+	return count, &latest.Date, err
+}
+
+func (r *cookLogRepository) Delete(event *models.CookEvent) error {
+	return r.DB.Delete(event).Error
+}
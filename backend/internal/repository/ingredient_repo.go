@@ -1,14 +1,54 @@
 package repository
 
 import (
+	"errors"
+	"sort"
+	"strings"
+
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/parser"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IngredientRepository interface {
 	Create(ingredient *models.Ingredient) error
 	FindAll() ([]models.Ingredient, error)
 	FindByID(id uint) (*models.Ingredient, error)
+	Update(ingredient *models.Ingredient) error
+	// SaveAll creates or updates the ingredients in one transaction.
+	SaveAll(ingredients []*models.Ingredient) error
+	// FindByName finds the ingredient a name refers to, ignoring case,
+	// spacing and plurals and following aliases. It returns
+	// gorm.ErrRecordNotFound when there is none.
+	FindByName(name string) (*models.Ingredient, error)
+	// UsageCounts returns how many recipe lines use each ingredient.
+	UsageCounts() (map[uint]int64, error)
+	// UserUsageCounts returns how many of the user's recipe lines use each
+	// of the given ingredients.
+	UserUsageCounts(userID uint, ids []uint) (map[uint]int64, error)
+	// Search returns up to limit ingredients whose name or an alias contains
+	// the text or one of its trigrams, shortest names first, for the caller
+	// to rank.
+	Search(text string, limit int) ([]models.Ingredient, error)
+
+	CreateAlias(alias *models.IngredientAlias) error
+	FindAliasByID(id uint) (*models.IngredientAlias, error)
+	DeleteAlias(id uint) error
+
+	// Merge repoints everything that refers to the duplicates at canonical,
+	// keeps their names as aliases, saves canonical and deletes the
+	// duplicates, all in one transaction.
+	Merge(canonical *models.Ingredient, duplicates []models.Ingredient) (*MergeResult, error)
+}
+
+// MergeResult counts the rows Merge moved to the canonical ingredient.
+type MergeResult struct {
+	RecipeIngredients int64
+	ShoppingListItems int64
+	Prices            int64
+	PantryItems       int64
+	Aliases           int64
 }
 
 type ingredientRepository struct {
@@ -25,12 +65,236 @@ func (r *ingredientRepository) Create(ingredient *models.Ingredient) error {
 
 func (r *ingredientRepository) FindAll() ([]models.Ingredient, error) {
 	var ingredients []models.Ingredient
-	err := r.db.Find(&ingredients).Error
+	err := r.db.Preload("Aliases", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name")
+	}).Find(&ingredients).Error
 	return ingredients, err
 }
 
 func (r *ingredientRepository) FindByID(id uint) (*models.Ingredient, error) {
 	var ingredient models.Ingredient
-	err := r.db.First(&ingredient, id).Error
+	err := r.db.Preload("Aliases", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name")
+	}).First(&ingredient, id).Error
 	return &ingredient, err
 }
+
+func (r *ingredientRepository) Update(ingredient *models.Ingredient) error {
+	return r.db.Omit(clause.Associations).Save(ingredient).Error
+}
+
+func (r *ingredientRepository) SaveAll(ingredients []*models.Ingredient) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, ingredient := range ingredients {
+			if err := tx.Save(ingredient).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *ingredientRepository) FindByName(name string) (*models.Ingredient, error) {
+	candidates := parser.NameCandidates(name)
+
+	var alias models.IngredientAlias
+	err := r.db.Preload("Ingredient").Where("name IN ?", candidates).First(&alias).Error
+	if err == nil {
+		return &alias.Ingredient, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var matches []models.Ingredient
+	if err := r.db.Where("LOWER(name) IN ?", candidates).Order("id").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	// the name as given beats a singular or plural of it
+	for i := range matches {
+		if parser.NameForms(matches[i].Name)[0] == candidates[0] {
+			return &matches[i], nil
+		}
+	}
+	for i := range matches {
+		if parser.SameName(matches[i].Name, name) {
+			return &matches[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *ingredientRepository) UsageCounts() (map[uint]int64, error) {
+	var rows []struct {
+		IngredientID uint
+		Uses         int64
+	}
+	err := r.db.Model(&models.RecipeIngredient{}).
+		Select("ingredient_id, COUNT(*) AS uses").
+		Group("ingredient_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.IngredientID] = row.Uses
+	}
+	return counts, nil
+}
+
+func (r *ingredientRepository) UserUsageCounts(userID uint, ids []uint) (map[uint]int64, error) {
+	var rows []struct {
+		IngredientID uint
+		Uses         int64
+	}
+	err := r.db.Model(&models.RecipeIngredient{}).
+		Select("recipe_ingredients.ingredient_id, COUNT(*) AS uses").
+		Joins("JOIN recipes ON recipes.id = recipe_ingredients.recipe_id").
+		Where("recipes.user_id = ? AND recipe_ingredients.ingredient_id IN ?", userID, ids).
+		Group("recipe_ingredients.ingredient_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.IngredientID] = row.Uses
+	}
+	return counts, nil
+}
+
+func (r *ingredientRepository) Search(text string, limit int) ([]models.Ingredient, error) {
+	// trigrams with no padding space can be matched with a plain LIKE, so
+	// this narrows the catalog the same way on Postgres and SQLite
+	patterns := []string{likePattern(text)}
+	var grams []string
+	for g := range parser.Trigrams(text) {
+		if !strings.Contains(g, " ") {
+			grams = append(grams, g)
+		}
+	}
+	sort.Strings(grams)
+	for _, g := range grams {
+		patterns = append(patterns, likePattern(g))
+	}
+
+	conds := make([]string, len(patterns))
+	args := make([]any, len(patterns))
+	for i, p := range patterns {
+		conds[i] = "LOWER(name) LIKE ?"
+		args[i] = p
+	}
+	match := strings.Join(conds, " OR ")
+
+	aliases := func(query string, arg any) *gorm.DB {
+		return r.db.Model(&models.IngredientAlias{}).Select("ingredient_id").Where(query, arg)
+	}
+	aliased := r.db.Model(&models.IngredientAlias{}).Select("ingredient_id").Where(match, args...)
+
+	// exact, prefix and substring matches come before the trigram ones, so
+	// rows sharing a common trigram can't crowd them out of the limit
+	text = strings.ToLower(strings.TrimSpace(text))
+	prefix := strings.TrimPrefix(patterns[0], "%")
+	kind := clause.Expr{
+		SQL: `CASE WHEN LOWER(name) = ? OR id IN (?) THEN 0
+			WHEN LOWER(name) LIKE ? OR id IN (?) THEN 1
+			WHEN LOWER(name) LIKE ? OR id IN (?) THEN 2
+			ELSE 3 END, LENGTH(name), id`,
+		Vars: []any{
+			text, aliases("LOWER(name) = ?", text),
+			prefix, aliases("LOWER(name) LIKE ?", prefix),
+			patterns[0], aliases("LOWER(name) LIKE ?", patterns[0]),
+		},
+		WithoutParentheses: true,
+	}
+
+	var ingredients []models.Ingredient
+	err := r.db.Preload("Aliases").
+		Where(match, args...).
+		Or("id IN (?)", aliased).
+		Order(clause.OrderBy{Expression: kind}).
+		Limit(limit).
+		Find(&ingredients).Error
+	return ingredients, err
+}
+
+func (r *ingredientRepository) CreateAlias(alias *models.IngredientAlias) error {
+	return r.db.Omit("Ingredient").Create(alias).Error
+}
+
+func (r *ingredientRepository) FindAliasByID(id uint) (*models.IngredientAlias, error) {
+	var alias models.IngredientAlias
+	err := r.db.First(&alias, id).Error
+	return &alias, err
+}
+
+func (r *ingredientRepository) DeleteAlias(id uint) error {
+	return r.db.Delete(&models.IngredientAlias{}, id).Error
+}
+
+func (r *ingredientRepository) Merge(canonical *models.Ingredient, duplicates []models.Ingredient) (*MergeResult, error) {
+	ids := make([]uint, len(duplicates))
+	for i, d := range duplicates {
+		ids[i] = d.ID
+	}
+
+	result := &MergeResult{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		repoint := []struct {
+			model any
+			count *int64
+		}{
+			{&models.RecipeIngredient{}, &result.RecipeIngredients},
+			{&models.IngredientPrice{}, &result.Prices},
+			{&models.PantryItem{}, &result.PantryItems},
+			{&models.IngredientAlias{}, &result.Aliases},
+		}
+		for _, table := range repoint {
+			res := tx.Model(table.model).Where("ingredient_id IN ?", ids).Update("ingredient_id", canonical.ID)
+			if res.Error != nil {
+				return res.Error
+			}
+			*table.count = res.RowsAffected
+		}
+
+		// list items go through the change log so offline clients see them
+		canonicalID := canonical.ID
+		var items []models.ShoppingListItem
+		if err := tx.Where("ingredient_id IN ?", ids).Find(&items).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].IngredientID = &canonicalID
+			if err := saveItem(tx, &items[i]); err != nil {
+				return err
+			}
+		}
+		result.ShoppingListItems = int64(len(items))
+
+		for _, d := range duplicates {
+			// a name that only differs by case or plural already finds canonical
+			if parser.SameName(d.Name, canonical.Name) {
+				continue
+			}
+			var alias models.IngredientAlias
+			err := tx.Omit("Ingredient").Where(models.IngredientAlias{Name: parser.NameForms(d.Name)[0]}).
+				Attrs(models.IngredientAlias{IngredientID: canonical.ID}).
+				FirstOrCreate(&alias).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Save(canonical).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Ingredient{}, ids).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -53,8 +53,15 @@ func (r *mealPlanRepository) Update(mp *models.MealPlan) error {
 	return r.DB.Save(mp).Error
 }
 
+// Delete removes the plan; anything cooked from it stays in the cook log.
 func (r *mealPlanRepository) Delete(mp *models.MealPlan) error {
-	return r.DB.Delete(mp).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.CookEvent{}).Where("meal_plan_id = ?", mp.ID).
+			Update("meal_plan_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(mp).Error
+	})
 }
 
 func (r *mealPlanRepository) FindByUserAndDateRange(userID uint, start, end time.Time) ([]models.MealPlan, error) {
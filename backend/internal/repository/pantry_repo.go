@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type PantryRepository interface {
+	Create(item *models.PantryItem) error
+	FindByID(id uint) (*models.PantryItem, error)
+	// FindByUser lists the user's stock, soonest expiry first.
+	FindByUser(userID uint) ([]models.PantryItem, error)
+	Update(item *models.PantryItem) error
+	Delete(item *models.PantryItem) error
+}
+
+type pantryRepository struct {
+	DB *gorm.DB
+}
+
+func NewPantryRepository(db *gorm.DB) PantryRepository {
+	return &pantryRepository{DB: db}
+}
+
+func (r *pantryRepository) Create(item *models.PantryItem) error {
+	return r.DB.Create(item).Error
+}
+
+func (r *pantryRepository) FindByID(id uint) (*models.PantryItem, error) {
+	var item models.PantryItem
+	err := r.DB.Preload("Ingredient").First(&item, id).Error
+	return &item, err
+}
+
+func (r *pantryRepository) FindByUser(userID uint) ([]models.PantryItem, error) {
+	var items []models.PantryItem
+	err := r.DB.Preload("Ingredient").
+		Where("user_id = ?", userID).
+		Order("expires_at IS NULL").Order("expires_at").Order("id").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *pantryRepository) Update(item *models.PantryItem) error {
+	return r.DB.Omit("Ingredient").Save(item).Error
+}
+
+func (r *pantryRepository) Delete(item *models.PantryItem) error {
+	return r.DB.Delete(item).Error
+}
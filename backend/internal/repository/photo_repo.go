@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type PhotoRepository interface {
+	FindByRecipeID(recipeID uint) ([]models.Photo, error)
+	FindByStep(recipeID uint, step int) (*models.Photo, error)
+	// Save creates the photo for its recipe and step, or replaces the existing one.
+	Save(photo *models.Photo) error
+	// Move renumbers the photo to step, replacing any photo already there,
+	// which it returns so its files can be removed.
+	Move(photo *models.Photo, step int) (*models.Photo, error)
+	Delete(photo *models.Photo) error
+}
+
+type photoRepository struct {
+	DB *gorm.DB
+}
+
+func NewPhotoRepository(db *gorm.DB) PhotoRepository {
+	return &photoRepository{DB: db}
+}
+
+func (r *photoRepository) FindByRecipeID(recipeID uint) ([]models.Photo, error) {
+	var photos []models.Photo
+	err := r.DB.Where("recipe_id = ?", recipeID).Order("step asc").Find(&photos).Error
+	return photos, err
+}
+
+func (r *photoRepository) FindByStep(recipeID uint, step int) (*models.Photo, error) {
+	var photo models.Photo
+	err := r.DB.Where("recipe_id = ? AND step = ?", recipeID, step).First(&photo).Error
+	return &photo, err
+}
+
+func (r *photoRepository) Save(photo *models.Photo) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("recipe_id = ? AND step = ?", photo.RecipeID, photo.Step).Delete(&models.Photo{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(photo).Error
+	})
+}
+
+func (r *photoRepository) Move(photo *models.Photo, step int) (*models.Photo, error) {
+	var replaced *models.Photo
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var existing models.Photo
+		err := tx.Where("recipe_id = ? AND step = ?", photo.RecipeID, step).First(&existing).Error
+		if err == nil {
+			if err := tx.Delete(&existing).Error; err != nil {
+				return err
+			}
+			replaced = &existing
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return tx.Model(photo).Update("step", step).Error
+	})
+	return replaced, err
+}
+
+func (r *photoRepository) Delete(photo *models.Photo) error {
+	return r.DB.Delete(photo).Error
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type PriceRepository interface {
+	Create(price *models.IngredientPrice) error
+	FindByID(id uint) (*models.IngredientPrice, error)
+	// FindByUser lists the user's prices newest first, optionally for one
+	// ingredient (0 for all) and one store (empty for all).
+	FindByUser(userID uint, ingredientID uint, store string) ([]models.IngredientPrice, error)
+	// FindLatest returns the most recent price of each ingredient that has one.
+	FindLatest(userID uint, ingredientIDs []uint) (map[uint]models.IngredientPrice, error)
+	Delete(price *models.IngredientPrice) error
+}
+
+type priceRepository struct {
+	DB *gorm.DB
+}
+
+func NewPriceRepository(db *gorm.DB) PriceRepository {
+	return &priceRepository{DB: db}
+}
+
+func (r *priceRepository) Create(price *models.IngredientPrice) error {
+	return r.DB.Create(price).Error
+}
+
+func (r *priceRepository) FindByID(id uint) (*models.IngredientPrice, error) {
+	var price models.IngredientPrice
+	err := r.DB.Preload("Ingredient").First(&price, id).Error
+	return &price, err
+}
+
+func (r *priceRepository) FindByUser(userID uint, ingredientID uint, store string) ([]models.IngredientPrice, error) {
+	var prices []models.IngredientPrice
+
+	query := r.DB.Preload("Ingredient").Where("user_id = ?", userID)
+	if ingredientID != 0 {
+		query = query.Where("ingredient_id = ?", ingredientID)
+	}
+	if store = strings.TrimSpace(store); store != "" {
+		query = query.Where("LOWER(store) = ?", strings.ToLower(store))
+	}
+
+	err := query.Order("date desc").Order("id desc").Find(&prices).Error
+	return prices, err
+}
+
+func (r *priceRepository) FindLatest(userID uint, ingredientIDs []uint) (map[uint]models.IngredientPrice, error) {
+	latest := make(map[uint]models.IngredientPrice)
+	if len(ingredientIDs) == 0 {
+		return latest, nil
+	}
+
+	var prices []models.IngredientPrice
+	err := r.DB.Where("user_id = ? AND ingredient_id IN ?", userID, ingredientIDs).
+		Order("date desc").Order("id desc").Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range prices {
+		if _, seen := latest[p.IngredientID]; !seen {
+			latest[p.IngredientID] = p
+		}
+	}
+	return latest, nil
+}
+
+func (r *priceRepository) Delete(price *models.IngredientPrice) error {
+	return r.DB.Delete(price).Error
+}
@@ -1,16 +1,50 @@
 package repository
 
 import (
+	"strings"
+
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
 	"gorm.io/gorm"
 )
 
+// RecipeSortColumns maps the sort keys accepted by Search to SQL expressions.
+var RecipeSortColumns = map[string]string{
+	"name":       "recipes.name",
+	"created":    "recipes.created_at",
+	"updated":    "recipes.updated_at",
+	"total_time": "(recipes.prep_time + recipes.cook_time)",
+}
+
+// RecipeFilter describes a recipe listing query. Zero values disable a filter.
+type RecipeFilter struct {
+	Search             string
+	Category           string
+	MaxTotalTime       int
+	MinServings        int
+	MaxServings        int
+	IncludeIngredients []string
+	ExcludeIngredients []string
+	Tags               []string
+	MatchAllTags       bool
+	Diets              []string // normalised names from package diet
+	ExcludeAllergens   []string
+
+	SortBy   string
+	SortDesc bool
+	Offset   int
+	Limit    int
+}
+
 type RecipeRepository interface {
 	Create(recipe *models.Recipe) error
+	// FindByUserID lists every recipe of the user with its ingredients and tags.
 	FindByUserID(userID uint) ([]models.Recipe, error)
+	Search(userID uint, filter RecipeFilter) ([]models.Recipe, int64, error)
 	FindByID(id uint) (*models.Recipe, error)
 	FindByIDWithDetails(id uint) (*models.Recipe, error)
 	Update(recipe *models.Recipe) error
+	// Delete removes the recipe and everything hanging off it, leaving the
+	// deleted photos in recipe.Photos.
 	Delete(recipe *models.Recipe) error
 }
 
@@ -29,10 +63,118 @@ func (r *recipeRepository) Create(recipe *models.Recipe) error {
 func (r *recipeRepository) FindByUserID(userID uint) ([]models.Recipe, error) {
 	var recipes []models.Recipe
 
-	err := r.DB.Where("user_id = ?", userID).Find(&recipes).Error
+	err := r.DB.Preload("Ingredients.Ingredient").Preload("Tags").
+		Where("user_id = ?", userID).Find(&recipes).Error
 	return recipes, err
 }
 
+func (r *recipeRepository) Search(userID uint, filter RecipeFilter) ([]models.Recipe, int64, error) {
+	var recipes []models.Recipe
+	var total int64
+
+	query := r.DB.Model(&models.Recipe{}).Where("recipes.user_id = ?", userID)
+
+	if filter.Search != "" {
+		pattern := likePattern(filter.Search)
+		query = query.Where("(LOWER(recipes.name) LIKE ? OR LOWER(recipes.description) LIKE ?)", pattern, pattern)
+	}
+	if filter.Category != "" {
+		query = query.Where("LOWER(recipes.category) = ?", strings.ToLower(filter.Category))
+	}
+	if filter.MaxTotalTime > 0 {
+		query = query.Where("recipes.prep_time + recipes.cook_time <= ?", filter.MaxTotalTime)
+	}
+	if filter.MinServings > 0 {
+		query = query.Where("recipes.servings >= ?", filter.MinServings)
+	}
+	if filter.MaxServings > 0 {
+		query = query.Where("recipes.servings <= ?", filter.MaxServings)
+	}
+
+	ingredientMatch := `SELECT 1 FROM recipe_ingredients ri
+		JOIN ingredients i ON i.id = ri.ingredient_id
+		WHERE ri.recipe_id = recipes.id AND LOWER(i.name) LIKE ?`
+
+	for _, name := range filter.IncludeIngredients {
+		query = query.Where("EXISTS ("+ingredientMatch+")", likePattern(name))
+	}
+	for _, name := range filter.ExcludeIngredients {
+		query = query.Where("NOT EXISTS ("+ingredientMatch+")", likePattern(name))
+	}
+
+	// allergens and diets are JSON arrays of plain names, so a quoted name
+	// matches exactly one element
+	for _, allergen := range filter.ExcludeAllergens {
+		query = query.Where(`NOT EXISTS (SELECT 1 FROM recipe_ingredients ri
+			JOIN ingredients i ON i.id = ri.ingredient_id
+			WHERE ri.recipe_id = recipes.id AND i.allergens LIKE ?)`, jsonElementPattern(allergen))
+	}
+	if len(filter.Diets) > 0 {
+		// a recipe with no ingredients suits nothing in particular
+		query = query.Where("EXISTS (SELECT 1 FROM recipe_ingredients ri WHERE ri.recipe_id = recipes.id)")
+	}
+	for _, d := range filter.Diets {
+		query = query.Where(`NOT EXISTS (SELECT 1 FROM recipe_ingredients ri
+			JOIN ingredients i ON i.id = ri.ingredient_id
+			WHERE ri.recipe_id = recipes.id AND (i.diets IS NULL OR i.diets NOT LIKE ?))`, jsonElementPattern(d))
+	}
+
+	if len(filter.Tags) > 0 {
+		// tag names are unique per user regardless of case, so a name asked
+		// for twice must only be counted once
+		names := make([]string, 0, len(filter.Tags))
+		seen := map[string]bool{}
+		for _, t := range filter.Tags {
+			name := strings.ToLower(strings.TrimSpace(t))
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+		tagMatch := `SELECT COUNT(DISTINCT t.id) FROM recipe_tags rt
+			JOIN tags t ON t.id = rt.tag_id
+			WHERE rt.recipe_id = recipes.id AND LOWER(t.name) IN ?`
+		if filter.MatchAllTags {
+			query = query.Where("("+tagMatch+") = ?", names, len(names))
+		} else {
+			query = query.Where("("+tagMatch+") > 0", names)
+		}
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := RecipeSortColumns[filter.SortBy]
+	if !ok {
+		column = RecipeSortColumns["created"]
+	}
+	direction := " ASC"
+	if filter.SortDesc {
+		direction = " DESC"
+	}
+	query = query.Order(column + direction).Order("recipes.id" + direction)
+
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	err := query.Preload("Tags").Find(&recipes).Error
+	return recipes, total, err
+}
+
+func jsonElementPattern(name string) string {
+	replacer := strings.NewReplacer("%", "", "_", "", `"`, "")
+	return `%"` + replacer.Replace(name) + `"%`
+}
+
+// likePattern builds a case-insensitive substring pattern, dropping LIKE wildcards from the term.
+func likePattern(term string) string {
+	replacer := strings.NewReplacer("%", "", "_", "")
+	return "%" + strings.ToLower(strings.TrimSpace(replacer.Replace(term))) + "%"
+}
+
 func (r *recipeRepository) FindByID(id uint) (*models.Recipe, error) {
 	var recipe models.Recipe
 
@@ -45,6 +187,8 @@ func (r *recipeRepository) FindByIDWithDetails(id uint) (*models.Recipe, error)
 	err := r.DB.
 		Preload("Ingredients.Ingredient").
 		Preload("Instructions").
+		Preload("Tags").
+		Preload("Photos").
 		First(&recipe, id).Error
 	return &recipe, err
 }
@@ -69,6 +213,22 @@ func (r *recipeRepository) Delete(recipe *models.Recipe) error {
 			return err
 		}
 
+		if err := tx.Where("recipe_id = ?", recipe.ID).Delete(&models.CookEvent{}).Error; err != nil {
+			return err
+		}
+
+		// hand the photo rows back so the caller can remove their files
+		if err := tx.Where("recipe_id = ?", recipe.ID).Find(&recipe.Photos).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("recipe_id = ?", recipe.ID).Delete(&models.Photo{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(recipe).Association("Tags").Clear(); err != nil {
+			return err
+		}
+
 		if err := tx.Delete(recipe).Error; err != nil {
 			return err
 		}
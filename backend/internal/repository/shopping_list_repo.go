@@ -1,17 +1,49 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ShoppingListRepository stores lists and their items. Every write to an
+// item bumps its version and the list's revision, and is logged as a
+// ShoppingListChange in the same transaction.
 type ShoppingListRepository interface {
 	Create(list *models.ShoppingList) error
 	CreateItem(item *models.ShoppingListItem) error
 	FindByID(id uint) (*models.ShoppingList, error)
 	FindItemsByListID(listID uint) ([]models.ShoppingListItem, error)
 	FindItemByID(id uint) (*models.ShoppingListItem, error)
+	// FindItemByClientID finds an item a client added offline by the id the
+	// client gave it.
+	FindItemByClientID(listID uint, clientID string) (*models.ShoppingListItem, error)
 	UpdateItem(item *models.ShoppingListItem) error
+	DeleteItem(item *models.ShoppingListItem) error
+	// FindByUser pages through the user's lists newest first, with items,
+	// and reports how many there are in total.
+	FindByUser(userID uint, offset, limit int) ([]models.ShoppingList, int64, error)
+	// FindLatestByRange returns the newest list the user made for exactly
+	// these dates, with items and their ingredients.
+	FindLatestByRange(userID uint, start, end time.Time) (*models.ShoppingList, error)
+	// SyncItems creates or updates the saved items and deletes the removed
+	// ones in a single transaction.
+	SyncItems(save []*models.ShoppingListItem, remove []*models.ShoppingListItem) error
+	// Delete removes the list, its items and their change log.
+	Delete(list *models.ShoppingList) error
+
+	// ChangesSince returns the list's changes after the token, oldest first.
+	ChangesSince(listID uint, token uint) ([]models.ShoppingListChange, error)
+	// LatestChange returns the list's revision, the token of its newest
+	// change, 0 if none.
+	LatestChange(listID uint) (uint, error)
+	FindSyncOp(listID uint, opID string) (*models.ShoppingListSyncOp, error)
+	// ApplySyncOp writes a synced operation's item changes and its receipt
+	// in one transaction. When the operation already has a receipt nothing
+	// is written and gorm.ErrDuplicatedKey is returned.
+	ApplySyncOp(receipt *models.ShoppingListSyncOp, save []*models.ShoppingListItem, remove []*models.ShoppingListItem) error
 }
 
 type shoppingListRepository struct {
@@ -27,7 +59,9 @@ func (r *shoppingListRepository) Create(list *models.ShoppingList) error {
 }
 
 func (r *shoppingListRepository) CreateItem(item *models.ShoppingListItem) error {
-	return r.DB.Create(item).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return createItem(tx, item)
+	})
 }
 
 func (r *shoppingListRepository) FindByID(id uint) (*models.ShoppingList, error) {
@@ -48,6 +82,159 @@ func (r *shoppingListRepository) FindItemByID(id uint) (*models.ShoppingListItem
 	return &item, err
 }
 
+func (r *shoppingListRepository) FindItemByClientID(listID uint, clientID string) (*models.ShoppingListItem, error) {
+	var item models.ShoppingListItem
+	err := r.DB.Where("shopping_list_id = ? AND client_id = ?", listID, clientID).First(&item).Error
+	return &item, err
+}
+
 func (r *shoppingListRepository) UpdateItem(item *models.ShoppingListItem) error {
-	return r.DB.Save(item).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return saveItem(tx, item)
+	})
+}
+
+func (r *shoppingListRepository) DeleteItem(item *models.ShoppingListItem) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return deleteItem(tx, item)
+	})
+}
+
+func (r *shoppingListRepository) FindByUser(userID uint, offset, limit int) ([]models.ShoppingList, int64, error) {
+	var lists []models.ShoppingList
+	var total int64
+
+	query := r.DB.Model(&models.ShoppingList{}).Where("user_id = ?", userID)
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Items").
+		Order("created_at DESC").Order("id DESC").
+		Limit(limit).Offset(offset).
+		Find(&lists).Error
+	return lists, total, err
+}
+
+func (r *shoppingListRepository) FindLatestByRange(userID uint, start, end time.Time) (*models.ShoppingList, error) {
+	var list models.ShoppingList
+	err := r.DB.Preload("Items.Ingredient").
+		Where("user_id = ? AND start_date = ? AND end_date = ?", userID, start, end).
+		Order("created_at DESC").Order("id DESC").
+		First(&list).Error
+	return &list, err
+}
+
+func (r *shoppingListRepository) Delete(list *models.ShoppingList) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []any{&models.ShoppingListItem{}, &models.ShoppingListChange{}, &models.ShoppingListSyncOp{}} {
+			if err := tx.Where("shopping_list_id = ?", list.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(list).Error
+	})
+}
+
+func (r *shoppingListRepository) SyncItems(save []*models.ShoppingListItem, remove []*models.ShoppingListItem) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return writeItems(tx, save, remove)
+	})
+}
+
+func (r *shoppingListRepository) ChangesSince(listID uint, token uint) ([]models.ShoppingListChange, error) {
+	var changes []models.ShoppingListChange
+	err := r.DB.Where("shopping_list_id = ? AND token > ?", listID, token).Order("token").Find(&changes).Error
+	return changes, err
+}
+
+func (r *shoppingListRepository) LatestChange(listID uint) (uint, error) {
+	var list models.ShoppingList
+	err := r.DB.Select("revision").First(&list, listID).Error
+	return list.Revision, err
+}
+
+func (r *shoppingListRepository) FindSyncOp(listID uint, opID string) (*models.ShoppingListSyncOp, error) {
+	var op models.ShoppingListSyncOp
+	err := r.DB.Where("shopping_list_id = ? AND op_id = ?", listID, opID).First(&op).Error
+	return &op, err
+}
+
+func (r *shoppingListRepository) ApplySyncOp(receipt *models.ShoppingListSyncOp, save []*models.ShoppingListItem, remove []*models.ShoppingListItem) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := writeItems(tx, save, remove); err != nil {
+			return err
+		}
+		// an add's receipt names the item it created
+		if receipt.ItemID == 0 && len(save) > 0 {
+			receipt.ItemID = save[0].ID
+		}
+		// the unique (list, op) index turns away a second request applying
+		// the same operation, rolling back its writes
+		return tx.Create(receipt).Error
+	})
+}
+
+func writeItems(tx *gorm.DB, save []*models.ShoppingListItem, remove []*models.ShoppingListItem) error {
+	for _, item := range save {
+		write := saveItem
+		if item.ID == 0 {
+			write = createItem
+		}
+		if err := write(tx, item); err != nil {
+			return err
+		}
+	}
+	for _, item := range remove {
+		if err := deleteItem(tx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createItem(tx *gorm.DB, item *models.ShoppingListItem) error {
+	item.Version = 1
+	if err := tx.Omit(clause.Associations).Create(item).Error; err != nil {
+		return err
+	}
+	return logChange(tx, item, false)
+}
+
+func saveItem(tx *gorm.DB, item *models.ShoppingListItem) error {
+	item.Version++
+	if err := tx.Omit(clause.Associations).Save(item).Error; err != nil {
+		return err
+	}
+	return logChange(tx, item, false)
+}
+
+func deleteItem(tx *gorm.DB, item *models.ShoppingListItem) error {
+	if err := tx.Delete(item).Error; err != nil {
+		return err
+	}
+	return logChange(tx, item, true)
+}
+
+// logChange numbers the change with the list's next revision. The update
+// holds the list's row lock until the transaction ends, so revisions are
+// handed out in commit order and a client given a token has already been
+// able to see every change up to it.
+func logChange(tx *gorm.DB, item *models.ShoppingListItem, deleted bool) error {
+	err := tx.Model(&models.ShoppingList{}).Where("id = ?", item.ShoppingListID).
+		UpdateColumn("revision", gorm.Expr("revision + 1")).Error
+	if err != nil {
+		return err
+	}
+	var list models.ShoppingList
+	if err := tx.Select("revision").First(&list, item.ShoppingListID).Error; err != nil {
+		return err
+	}
+	return tx.Create(&models.ShoppingListChange{
+		ShoppingListID: item.ShoppingListID,
+		Token:          list.Revision,
+		ItemID:         item.ID,
+		Version:        item.Version,
+		Deleted:        deleted,
+	}).Error
 }
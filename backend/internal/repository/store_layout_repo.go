@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type StoreLayoutRepository interface {
+	Create(store *models.StoreLayout) error
+	FindByID(id uint) (*models.StoreLayout, error)
+	FindByUser(userID uint) ([]models.StoreLayout, error)
+	Update(store *models.StoreLayout) error
+	Delete(store *models.StoreLayout) error
+}
+
+type storeLayoutRepository struct {
+	DB *gorm.DB
+}
+
+func NewStoreLayoutRepository(db *gorm.DB) StoreLayoutRepository {
+	return &storeLayoutRepository{DB: db}
+}
+
+func (r *storeLayoutRepository) Create(store *models.StoreLayout) error {
+	return r.DB.Create(store).Error
+}
+
+func (r *storeLayoutRepository) FindByID(id uint) (*models.StoreLayout, error) {
+	var store models.StoreLayout
+	err := r.DB.First(&store, id).Error
+	return &store, err
+}
+
+func (r *storeLayoutRepository) FindByUser(userID uint) ([]models.StoreLayout, error) {
+	var stores []models.StoreLayout
+	err := r.DB.Where("user_id = ?", userID).Order("name").Order("id").Find(&stores).Error
+	return stores, err
+}
+
+func (r *storeLayoutRepository) Update(store *models.StoreLayout) error {
+	return r.DB.Save(store).Error
+}
+
+func (r *storeLayoutRepository) Delete(store *models.StoreLayout) error {
+	return r.DB.Delete(store).Error
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	Create(tag *models.Tag) error
+	FindByUserID(userID uint) ([]models.Tag, error)
+	FindByID(id uint) (*models.Tag, error)
+	FindByName(userID uint, name string) (*models.Tag, error)
+	Update(tag *models.Tag) error
+	Delete(tag *models.Tag) error
+	AttachToRecipe(tag *models.Tag, recipeID uint) error
+}
+
+type tagRepository struct {
+	DB *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{DB: db}
+}
+
+func (r *tagRepository) Create(tag *models.Tag) error {
+	return r.DB.Create(tag).Error
+}
+
+func (r *tagRepository) FindByUserID(userID uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.DB.Where("user_id = ?", userID).Order("name asc").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) FindByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.DB.First(&tag, id).Error
+	return &tag, err
+}
+
+func (r *tagRepository) FindByName(userID uint, name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.DB.
+		Where("user_id = ? AND LOWER(name) = ?", userID, strings.ToLower(strings.TrimSpace(name))).
+		First(&tag).Error
+	return &tag, err
+}
+
+func (r *tagRepository) Update(tag *models.Tag) error {
+	return r.DB.Save(tag).Error
+}
+
+func (r *tagRepository) Delete(tag *models.Tag) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(tag).Association("Recipes").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(tag).Error
+	})
+}
+
+func (r *tagRepository) AttachToRecipe(tag *models.Tag, recipeID uint) error {
+	return r.DB.Model(&models.Recipe{ID: recipeID}).Association("Tags").Append(tag)
+}
@@ -8,6 +8,9 @@ import (
 type UserRepository interface {
 	Create(user *models.User) error
 	FindByEmail(email string) (*models.User, error)
+	FindByID(id uint) (*models.User, error)
+	UpdateTargets(userID uint, targets *models.Nutrition) error
+	UpdateExclusions(userID uint, exclusions *models.Exclusions) error
 }
 
 type userRepository struct {
@@ -27,3 +30,21 @@ func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 	err := r.DB.Where("email = ?", email).First(&user).Error
 	return &user, err
 }
+
+func (r *userRepository) FindByID(id uint) (*models.User, error) {
+	var user models.User
+	err := r.DB.First(&user, id).Error
+	return &user, err
+}
+
+// UpdateTargets replaces the user's daily targets; nil clears them.
+func (r *userRepository) UpdateTargets(userID uint, targets *models.Nutrition) error {
+	return r.DB.Model(&models.User{ID: userID}).Select("Targets").
+		Updates(&models.User{Targets: targets}).Error
+}
+
+// UpdateExclusions replaces the user's exclusions; nil clears them.
+func (r *userRepository) UpdateExclusions(userID uint, exclusions *models.Exclusions) error {
+	return r.DB.Model(&models.User{ID: userID}).Select("Exclusions").
+		Updates(&models.User{Exclusions: exclusions}).Error
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/handlers"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterAccountRoutes(r *gin.RouterGroup, db *gorm.DB) {
+
+	accountService := services.NewAccountService(db)
+	accountHandler := handlers.NewAccountHandler(accountService)
+
+	account := r.Group("/account")
+	{
+		account.GET("/export", accountHandler.ExportArchive)
+		account.POST("/import", accountHandler.ImportArchive)
+	}
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/handlers"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterCookLogRoutes adds marking recipes and planned meals as cooked,
+// and the history that builds up.
+func RegisterCookLogRoutes(r *gin.RouterGroup, db *gorm.DB) {
+
+	cookLogRepo := repository.NewCookLogRepository(db)
+	recipeRepo := repository.NewRecipeRepository(db)
+	mealPlanRepo := repository.NewMealPlanRepository(db)
+
+	cookLogService := services.NewCookLogService(cookLogRepo, recipeRepo, mealPlanRepo)
+	cookLogHandler := handlers.NewCookLogHandler(cookLogService)
+
+	r.POST("/meal-plans/:id/cooked", cookLogHandler.CookMealPlan)
+	r.POST("/recipes/:id/cooked", cookLogHandler.CookRecipe)
+	r.GET("/recipes/:id/cooked", cookLogHandler.RecipeHistory)
+
+	cookLog := r.Group("/cook-log")
+	{
+		cookLog.GET("", cookLogHandler.GetCookLog)
+		cookLog.GET("/stats", cookLogHandler.CookStats)
+		cookLog.DELETE("/:id", cookLogHandler.DeleteEvent)
+	}
+}
@@ -26,6 +26,16 @@ func RegisterIngredientRoutes(r *gin.RouterGroup, db *gorm.DB) {
 	{
 		ingredients.POST("", ingredientHandler.CreateIngredient)
 		ingredients.GET("", ingredientHandler.GetIngredients)
+		ingredients.GET("/search", ingredientHandler.SearchIngredients)
+		ingredients.PUT("/:id", ingredientHandler.UpdateIngredient)
+		ingredients.POST("/parse", ingredientHandler.ParseIngredients)
+		ingredients.POST("/nutrition/import", ingredientHandler.ImportNutrition)
+
+		// the catalog is shared, so any signed-in user may tidy it
+		ingredients.GET("/duplicates", ingredientHandler.SuggestDuplicates)
+		ingredients.POST("/merge", ingredientHandler.MergeIngredients)
+		ingredients.POST("/:id/aliases", ingredientHandler.AddAlias)
+		ingredients.DELETE("/aliases/:id", ingredientHandler.DeleteAlias)
 
 		ingredients.POST("/recipes/:id/ingredients", ingredientHandler.AddIngredientToRecipe)
 		ingredients.GET("/recipes/:id/ingredients", ingredientHandler.GetRecipeIngredients)
@@ -4,15 +4,16 @@ import (
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/handlers"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/storage"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func RegisterInstructionRoutes(r *gin.RouterGroup, db *gorm.DB) {
+func RegisterInstructionRoutes(r *gin.RouterGroup, db *gorm.DB, store storage.Storage) {
 	instructionRepo := repository.NewInstructionRepository(db)
 	recipeRepo := repository.NewRecipeRepository(db)
 
-	service := services.NewInstructionService(instructionRepo, recipeRepo)
+	service := services.NewInstructionService(instructionRepo, recipeRepo, repository.NewPhotoRepository(db), store)
 	handler := handlers.NewInstructionHandler(service)
 
 	instructions := r.Group("/recipes/:id/instructions")
@@ -12,13 +12,18 @@ func RegisterMealPlanRoutes(r *gin.RouterGroup, db *gorm.DB) {
 
 	mealRepo := repository.NewMealPlanRepository(db)
 	recipeRepo := repository.NewRecipeRepository(db)
-	mealPlanService := services.NewMealPlanService(mealRepo, recipeRepo)
+	userRepo := repository.NewUserRepository(db)
+	mealPlanService := services.NewMealPlanService(mealRepo, recipeRepo, userRepo)
 	mealPlanHandler := handlers.NewMealPlanHandler(mealPlanService)
 
+	nutritionService := services.NewNutritionService(recipeRepo, mealRepo, userRepo)
+	nutritionHandler := handlers.NewNutritionHandler(nutritionService)
+
 	mealPlans := r.Group("/meal-plans")
 	{
 		mealPlans.POST("", mealPlanHandler.Create)
 		mealPlans.GET("", mealPlanHandler.GetByDate)
+		mealPlans.GET("/nutrition", nutritionHandler.MealPlanNutrition)
 		mealPlans.PUT("/:id", mealPlanHandler.Update)
 		mealPlans.DELETE("/:id", mealPlanHandler.Delete)
 	}
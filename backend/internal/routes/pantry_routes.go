@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/handlers"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterPantryRoutes(r *gin.RouterGroup, db *gorm.DB) {
+
+	pantryRepo := repository.NewPantryRepository(db)
+	ingredientRepo := repository.NewIngredientRepository(db)
+	pantryService := services.NewPantryService(pantryRepo, ingredientRepo)
+	pantryHandler := handlers.NewPantryHandler(pantryService)
+
+	pantry := r.Group("/pantry")
+	{
+		pantry.POST("", pantryHandler.AddItem)
+		pantry.GET("", pantryHandler.GetItems)
+		pantry.PUT("/:id", pantryHandler.UpdateItem)
+		pantry.DELETE("/:id", pantryHandler.DeleteItem)
+	}
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/handlers"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegisterPriceRoutes adds price tracking and the cost estimates built on it.
+func RegisterPriceRoutes(r *gin.RouterGroup, db *gorm.DB) {
+
+	priceRepo := repository.NewPriceRepository(db)
+	ingredientRepo := repository.NewIngredientRepository(db)
+	recipeRepo := repository.NewRecipeRepository(db)
+	mealPlanRepo := repository.NewMealPlanRepository(db)
+
+	priceService := services.NewPriceService(priceRepo, ingredientRepo)
+	costService := services.NewCostService(recipeRepo, mealPlanRepo, priceRepo)
+	priceHandler := handlers.NewPriceHandler(priceService, costService)
+
+	prices := r.Group("/prices")
+	{
+		prices.POST("", priceHandler.AddPrice)
+		prices.GET("", priceHandler.GetPrices)
+		prices.DELETE("/:id", priceHandler.DeletePrice)
+	}
+
+	r.GET("/recipes/:id/cost", priceHandler.RecipeCost)
+	r.GET("/meal-plans/cost", priceHandler.MealPlanCost)
+}
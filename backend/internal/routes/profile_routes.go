@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/handlers"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterProfileRoutes(r *gin.RouterGroup, db *gorm.DB) {
+
+	userRepo := repository.NewUserRepository(db)
+	profileService := services.NewProfileService(userRepo)
+	profileHandler := handlers.NewProfileHandler(profileService)
+
+	profile := r.Group("/profile")
+	{
+		profile.GET("", profileHandler.GetProfile)
+		profile.PUT("/targets", profileHandler.SetTargets)
+		profile.PUT("/exclusions", profileHandler.SetExclusions)
+	}
+}
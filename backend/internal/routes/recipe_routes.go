@@ -4,32 +4,59 @@ import (
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/handlers"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/storage"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func RegisterRecipeRoutes(r *gin.RouterGroup, db *gorm.DB) {
+func RegisterRecipeRoutes(r *gin.RouterGroup, db *gorm.DB, store storage.Storage) {
 
 	recipeRepo := repository.NewRecipeRepository(db)
-	recipeService := services.NewRecipeService(recipeRepo, db)
+	recipeService := services.NewRecipeService(recipeRepo, repository.NewCookLogRepository(db), db, store)
 	recipeHandler := handlers.NewRecipeHandler(recipeService)
 
+	formatService := services.NewRecipeFormatService(recipeService, recipeRepo)
+	formatHandler := handlers.NewRecipeFormatHandler(formatService)
+
 	scaleService := services.NewRecipeScaleService(recipeRepo)
 	scaleHandler := handlers.NewRecipeScaleHandler(scaleService)
 
+	nutritionService := services.NewNutritionService(recipeRepo, repository.NewMealPlanRepository(db), repository.NewUserRepository(db))
+	nutritionHandler := handlers.NewNutritionHandler(nutritionService)
+
+	recommendationService := services.NewRecommendationService(recipeRepo, repository.NewMealPlanRepository(db))
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService)
+
+	matchService := services.NewMatchService(recipeRepo, repository.NewIngredientRepository(db))
+	matchHandler := handlers.NewMatchHandler(matchService)
+
 	instRepo := repository.NewInstructionRepository(db)
-	instService := services.NewInstructionService(instRepo, recipeRepo)
+	photoRepo := repository.NewPhotoRepository(db)
+	instService := services.NewInstructionService(instRepo, recipeRepo, photoRepo, store)
 	instHandler := handlers.NewInstructionHandler(instService)
 
+	photoService := services.NewPhotoService(photoRepo, recipeRepo, instRepo, store)
+	photoHandler := handlers.NewPhotoHandler(photoService)
+
 	recipes := r.Group("/recipes")
 	{
 		recipes.POST("", recipeHandler.CreateRecipe)
 		recipes.GET("", recipeHandler.GetMyRecipes)
+		recipes.POST("/import", formatHandler.ImportRecipe)
+		recipes.GET("/recommendations", recommendationHandler.Recommend)
+		recipes.POST("/match", matchHandler.MatchRecipes)
 		recipes.GET("/:id", recipeHandler.GetRecipeByID)
 		recipes.PUT("/:id", recipeHandler.UpdateRecipe)
 		recipes.DELETE("/:id", recipeHandler.DeleteRecipe)
 
 		recipes.GET("/:id/scale", scaleHandler.ScaleRecipe)
+		recipes.GET("/:id/nutrition", nutritionHandler.RecipeNutrition)
+		recipes.GET("/:id/export", formatHandler.ExportRecipe)
+
+		recipes.PUT("/:id/photo", photoHandler.UploadPhoto)
+		recipes.DELETE("/:id/photo", photoHandler.DeletePhoto)
+		recipes.PUT("/:id/steps/:step/photo", photoHandler.UploadPhoto)
+		recipes.DELETE("/:id/steps/:step/photo", photoHandler.DeletePhoto)
 
 		recipes.POST("/:id/instructions", instHandler.AddInstruction)
 		recipes.GET("/:id/instructions", instHandler.GetInstructions)
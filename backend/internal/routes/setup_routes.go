@@ -2,41 +2,39 @@ package routes
 
 import (
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/middleware"
-	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/realtime"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/storage"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(db *gorm.DB) *gin.Engine {
+func SetupRoutes(db *gorm.DB, store storage.Storage, hub realtime.Hub) *gin.Engine {
 	r := gin.Default()
 	r.Use(CORSMiddleware())
 
 	RegisterAuthRoutes(r, db)
 
+	// uploaded photos have unguessable keys and are served without auth
+	// so <img> tags can load them
+	if local, ok := store.(*storage.Local); ok {
+		r.Static("/uploads", local.Root)
+	}
+
 	protected := r.Group("/api")
 	protected.Use(middleware.JWTAuthMiddleware())
 	{
-		RegisterRecipeRoutes(protected, db)
+		RegisterRecipeRoutes(protected, db, store)
 		RegisterIngredientRoutes(protected, db)
-		// RegisterInstructionRoutes(protected, db)
+		// RegisterInstructionRoutes(protected, db, store)
 		RegisterMealPlanRoutes(protected, db)
-		RegisterShoppingListRoutes(protected, db)
-
-		protected.GET("/profile", func(c *gin.Context) {
-			userID, _ := c.Get("user_id")
-
-			var user models.User
-			if err := db.First(&user, userID).Error; err != nil {
-				c.JSON(404, gin.H{"error": "User not found"})
-				return
-			}
-
-			c.JSON(200, gin.H{
-				"id":    user.ID,
-				"name":  user.Name,
-				"email": user.Email,
-			})
-		})
+		RegisterShoppingListRoutes(protected, db, hub)
+		RegisterTagRoutes(protected, db)
+		RegisterAccountRoutes(protected, db)
+		RegisterProfileRoutes(protected, db)
+		RegisterPriceRoutes(protected, db)
+		RegisterPantryRoutes(protected, db)
+		RegisterCookLogRoutes(protected, db)
+		RegisterStoreRoutes(protected, db)
 	}
 
 	return r
@@ -2,27 +2,41 @@ package routes
 
 import (
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/handlers"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/realtime"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func RegisterShoppingListRoutes(r *gin.RouterGroup, db *gorm.DB) {
+func RegisterShoppingListRoutes(r *gin.RouterGroup, db *gorm.DB, hub realtime.Hub) {
 
 	mealPlanRepo := repository.NewMealPlanRepository(db)
 	shoppingRepo := repository.NewShoppingListRepository(db)
 
 	recipeIngRepo := repository.NewRecipeIngredientRepository(db)
+	priceRepo := repository.NewPriceRepository(db)
+	pantryRepo := repository.NewPantryRepository(db)
+	storeRepo := repository.NewStoreLayoutRepository(db)
+	ingredientRepo := repository.NewIngredientRepository(db)
 
-	service := services.NewShoppingListService(mealPlanRepo, recipeIngRepo, shoppingRepo)
+	service := services.NewShoppingListService(mealPlanRepo, recipeIngRepo, shoppingRepo, priceRepo, pantryRepo, storeRepo, ingredientRepo, hub)
 
 	handler := handlers.NewShoppingListHandler(service)
 
 	shopping := r.Group("/shopping-lists")
 	{
+		shopping.GET("", handler.ListShoppingLists)
 		shopping.POST("/generate", handler.GenerateShoppingList)
 		shopping.GET("/:id", handler.GetShoppingList)
+		shopping.DELETE("/:id", handler.DeleteShoppingList)
+		shopping.POST("/:id/refresh", handler.RefreshShoppingList)
+		shopping.POST("/:id/sync", handler.SyncShoppingList)
+		shopping.GET("/:id/export", handler.ExportShoppingList)
+		shopping.GET("/:id/events", handler.StreamEvents)
+		shopping.POST("/:id/items", handler.AddItem)
+		shopping.PATCH("/items/:id", handler.UpdateItem)
+		shopping.DELETE("/items/:id", handler.DeleteItem)
 		shopping.PATCH("/items/:id/toggle", handler.ToggleItem)
 	}
 }
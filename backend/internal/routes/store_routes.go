@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/handlers"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterStoreRoutes(r *gin.RouterGroup, db *gorm.DB) {
+
+	storeRepo := repository.NewStoreLayoutRepository(db)
+	storeService := services.NewStoreService(storeRepo)
+	storeHandler := handlers.NewStoreHandler(storeService)
+
+	stores := r.Group("/stores")
+	{
+		stores.GET("/aisles", storeHandler.ListAisles)
+		stores.POST("", storeHandler.CreateStore)
+		stores.GET("", storeHandler.GetStores)
+		stores.PUT("/:id", storeHandler.UpdateStore)
+		stores.DELETE("/:id", storeHandler.DeleteStore)
+	}
+}
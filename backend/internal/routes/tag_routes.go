@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/handlers"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func RegisterTagRoutes(r *gin.RouterGroup, db *gorm.DB) {
+
+	tagRepo := repository.NewTagRepository(db)
+	recipeRepo := repository.NewRecipeRepository(db)
+	tagService := services.NewTagService(tagRepo, recipeRepo)
+	tagHandler := handlers.NewTagHandler(tagService)
+
+	tags := r.Group("/tags")
+	{
+		tags.POST("", tagHandler.CreateTag)
+		tags.GET("", tagHandler.GetTags)
+		tags.PUT("/:id", tagHandler.UpdateTag)
+		tags.DELETE("/:id", tagHandler.DeleteTag)
+		tags.POST("/migrate-categories", tagHandler.MigrateCategories)
+	}
+}
@@ -0,0 +1,390 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/archive"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/parser"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var ErrImportConflict = errors.New("archive conflicts with existing data")
+var ErrInvalidConflictPolicy = errors.New("on_conflict must be skip, duplicate or fail")
+
+// errDryRun rolls back the import transaction after a dry run.
+var errDryRun = errors.New("dry run")
+
+type AccountService interface {
+	ExportArchive(userID uint, w io.Writer) error
+	ImportArchive(userID uint, r io.ReaderAt, size int64, query dto.AccountImportQuery) (*dto.AccountImportResponse, error)
+}
+
+type accountService struct {
+	DB *gorm.DB
+}
+
+func NewAccountService(db *gorm.DB) AccountService {
+	return &accountService{DB: db}
+}
+
+func (s *accountService) ExportArchive(userID uint, w io.Writer) error {
+	a := &archive.Archive{}
+	ingredients := map[uint]bool{}
+	addIngredient := func(i models.Ingredient) {
+		if !ingredients[i.ID] {
+			ingredients[i.ID] = true
+			a.Ingredients = append(a.Ingredients, archive.Ingredient{
+				ID: i.ID, Name: i.Name, Density: i.Density, PieceWeight: i.PieceWeight,
+				Nutrition: (*archive.Nutrition)(i.Nutrition), Allergens: i.Allergens, Diets: i.Diets,
+			})
+		}
+	}
+
+	var tags []models.Tag
+	if err := s.DB.Where("user_id = ?", userID).Order("id").Find(&tags).Error; err != nil {
+		return err
+	}
+	for _, t := range tags {
+		a.Tags = append(a.Tags, archive.Tag{ID: t.ID, Name: t.Name})
+	}
+
+	var recipes []models.Recipe
+	err := s.DB.
+		Preload("Ingredients", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Ingredients.Ingredient").
+		Preload("Instructions", func(db *gorm.DB) *gorm.DB { return db.Order("step_number") }).
+		Preload("Tags").
+		Where("user_id = ?", userID).Order("id").Find(&recipes).Error
+	if err != nil {
+		return err
+	}
+	for _, r := range recipes {
+		rec := archive.Recipe{
+			ID: r.ID, Name: r.Name, Description: r.Description, Servings: r.Servings,
+			PrepTime: r.PrepTime, CookTime: r.CookTime, Category: r.Category,
+			Nutrition: (*archive.Nutrition)(r.Nutrition), TagIDs: []uint{}, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
+		}
+		for _, t := range r.Tags {
+			rec.TagIDs = append(rec.TagIDs, t.ID)
+		}
+		for _, ri := range r.Ingredients {
+			addIngredient(ri.Ingredient)
+			rec.Ingredients = append(rec.Ingredients, archive.RecipeIngredient{
+				IngredientID: ri.IngredientID, Quantity: ri.Quantity, Unit: ri.Unit, Note: ri.Note,
+			})
+		}
+		for _, ins := range r.Instructions {
+			rec.Instructions = append(rec.Instructions, archive.Instruction{StepNumber: ins.StepNumber, Text: ins.Text})
+		}
+		a.Recipes = append(a.Recipes, rec)
+	}
+
+	var plans []models.MealPlan
+	if err := s.DB.Where("user_id = ?", userID).Order("date, id").Find(&plans).Error; err != nil {
+		return err
+	}
+	for _, mp := range plans {
+		a.MealPlans = append(a.MealPlans, archive.MealPlan{
+			ID: mp.ID, RecipeID: mp.RecipeID, Date: mp.Date, MealType: mp.MealType, TargetServings: mp.TargetServings,
+		})
+	}
+
+	var lists []models.ShoppingList
+	err = s.DB.
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Items.Ingredient").
+		Where("user_id = ?", userID).Order("id").Find(&lists).Error
+	if err != nil {
+		return err
+	}
+	for _, l := range lists {
+		list := archive.ShoppingList{ID: l.ID, StartDate: l.StartDate, EndDate: l.EndDate}
+		for _, item := range l.Items {
+			entry := archive.ShoppingListItem{
+				Name: item.Name, Quantity: item.Quantity, Unit: item.Unit,
+				Note: item.Note, Manual: item.Manual, Checked: item.Checked, Stale: item.Stale,
+			}
+			if item.IngredientID != nil {
+				addIngredient(item.Ingredient)
+				entry.IngredientID = *item.IngredientID
+			}
+			list.Items = append(list.Items, entry)
+		}
+		a.ShoppingLists = append(a.ShoppingLists, list)
+	}
+
+	return archive.Write(w, a)
+}
+
+// ImportArchive restores an archive into the user's account in one
+// transaction. Ingredients are re-linked to master rows by name; recipes,
+// meal plans and shopping lists that already exist are conflicts, resolved
+// by the on_conflict policy and reported either way.
+func (s *accountService) ImportArchive(userID uint, r io.ReaderAt, size int64, query dto.AccountImportQuery) (*dto.AccountImportResponse, error) {
+	policy := query.OnConflict
+	if policy == "" {
+		policy = "skip"
+	}
+	if policy != "skip" && policy != "duplicate" && policy != "fail" {
+		return nil, ErrInvalidConflictPolicy
+	}
+
+	a, err := archive.Read(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AccountImportResponse{DryRun: query.DryRun, Conflicts: []dto.ImportConflict{}}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		imp := &archiveImport{tx: tx, userID: userID, policy: policy, resp: resp}
+		if err := imp.run(a); err != nil {
+			return err
+		}
+		if policy == "fail" && len(imp.blocking) > 0 {
+			return ErrImportConflict
+		}
+		if query.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && err != errDryRun {
+		if err == ErrImportConflict {
+			return resp, err
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// archiveImport carries the ID maps while one archive is written.
+type archiveImport struct {
+	tx       *gorm.DB
+	userID   uint
+	policy   string
+	resp     *dto.AccountImportResponse
+	blocking []dto.ImportConflict
+
+	ingredients map[uint]uint
+	tags        map[uint]models.Tag
+	recipes     map[uint]uint
+}
+
+// conflict records a clash and reports whether the record should still be
+// imported.
+func (imp *archiveImport) conflict(kind, name string) bool {
+	c := dto.ImportConflict{Type: kind, Name: name}
+	switch imp.policy {
+	case "duplicate":
+		c.Resolution = "imported as a duplicate"
+	case "fail":
+		c.Resolution = "aborted"
+		imp.blocking = append(imp.blocking, c)
+	default:
+		c.Resolution = "skipped"
+	}
+	imp.resp.Conflicts = append(imp.resp.Conflicts, c)
+	return imp.policy == "duplicate"
+}
+
+func (imp *archiveImport) run(a *archive.Archive) error {
+	steps := []func(*archive.Archive) error{
+		imp.importIngredients, imp.importTags, imp.importRecipes, imp.importMealPlans, imp.importShoppingLists,
+	}
+	for _, step := range steps {
+		if err := step(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (imp *archiveImport) importIngredients(a *archive.Archive) error {
+	imp.ingredients = map[uint]uint{}
+	for _, in := range a.Ingredients {
+		existing, err := repository.NewIngredientRepository(imp.tx).FindByName(in.Name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ing := models.Ingredient{Name: parser.CleanName(in.Name), Density: in.Density, PieceWeight: in.PieceWeight,
+				Nutrition: (*models.Nutrition)(in.Nutrition), Allergens: in.Allergens, Diets: in.Diets}
+			if err := imp.tx.Create(&ing).Error; err != nil {
+				return err
+			}
+			imp.ingredients[in.ID] = ing.ID
+			imp.resp.IngredientsCreated++
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		imp.ingredients[in.ID] = existing.ID
+
+		// fill gaps in the shared master row, but never overwrite it
+		updates := map[string]any{}
+		if existing.Density == nil && in.Density != nil {
+			updates["density"] = *in.Density
+		} else if differs(existing.Density, in.Density) {
+			imp.resp.Conflicts = append(imp.resp.Conflicts, dto.ImportConflict{
+				Type: "ingredient", Name: in.Name, Resolution: "kept this instance's density",
+			})
+		}
+		if existing.PieceWeight == nil && in.PieceWeight != nil {
+			updates["piece_weight"] = *in.PieceWeight
+		} else if differs(existing.PieceWeight, in.PieceWeight) {
+			imp.resp.Conflicts = append(imp.resp.Conflicts, dto.ImportConflict{
+				Type: "ingredient", Name: in.Name, Resolution: "kept this instance's piece weight",
+			})
+		}
+		if len(updates) > 0 {
+			if err := imp.tx.Model(existing).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if existing.Nutrition == nil && in.Nutrition != nil {
+			// a struct update, since map updates skip the JSON serializer
+			fill := models.Ingredient{Nutrition: (*models.Nutrition)(in.Nutrition)}
+			if err := imp.tx.Model(existing).Select("Nutrition").Updates(&fill).Error; err != nil {
+				return err
+			}
+		} else if existing.Nutrition != nil && in.Nutrition != nil && *existing.Nutrition != models.Nutrition(*in.Nutrition) {
+			imp.resp.Conflicts = append(imp.resp.Conflicts, dto.ImportConflict{
+				Type: "ingredient", Name: in.Name, Resolution: "kept this instance's nutrition",
+			})
+		}
+		if existing.Diets == nil && len(existing.Allergens) == 0 && (in.Diets != nil || len(in.Allergens) > 0) {
+			fill := models.Ingredient{Allergens: in.Allergens, Diets: in.Diets}
+			if err := imp.tx.Model(existing).Select("Allergens", "Diets").Updates(&fill).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func differs(a, b *float64) bool {
+	return a != nil && b != nil && *a != *b
+}
+
+func (imp *archiveImport) importTags(a *archive.Archive) error {
+	imp.tags = map[uint]models.Tag{}
+	for _, t := range a.Tags {
+		var tag models.Tag
+		err := imp.tx.Where("user_id = ? AND LOWER(name) = LOWER(?)", imp.userID, t.Name).First(&tag).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			tag = models.Tag{UserID: imp.userID, Name: t.Name}
+			if err := imp.tx.Create(&tag).Error; err != nil {
+				return err
+			}
+			imp.resp.TagsCreated++
+		} else if err != nil {
+			return err
+		}
+		imp.tags[t.ID] = tag
+	}
+	return nil
+}
+
+func (imp *archiveImport) importRecipes(a *archive.Archive) error {
+	imp.recipes = map[uint]uint{}
+	for _, r := range a.Recipes {
+		var existing models.Recipe
+		err := imp.tx.Where("user_id = ? AND LOWER(name) = LOWER(?)", imp.userID, r.Name).First(&existing).Error
+		if err == nil {
+			if !imp.conflict("recipe", r.Name) {
+				// meal plans for a skipped recipe point at the one already here
+				imp.recipes[r.ID] = existing.ID
+				continue
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		recipe := models.Recipe{
+			UserID: imp.userID, Name: r.Name, Description: r.Description, Servings: r.Servings,
+			PrepTime: r.PrepTime, CookTime: r.CookTime, Category: r.Category,
+			Nutrition: (*models.Nutrition)(r.Nutrition), CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt,
+		}
+		for _, ri := range r.Ingredients {
+			recipe.Ingredients = append(recipe.Ingredients, models.RecipeIngredient{
+				IngredientID: imp.ingredients[ri.IngredientID], Quantity: ri.Quantity, Unit: ri.Unit, Note: ri.Note,
+			})
+		}
+		for _, ins := range r.Instructions {
+			recipe.Instructions = append(recipe.Instructions, models.Instruction{StepNumber: ins.StepNumber, Text: ins.Text})
+		}
+		for _, id := range r.TagIDs {
+			recipe.Tags = append(recipe.Tags, imp.tags[id])
+		}
+
+		if err := imp.tx.Create(&recipe).Error; err != nil {
+			return err
+		}
+		imp.recipes[r.ID] = recipe.ID
+		imp.resp.RecipesImported++
+	}
+	return nil
+}
+
+func (imp *archiveImport) importMealPlans(a *archive.Archive) error {
+	for _, mp := range a.MealPlans {
+		var count int64
+		err := imp.tx.Model(&models.MealPlan{}).
+			Where("user_id = ? AND date = ? AND meal_type = ?", imp.userID, mp.Date, mp.MealType).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 && !imp.conflict("meal_plan", fmt.Sprintf("%s %s", mp.Date.Format("2006-01-02"), mp.MealType)) {
+			continue
+		}
+
+		plan := models.MealPlan{
+			UserID: imp.userID, RecipeID: imp.recipes[mp.RecipeID], Date: mp.Date,
+			MealType: mp.MealType, TargetServings: mp.TargetServings,
+		}
+		if err := imp.tx.Create(&plan).Error; err != nil {
+			return err
+		}
+		imp.resp.MealPlansImported++
+	}
+	return nil
+}
+
+func (imp *archiveImport) importShoppingLists(a *archive.Archive) error {
+	for _, sl := range a.ShoppingLists {
+		var count int64
+		err := imp.tx.Model(&models.ShoppingList{}).
+			Where("user_id = ? AND start_date = ? AND end_date = ?", imp.userID, sl.StartDate, sl.EndDate).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%s to %s", sl.StartDate.Format("2006-01-02"), sl.EndDate.Format("2006-01-02"))
+		if count > 0 && !imp.conflict("shopping_list", name) {
+			continue
+		}
+
+		list := models.ShoppingList{UserID: imp.userID, StartDate: sl.StartDate, EndDate: sl.EndDate}
+		for _, item := range sl.Items {
+			entry := models.ShoppingListItem{
+				Name: item.Name, Quantity: item.Quantity, Unit: item.Unit,
+				Note: item.Note, Manual: item.Manual, Checked: item.Checked, Stale: item.Stale,
+			}
+			if item.IngredientID != 0 {
+				id := imp.ingredients[item.IngredientID]
+				entry.IngredientID = &id
+			}
+			list.Items = append(list.Items, entry)
+		}
+		if err := imp.tx.Create(&list).Error; err != nil {
+			return err
+		}
+		imp.resp.ListsImported++
+	}
+	return nil
+}
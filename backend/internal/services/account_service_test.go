@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+func seedAccount(t *testing.T, db *gorm.DB, userID uint) {
+	t.Helper()
+	recipes := NewRecipeService(repository.NewRecipeRepository(db), repository.NewCookLogRepository(db), db, nil)
+	id, err := recipes.CreateRecipe(userID, dto.CreateRecipeRequest{
+		Name: "Dal", Servings: 2, Category: "Dinner", Tags: []string{"Vegan"},
+		Ingredients: []dto.RecipeIngredientRequest{
+			{Name: "Red lentils", Amount: 200, Unit: "g"},
+			{Name: "Onion", Amount: 1, Unit: "pcs", Note: "diced"},
+		},
+		Instructions: []string{"Rinse.", "Simmer."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	db.Create(&models.MealPlan{UserID: userID, RecipeID: id, Date: date, MealType: "dinner", TargetServings: 4})
+
+	var lentils models.Ingredient
+	db.Where("name = ?", "Red lentils").First(&lentils)
+	db.Create(&models.ShoppingList{UserID: userID, StartDate: date, EndDate: date.AddDate(0, 0, 6),
+		Items: []models.ShoppingListItem{{IngredientID: &lentils.ID, Quantity: 400, Unit: "g", Checked: true}}})
+}
+
+func exportAccount(t *testing.T, service AccountService, userID uint) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := service.ExportArchive(userID, &buf); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestAccountArchive_ExportImportIntoOtherAccount(t *testing.T) {
+	db := setupTestDB()
+	seedAccount(t, db, 1)
+	service := NewAccountService(db)
+
+	data := exportAccount(t, service, 1)
+	resp, err := service.ImportArchive(2, data, data.Size(), dto.AccountImportQuery{})
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if resp.RecipesImported != 1 || resp.TagsCreated != 1 || resp.MealPlansImported != 1 ||
+		resp.ListsImported != 1 || resp.IngredientsCreated != 0 || len(resp.Conflicts) != 0 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	var recipe models.Recipe
+	db.Preload("Ingredients.Ingredient").Preload("Instructions").Preload("Tags").
+		Where("user_id = ?", 2).First(&recipe)
+	if len(recipe.Ingredients) != 2 || recipe.Ingredients[1].Note != "diced" || len(recipe.Instructions) != 2 || len(recipe.Tags) != 1 {
+		t.Fatalf("unexpected imported recipe %+v", recipe)
+	}
+
+	var ingredients int64
+	db.Model(&models.Ingredient{}).Count(&ingredients)
+	if ingredients != 2 {
+		t.Fatalf("expected ingredient master rows to be re-linked, got %d rows", ingredients)
+	}
+
+	var plan models.MealPlan
+	db.Where("user_id = ?", 2).First(&plan)
+	if plan.RecipeID != recipe.ID || plan.TargetServings != 4 {
+		t.Fatalf("meal plan not remapped: %+v", plan)
+	}
+
+	var list models.ShoppingList
+	db.Preload("Items").Where("user_id = ?", 2).First(&list)
+	if len(list.Items) != 1 || !list.Items[0].Checked || *list.Items[0].IngredientID != recipe.Ingredients[0].IngredientID {
+		t.Fatalf("shopping list not remapped: %+v", list)
+	}
+}
+
+func TestAccountArchive_Conflicts(t *testing.T) {
+	db := setupTestDB()
+	seedAccount(t, db, 1)
+	service := NewAccountService(db)
+	data := exportAccount(t, service, 1)
+
+	count := func() int64 {
+		var n int64
+		db.Model(&models.Recipe{}).Where("user_id = ?", 1).Count(&n)
+		return n
+	}
+
+	resp, err := service.ImportArchive(1, data, data.Size(), dto.AccountImportQuery{OnConflict: "fail"})
+	if !errors.Is(err, ErrImportConflict) || len(resp.Conflicts) != 3 {
+		t.Fatalf("expected 3 blocking conflicts, got %v %+v", err, resp)
+	}
+
+	resp, err = service.ImportArchive(1, data, data.Size(), dto.AccountImportQuery{})
+	if err != nil || resp.RecipesImported != 0 || len(resp.Conflicts) != 3 || count() != 1 {
+		t.Fatalf("expected everything skipped, got %v %+v", err, resp)
+	}
+
+	resp, err = service.ImportArchive(1, data, data.Size(), dto.AccountImportQuery{OnConflict: "duplicate", DryRun: true})
+	if err != nil || resp.RecipesImported != 1 || count() != 1 {
+		t.Fatalf("dry run should report but not write, got %v %+v", err, resp)
+	}
+
+	if _, err := service.ImportArchive(1, data, data.Size(), dto.AccountImportQuery{OnConflict: "merge"}); err != ErrInvalidConflictPolicy {
+		t.Fatalf("expected ErrInvalidConflictPolicy, got %v", err)
+	}
+}
@@ -12,8 +12,11 @@ import (
 )
 
 type MockUserRepo struct {
-	FindByEmailFn func(email string) (*models.User, error)
-	CreateFn      func(user *models.User) error
+	FindByEmailFn      func(email string) (*models.User, error)
+	CreateFn           func(user *models.User) error
+	FindByIDFn         func(id uint) (*models.User, error)
+	UpdateTargetsFn    func(userID uint, targets *models.Nutrition) error
+	UpdateExclusionsFn func(userID uint, exclusions *models.Exclusions) error
 }
 
 func (m *MockUserRepo) FindByEmail(email string) (*models.User, error) {
@@ -24,6 +27,18 @@ func (m *MockUserRepo) Create(user *models.User) error {
 	return m.CreateFn(user)
 }
 
+func (m *MockUserRepo) FindByID(id uint) (*models.User, error) {
+	return m.FindByIDFn(id)
+}
+
+func (m *MockUserRepo) UpdateTargets(userID uint, targets *models.Nutrition) error {
+	return m.UpdateTargetsFn(userID, targets)
+}
+
+func (m *MockUserRepo) UpdateExclusions(userID uint, exclusions *models.Exclusions) error {
+	return m.UpdateExclusionsFn(userID, exclusions)
+}
+
 func TestRegister_Success(t *testing.T) {
 	mockRepo := &MockUserRepo{
 		FindByEmailFn: func(email string) (*models.User, error) {
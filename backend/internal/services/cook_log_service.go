@@ -0,0 +1,267 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCookDate = errors.New("date must be YYYY-MM-DD")
+	ErrAlreadyCooked   = errors.New("meal plan is already marked as cooked")
+)
+
+type CookLogService interface {
+	// CookMealPlan logs a planned meal as cooked at its target servings.
+	CookMealPlan(mealPlanID uint, userID uint, req dto.CookRequest) (*dto.CookEventResponse, error)
+	// CookRecipe logs a recipe cooked outside the plan, servings 0 for the
+	// recipe's own.
+	CookRecipe(recipeID uint, userID uint, servings int, req dto.CookRequest) (*dto.CookEventResponse, error)
+	RecipeHistory(recipeID uint, userID uint) (*dto.RecipeCookHistory, error)
+	GetCookLog(userID uint) ([]dto.CookEventResponse, error)
+	// CookStats sums the log per recipe, most cooked first.
+	CookStats(userID uint) ([]dto.RecipeCookStats, error)
+	DeleteEvent(eventID uint, userID uint) error
+}
+
+type cookLogService struct {
+	CookLogRepo  repository.CookLogRepository
+	RecipeRepo   repository.RecipeRepository
+	MealPlanRepo repository.MealPlanRepository
+}
+
+func NewCookLogService(
+	cookLogRepo repository.CookLogRepository,
+	recipeRepo repository.RecipeRepository,
+	mealPlanRepo repository.MealPlanRepository,
+) CookLogService {
+	return &cookLogService{CookLogRepo: cookLogRepo, RecipeRepo: recipeRepo, MealPlanRepo: mealPlanRepo}
+}
+
+func (s *cookLogService) CookMealPlan(mealPlanID uint, userID uint, req dto.CookRequest) (*dto.CookEventResponse, error) {
+	mp, err := s.MealPlanRepo.FindByID(mealPlanID)
+	if err != nil {
+		return nil, err
+	}
+	if mp.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	// the unique index on meal_plan_id settles requests that race past this
+	if _, err := s.CookLogRepo.FindByMealPlan(mp.ID); err == nil {
+		return nil, ErrAlreadyCooked
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	recipe, err := s.RecipeRepo.FindByID(mp.RecipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := cookDate(req.Date, mp.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	servings := mp.TargetServings
+	if servings <= 0 {
+		servings = recipe.Servings
+	}
+
+	resp, err := s.record(&models.CookEvent{
+		UserID:       userID,
+		RecipeID:     recipe.ID,
+		Recipe:       *recipe,
+		MealPlanID:   &mp.ID,
+		Date:         date,
+		Servings:     servings,
+		BaseServings: recipe.Servings,
+		Notes:        strings.TrimSpace(req.Notes),
+		Rating:       req.Rating,
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, ErrAlreadyCooked
+	}
+	return resp, err
+}
+
+func (s *cookLogService) CookRecipe(recipeID uint, userID uint, servings int, req dto.CookRequest) (*dto.CookEventResponse, error) {
+	if servings < 0 {
+		return nil, ErrInvalidServings
+	}
+
+	recipe, err := s.RecipeRepo.FindByID(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	date, err := cookDate(req.Date, time.Now().UTC().Truncate(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	if servings == 0 {
+		servings = recipe.Servings
+	}
+
+	return s.record(&models.CookEvent{
+		UserID:       userID,
+		RecipeID:     recipe.ID,
+		Recipe:       *recipe,
+		Date:         date,
+		Servings:     servings,
+		BaseServings: recipe.Servings,
+		Notes:        strings.TrimSpace(req.Notes),
+		Rating:       req.Rating,
+	})
+}
+
+func (s *cookLogService) record(event *models.CookEvent) (*dto.CookEventResponse, error) {
+	if err := s.CookLogRepo.Create(event); err != nil {
+		return nil, err
+	}
+	resp := cookEventResponse(*event)
+	return &resp, nil
+}
+
+func (s *cookLogService) RecipeHistory(recipeID uint, userID uint) (*dto.RecipeCookHistory, error) {
+	recipe, err := s.RecipeRepo.FindByID(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	events, err := s.CookLogRepo.FindByUser(userID, recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &dto.RecipeCookHistory{
+		RecipeCookStats: dto.RecipeCookStats{RecipeID: recipe.ID, RecipeName: recipe.Name},
+		Events:          []dto.CookEventResponse{},
+	}
+	if stats := cookStats(events); len(stats) > 0 {
+		history.RecipeCookStats = stats[0]
+	}
+	for _, e := range events {
+		history.Events = append(history.Events, cookEventResponse(e))
+	}
+	return history, nil
+}
+
+func (s *cookLogService) GetCookLog(userID uint) ([]dto.CookEventResponse, error) {
+	events, err := s.CookLogRepo.FindByUser(userID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.CookEventResponse{}
+	for _, e := range events {
+		response = append(response, cookEventResponse(e))
+	}
+	return response, nil
+}
+
+func (s *cookLogService) CookStats(userID uint) ([]dto.RecipeCookStats, error) {
+	events, err := s.CookLogRepo.FindByUser(userID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := cookStats(events)
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].TimesCooked != stats[j].TimesCooked {
+			return stats[i].TimesCooked > stats[j].TimesCooked
+		}
+		return stats[i].LastCooked > stats[j].LastCooked
+	})
+	return stats, nil
+}
+
+func (s *cookLogService) DeleteEvent(eventID uint, userID uint) error {
+	event, err := s.CookLogRepo.FindByID(eventID)
+	if err != nil {
+		return err
+	}
+	if event.UserID != userID {
+		return ErrUnauthorized
+	}
+
+	return s.CookLogRepo.Delete(event)
+}
+
+// cookStats sums events, newest first as the repository lists them, per
+// recipe in the order each recipe was last cooked.
+func cookStats(events []models.CookEvent) []dto.RecipeCookStats {
+	index := make(map[uint]int)
+	ratings := make(map[uint][]int)
+	var stats []dto.RecipeCookStats
+
+	for _, e := range events {
+		i, ok := index[e.RecipeID]
+		if !ok {
+			i = len(stats)
+			index[e.RecipeID] = i
+			stats = append(stats, dto.RecipeCookStats{
+				RecipeID:   e.RecipeID,
+				RecipeName: e.Recipe.Name,
+				LastCooked: e.Date.Format("2006-01-02"),
+			})
+		}
+		stats[i].TimesCooked++
+		stats[i].ServingsCooked += e.Servings
+		if e.Rating != nil {
+			ratings[e.RecipeID] = append(ratings[e.RecipeID], *e.Rating)
+		}
+	}
+
+	for i := range stats {
+		if r := ratings[stats[i].RecipeID]; len(r) > 0 {
+			var sum int
+			for _, v := range r {
+				sum += v
+			}
+			avg := float64(sum) / float64(len(r))
+			stats[i].AverageRating = &avg
+		}
+	}
+	return stats
+}
+
+// cookDate reads an optional YYYY-MM-DD date, falling back to def.
+func cookDate(s string, def time.Time) (time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return def, nil
+	}
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, ErrInvalidCookDate
+	}
+	return date, nil
+}
+
+func cookEventResponse(e models.CookEvent) dto.CookEventResponse {
+	return dto.CookEventResponse{
+		ID:           e.ID,
+		RecipeID:     e.RecipeID,
+		RecipeName:   e.Recipe.Name,
+		MealPlanID:   e.MealPlanID,
+		Date:         e.Date.Format("2006-01-02"),
+		Servings:     e.Servings,
+		BaseServings: e.BaseServings,
+		Notes:        e.Notes,
+		Rating:       e.Rating,
+	}
+}
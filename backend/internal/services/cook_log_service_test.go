@@ -0,0 +1,117 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// uncheckedCookLog never finds an event for a meal plan, as if another
+// request were logging it at the same moment.
+type uncheckedCookLog struct {
+	repository.CookLogRepository
+}
+
+func (uncheckedCookLog) FindByMealPlan(uint) (*models.CookEvent, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestCookLog(t *testing.T) {
+	db := setupTestDB()
+	recipeRepo := repository.NewRecipeRepository(db)
+	mealPlanRepo := repository.NewMealPlanRepository(db)
+	recipes := NewRecipeService(recipeRepo, repository.NewCookLogRepository(db), db, nil)
+	service := NewCookLogService(repository.NewCookLogRepository(db), recipeRepo, mealPlanRepo)
+
+	id, err := recipes.CreateRecipe(1, dto.CreateRecipeRequest{
+		Name: "Chili", Servings: 4, Category: "Dinner",
+		Ingredients: []dto.RecipeIngredientRequest{{Name: "Beans", Amount: 400, Unit: "g"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := &models.MealPlan{UserID: 1, RecipeID: id, Date: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC), MealType: "dinner", TargetServings: 8}
+	if err := mealPlanRepo.Create(plan); err != nil {
+		t.Fatal(err)
+	}
+
+	rating := 4
+	event, err := service.CookMealPlan(plan.ID, 1, dto.CookRequest{Rating: &rating})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Date != "2025-02-03" || event.Servings != 8 || event.BaseServings != 4 || event.MealPlanID == nil {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if _, err := service.CookMealPlan(plan.ID, 1, dto.CookRequest{}); err != ErrAlreadyCooked {
+		t.Errorf("expected ErrAlreadyCooked, got %v", err)
+	}
+
+	// a request that raced past the check is stopped by the unique index
+	racing := NewCookLogService(uncheckedCookLog{repository.NewCookLogRepository(db)}, recipeRepo, mealPlanRepo)
+	if _, err := racing.CookMealPlan(plan.ID, 1, dto.CookRequest{}); err != ErrAlreadyCooked {
+		t.Errorf("expected ErrAlreadyCooked, got %v", err)
+	}
+
+	if _, err := service.CookRecipe(id, 1, 2, dto.CookRequest{Date: "2025-01-20", Notes: "half batch"}); err != nil {
+		t.Fatal(err)
+	}
+	// ad hoc events share a null meal plan
+	adHoc, err := service.CookRecipe(id, 1, 0, dto.CookRequest{Date: "2025-01-21"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.DeleteEvent(adHoc.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.CookRecipe(id, 1, 0, dto.CookRequest{Date: "20/01/2025"}); err != ErrInvalidCookDate {
+		t.Errorf("expected ErrInvalidCookDate, got %v", err)
+	}
+	if _, err := service.CookRecipe(id, 2, 0, dto.CookRequest{}); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+
+	history, err := service.RecipeHistory(id, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.TimesCooked != 2 || history.LastCooked != "2025-02-03" || history.ServingsCooked != 10 ||
+		history.AverageRating == nil || *history.AverageRating != 4 || len(history.Events) != 2 {
+		t.Errorf("unexpected history %+v", history)
+	}
+	if history.Events[1].Notes != "half batch" || history.Events[1].RecipeName != "Chili" {
+		t.Errorf("unexpected events %+v", history.Events)
+	}
+
+	detail, err := recipes.GetRecipeByID(id, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.LastCooked != "2025-02-03" || detail.TimesCooked != 2 {
+		t.Errorf("unexpected detail %q / %d", detail.LastCooked, detail.TimesCooked)
+	}
+
+	// deleting the plan keeps what was cooked
+	if err := mealPlanRepo.Delete(plan); err != nil {
+		t.Fatal(err)
+	}
+	log, err := service.GetCookLog(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 2 || log[0].MealPlanID != nil {
+		t.Errorf("unexpected log %+v", log)
+	}
+
+	stats, err := service.CookStats(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 || stats[0].RecipeName != "Chili" || stats[0].TimesCooked != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/pricing"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+)
+
+type CostService interface {
+	// RecipeCost prices a recipe at servings, 0 for the recipe's own.
+	RecipeCost(recipeID uint, userID uint, servings int) (*dto.RecipeCostResponse, error)
+	MealPlanCost(userID uint, startDateStr, endDateStr string) (*dto.MealPlanCostResponse, error)
+}
+
+type costService struct {
+	RecipeRepo   repository.RecipeRepository
+	MealPlanRepo repository.MealPlanRepository
+	PriceRepo    repository.PriceRepository
+}
+
+func NewCostService(
+	recipeRepo repository.RecipeRepository,
+	mealPlanRepo repository.MealPlanRepository,
+	priceRepo repository.PriceRepository,
+) CostService {
+	return &costService{RecipeRepo: recipeRepo, MealPlanRepo: mealPlanRepo, PriceRepo: priceRepo}
+}
+
+func (s *costService) RecipeCost(recipeID uint, userID uint, servings int) (*dto.RecipeCostResponse, error) {
+	if servings < 0 {
+		return nil, ErrInvalidServings
+	}
+
+	recipe, err := s.RecipeRepo.FindByIDWithDetails(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	baseServings := recipe.Servings
+	if baseServings == 0 {
+		baseServings = 1
+	}
+	if servings == 0 {
+		servings = baseServings
+	}
+
+	prices, err := s.PriceRepo.FindLatest(userID, recipeIngredientIDs(recipe))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.RecipeCostResponse{
+		RecipeID: recipe.ID,
+		Name:     recipe.Name,
+		Servings: servings,
+		Items:    []dto.CostItem{},
+		Unpriced: []dto.UnpricedItem{},
+	}
+	factor := float64(servings) / float64(baseServings)
+	for _, ri := range recipe.Ingredients {
+		item, err := priceIngredient(prices, ri.Ingredient, ri.Quantity*factor, ri.Unit)
+		if err != nil {
+			resp.Unpriced = append(resp.Unpriced, unpricedItem(item, err))
+			continue
+		}
+		resp.Items = append(resp.Items, item)
+		resp.Total += item.Cost
+	}
+
+	resp.Total = pricing.Round(resp.Total)
+	resp.PerServing = pricing.Round(resp.Total / float64(servings))
+	return resp, nil
+}
+
+// MealPlanCost prices the shopping for a date range, with the ingredients
+// summed as Generate sums them for a shopping list, and what each planned
+// meal costs at its target servings.
+func (s *costService) MealPlanCost(userID uint, startDateStr, endDateStr string) (*dto.MealPlanCostResponse, error) {
+	layout := "2006-01-02"
+	start, err := time.Parse(layout, startDateStr)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(layout, endDateStr)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, ErrInvalidDateRange
+	}
+
+	plans, err := s.MealPlanRepo.FindByUserAndDateRange(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	for i := range plans {
+		ids = append(ids, recipeIngredientIDs(&plans[i].Recipe)...)
+	}
+	prices, err := s.PriceRepo.FindLatest(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.MealPlanCostResponse{
+		StartDate: startDateStr,
+		EndDate:   endDateStr,
+		Items:     []dto.CostItem{},
+		Unpriced:  []dto.UnpricedItem{},
+		Days:      []dto.DayCost{},
+	}
+
+	for _, line := range aggregateMealPlanIngredients(plans) {
+		item, err := priceIngredient(prices, line.Ingredient, line.Quantity, line.Unit)
+		if err != nil {
+			resp.Unpriced = append(resp.Unpriced, unpricedItem(item, err))
+			continue
+		}
+		resp.Items = append(resp.Items, item)
+		resp.Total += item.Cost
+	}
+	resp.Total = pricing.Round(resp.Total)
+
+	days := make(map[string]int)
+	for _, mp := range plans {
+		date := mp.Date.Format(layout)
+		i, ok := days[date]
+		if !ok {
+			i = len(resp.Days)
+			days[date] = i
+			resp.Days = append(resp.Days, dto.DayCost{Date: date, Meals: []dto.MealCost{}})
+		}
+
+		meal := mealCost(mp, prices)
+		resp.Days[i].Meals = append(resp.Days[i].Meals, meal)
+		resp.Days[i].Total = pricing.Round(resp.Days[i].Total + meal.Cost)
+	}
+	sortDayCosts(resp.Days)
+
+	return resp, nil
+}
+
+// mealCost prices one planned meal, scaled to its target servings the same
+// way as on the shopping list.
+func mealCost(mp models.MealPlan, prices map[uint]models.IngredientPrice) dto.MealCost {
+	baseServings := mp.Recipe.Servings
+	if baseServings == 0 {
+		baseServings = 1
+	}
+	ratio := float64(mp.TargetServings) / float64(baseServings)
+
+	meal := dto.MealCost{
+		MealPlanID: mp.ID,
+		MealType:   mp.MealType,
+		RecipeID:   mp.Recipe.ID,
+		RecipeName: mp.Recipe.Name,
+		Servings:   mp.TargetServings,
+		Complete:   true,
+	}
+	for _, ri := range mp.Recipe.Ingredients {
+		item, err := priceIngredient(prices, ri.Ingredient, ri.Quantity*ratio, ri.Unit)
+		if err != nil {
+			meal.Complete = false
+			continue
+		}
+		meal.Cost += item.Cost
+	}
+	meal.Cost = pricing.Round(meal.Cost)
+	return meal
+}
+
+func sortDayCosts(days []dto.DayCost) {
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	for _, day := range days {
+		meals := day.Meals
+		sort.SliceStable(meals, func(i, j int) bool {
+			return mealTypeRank(meals[i].MealType) < mealTypeRank(meals[j].MealType)
+		})
+	}
+}
+
+func recipeIngredientIDs(recipe *models.Recipe) []uint {
+	ids := make([]uint, 0, len(recipe.Ingredients))
+	for _, ri := range recipe.Ingredients {
+		ids = append(ids, ri.Ingredient.ID)
+	}
+	return ids
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+)
+
+func pancakes(id uint) (*models.Recipe, error) {
+	flour := models.Ingredient{ID: 1, Name: "Flour"}
+	milk := models.Ingredient{ID: 2, Name: "Milk", Density: ptr(1.03)}
+	egg := models.Ingredient{ID: 3, Name: "Egg", PieceWeight: ptr(50)}
+	vanilla := models.Ingredient{ID: 4, Name: "Vanilla"}
+	return &models.Recipe{
+		ID: id, UserID: 1, Name: "Pancakes", Servings: 4,
+		Ingredients: []models.RecipeIngredient{
+			{IngredientID: 1, Ingredient: flour, Quantity: 250, Unit: "g"},
+			{IngredientID: 2, Ingredient: milk, Quantity: 2, Unit: "cup"},
+			{IngredientID: 3, Ingredient: egg, Quantity: 2, Unit: ""},
+			{IngredientID: 4, Ingredient: vanilla, Quantity: 1, Unit: "tsp"},
+		},
+	}, nil
+}
+
+func pancakePrices(uint, []uint) (map[uint]models.IngredientPrice, error) {
+	return map[uint]models.IngredientPrice{
+		1: {ID: 11, IngredientID: 1, Price: 2, Quantity: 1, Unit: "kg", Store: "Store A"},
+		2: {ID: 12, IngredientID: 2, Price: 1.2, Quantity: 1, Unit: "l"},
+		3: {ID: 13, IngredientID: 3, Price: 3.6, Quantity: 1, Unit: "dozen"},
+	}, nil
+}
+
+func TestRecipeCost(t *testing.T) {
+	service := NewCostService(&MockRecipeRepoForScale{FindByIDWithDetailsFn: pancakes}, nil,
+		&MockPriceRepo{FindLatestFn: pancakePrices})
+
+	resp, err := service.RecipeCost(1, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 0.50 flour + 0.57 for 473 ml milk + 0.60 for two eggs
+	if resp.Servings != 4 || resp.Total != 1.67 || resp.PerServing != 0.42 {
+		t.Errorf("unexpected cost %+v", resp)
+	}
+	if len(resp.Items) != 3 || resp.Items[0].Store != "Store A" || resp.Items[0].PriceID != 11 {
+		t.Errorf("unexpected items %+v", resp.Items)
+	}
+	if len(resp.Unpriced) != 1 || resp.Unpriced[0].Name != "Vanilla" {
+		t.Errorf("unexpected unpriced %+v", resp.Unpriced)
+	}
+
+	scaled, err := service.RecipeCost(1, 1, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scaled.Total != 3.34 || scaled.PerServing != 0.42 {
+		t.Errorf("unexpected scaled cost %+v", scaled)
+	}
+
+	if _, err := service.RecipeCost(1, 2, 0); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if _, err := service.RecipeCost(1, 1, -1); err != ErrInvalidServings {
+		t.Errorf("expected ErrInvalidServings, got %v", err)
+	}
+}
+
+func TestMealPlanCost(t *testing.T) {
+	recipe, _ := pancakes(1)
+	monday := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	plans := []models.MealPlan{
+		{ID: 1, Date: monday.AddDate(0, 0, 1), MealType: "dinner", TargetServings: 4, Recipe: *recipe},
+		{ID: 2, Date: monday, MealType: "dinner", TargetServings: 4, Recipe: *recipe},
+		{ID: 3, Date: monday, MealType: "breakfast", TargetServings: 8, Recipe: *recipe},
+	}
+	service := NewCostService(nil,
+		&MockMealPlanRepo{FindByUserAndDateRangeFn: func(uint, time.Time, time.Time) ([]models.MealPlan, error) {
+			return plans, nil
+		}},
+		&MockPriceRepo{FindLatestFn: pancakePrices})
+
+	resp, err := service.MealPlanCost(1, "2025-01-06", "2025-01-12")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1 kg flour, 8 cups milk and 8 eggs over the week
+	if resp.Total != 6.67 || len(resp.Items) != 3 || resp.Items[0].Quantity != 1000 {
+		t.Errorf("unexpected totals %+v", resp)
+	}
+	if len(resp.Unpriced) != 1 || resp.Unpriced[0].Name != "Vanilla" {
+		t.Errorf("unexpected unpriced %+v", resp.Unpriced)
+	}
+
+	if len(resp.Days) != 2 || resp.Days[0].Date != "2025-01-06" || resp.Days[0].Total != 5.01 {
+		t.Fatalf("unexpected days %+v", resp.Days)
+	}
+	meals := resp.Days[0].Meals
+	if meals[0].MealType != "breakfast" || meals[0].Cost != 3.34 || meals[0].Complete {
+		t.Errorf("unexpected meals %+v", meals)
+	}
+
+	if _, err := service.MealPlanCost(1, "2025-01-12", "2025-01-06"); err != ErrInvalidDateRange {
+		t.Errorf("expected ErrInvalidDateRange, got %v", err)
+	}
+}
@@ -2,17 +2,50 @@ package services
 
 import (
 	"errors"
+	"io"
+	"math"
+	"sort"
+	"strings"
 
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/aisle"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/diet"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/nutrition"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/parser"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"gorm.io/gorm"
 )
 
-var ErrIngredientUnauthorized = errors.New("not authorized to modify ingredient")
+var (
+	ErrIngredientUnauthorized = errors.New("not authorized to modify ingredient")
+	ErrIngredientExists       = errors.New("an ingredient with this name already exists")
+	ErrAliasTaken             = errors.New("name already refers to an ingredient")
+	ErrInvalidMerge           = errors.New("canonical ingredient cannot be one of its duplicates")
+)
+
+const (
+	defaultDuplicateThreshold = 0.8
+	defaultDuplicateLimit     = 50
+
+	defaultSearchLimit = 10
+	searchCandidates   = 200 // rows fetched for ranking
+	minTrigramScore    = 0.2
+	maxUsageBoost      = 0.09 // kept below the 0.1 between match kinds
+)
 
 type IngredientService interface {
 	CreateIngredient(req dto.CreateIngredientRequest) error
 	GetIngredients() ([]dto.IngredientMasterResponse, error)
+	SearchIngredients(userID uint, query dto.IngredientSearchQuery) ([]dto.IngredientSearchResult, error)
+	UpdateIngredient(id uint, req dto.UpdateIngredientRequest) error
+	ParseIngredients(req dto.ParseIngredientsRequest) dto.ParseIngredientsResponse
+	ImportNutrition(r io.Reader, query dto.NutritionImportQuery) (*dto.NutritionImportResponse, error)
+
+	AddAlias(ingredientID uint, req dto.AliasRequest) (*dto.AliasResponse, error)
+	DeleteAlias(aliasID uint) error
+	MergeIngredients(req dto.MergeIngredientsRequest) (*dto.MergeIngredientsResponse, error)
+	SuggestDuplicates(query dto.DuplicateQuery) ([]dto.DuplicateSuggestion, error)
 
 	AddIngredientToRecipe(recipeID uint, userID uint, req dto.AddRecipeIngredientRequest) error
 	GetRecipeIngredients(recipeID uint, userID uint) ([]dto.IngredientResponse, error)
@@ -38,8 +71,30 @@ func NewIngredientService(
 }
 
 func (s *ingredientService) CreateIngredient(req dto.CreateIngredientRequest) error {
+	flags, err := ingredientFlags(req.Allergens, req.Diets)
+	if err != nil {
+		return err
+	}
+
+	name := parser.CleanName(req.Name)
+	if err := s.checkNameFree(name, 0); err != nil {
+		return err
+	}
+	shelf := aisle.Guess(name)
+	if req.Aisle != "" {
+		if shelf, err = aisle.Normalize(req.Aisle); err != nil {
+			return err
+		}
+	}
+
 	ingredient := &models.Ingredient{
-		Name: req.Name,
+		Name:        name,
+		Density:     req.Density,
+		PieceWeight: req.PieceWeight,
+		Nutrition:   nutritionModel(req.Nutrition),
+		Allergens:   flags.Allergens,
+		Diets:       flags.Diets,
+		Aisle:       shelf,
 	}
 	return s.IngredientRepo.Create(ingredient)
 }
@@ -52,15 +107,485 @@ func (s *ingredientService) GetIngredients() ([]dto.IngredientMasterResponse, er
 
 	var response []dto.IngredientMasterResponse
 	for _, ing := range ingredients {
-		response = append(response, dto.IngredientMasterResponse{
-			ID:   ing.ID,
-			Name: ing.Name,
-		})
+		response = append(response, ingredientMasterResponse(ing))
 	}
 
 	return response, nil
 }
 
+// SearchIngredients ranks ingredients for a typeahead: an exact name first,
+// then names starting with the text, then a word starting with it, then
+// containing it, then trigram matches that allow for typos. Aliases count
+// as names. Ingredients the user cooks with often are lifted within their
+// kind of match, by at most maxUsageBoost so a heavily used prefix match
+// cannot pass an exact one.
+func (s *ingredientService) SearchIngredients(userID uint, query dto.IngredientSearchQuery) ([]dto.IngredientSearchResult, error) {
+	text := strings.ToLower(parser.CleanName(query.Q))
+	results := []dto.IngredientSearchResult{}
+	if text == "" {
+		return results, nil
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	candidates, err := s.IngredientRepo.Search(text, searchCandidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return results, nil
+	}
+
+	ids := make([]uint, len(candidates))
+	for i, ing := range candidates {
+		ids[i] = ing.ID
+	}
+	uses, err := s.IngredientRepo.UserUsageCounts(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ing := range candidates {
+		score, alias := searchScore(text, ing.Name), ""
+		for _, a := range ing.Aliases {
+			if as := searchScore(text, a.Name); as > score {
+				score, alias = as, a.Name
+			}
+		}
+		if score == 0 {
+			continue
+		}
+
+		score += min(maxUsageBoost, 0.03*math.Log1p(float64(uses[ing.ID])))
+		results = append(results, dto.IngredientSearchResult{
+			ID:    ing.ID,
+			Name:  ing.Name,
+			Alias: alias,
+			Uses:  uses[ing.ID],
+			Score: roundScore(score),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if len(results[i].Name) != len(results[j].Name) {
+			return len(results[i].Name) < len(results[j].Name)
+		}
+		return strings.ToLower(results[i].Name) < strings.ToLower(results[j].Name)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchScore rates a name against lower-cased search text, 0 for no match.
+func searchScore(text, name string) float64 {
+	lower := strings.ToLower(name)
+	switch {
+	case parser.SameName(text, name):
+		return 1
+	case strings.HasPrefix(lower, text):
+		return 0.9
+	case strings.Contains(" "+lower, " "+text):
+		return 0.8
+	case strings.Contains(lower, text):
+		return 0.7
+	}
+	if sim := parser.TrigramSimilarity(text, name); sim >= minTrigramScore {
+		return 0.6 * sim
+	}
+	return 0
+}
+
+func ingredientMasterResponse(ing models.Ingredient) dto.IngredientMasterResponse {
+	aliases := []dto.AliasResponse{}
+	for _, a := range ing.Aliases {
+		aliases = append(aliases, dto.AliasResponse{ID: a.ID, Name: a.Name})
+	}
+	return dto.IngredientMasterResponse{
+		ID:          ing.ID,
+		Name:        ing.Name,
+		Density:     ing.Density,
+		PieceWeight: ing.PieceWeight,
+		Nutrition:   nutritionDTO(ing.Nutrition),
+		Allergens:   ing.Allergens,
+		Diets:       ing.Diets,
+		Aisle:       aisle.Of(ing.Aisle, ing.Name),
+		Aliases:     aliases,
+	}
+}
+
+// checkNameFree returns ErrIngredientExists when name, folded for case,
+// spacing and plurals or through an alias, already belongs to an ingredient
+// other than id.
+func (s *ingredientService) checkNameFree(name string, id uint) error {
+	existing, err := s.IngredientRepo.FindByName(name)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil
+	case err != nil:
+		return err
+	case existing.ID != id:
+		return ErrIngredientExists
+	}
+	return nil
+}
+
+func (s *ingredientService) UpdateIngredient(id uint, req dto.UpdateIngredientRequest) error {
+	flags, err := ingredientFlags(req.Allergens, req.Diets)
+	if err != nil {
+		return err
+	}
+
+	ingredient, err := s.IngredientRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	name := parser.CleanName(req.Name)
+	if err := s.checkNameFree(name, id); err != nil {
+		return err
+	}
+	if req.Aisle != "" {
+		if ingredient.Aisle, err = aisle.Normalize(req.Aisle); err != nil {
+			return err
+		}
+	}
+
+	ingredient.Name = name
+	ingredient.Density = req.Density
+	ingredient.PieceWeight = req.PieceWeight
+	ingredient.Nutrition = nutritionModel(req.Nutrition)
+	ingredient.Allergens = flags.Allergens
+	ingredient.Diets = flags.Diets
+
+	return s.IngredientRepo.Update(ingredient)
+}
+
+func (s *ingredientService) ParseIngredients(req dto.ParseIngredientsRequest) dto.ParseIngredientsResponse {
+	lines, errs := parser.ParseLines(req.Text)
+
+	response := dto.ParseIngredientsResponse{
+		Ingredients: []dto.ParsedIngredientResponse{},
+		Errors:      []string{},
+	}
+	for _, line := range lines {
+		response.Ingredients = append(response.Ingredients, dto.ParsedIngredientResponse{
+			Original:  line.Original,
+			Name:      line.Name,
+			Amount:    line.Amount,
+			AmountMax: line.AmountMax,
+			Unit:      line.Unit,
+			Size:      line.Size,
+			Note:      line.Note,
+		})
+	}
+	for _, err := range errs {
+		response.Errors = append(response.Errors, err.Error())
+	}
+
+	return response
+}
+
+// ImportNutrition loads per-100 g nutrition from a CSV such as a USDA
+// extract, matching ingredients by name the way recipes do: ignoring case,
+// spacing and plurals and following aliases.
+func (s *ingredientService) ImportNutrition(r io.Reader, query dto.NutritionImportQuery) (*dto.NutritionImportResponse, error) {
+	records, err := nutrition.ReadCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// rows naming an ingredient already seen share it, whether it came from
+	// the catalog or was created by an earlier row
+	byID := make(map[uint]*models.Ingredient)
+	created := make(map[string]*models.Ingredient)
+	find := func(name string) (*models.Ingredient, bool, error) {
+		for _, candidate := range parser.NameCandidates(name) {
+			if ingredient, ok := created[candidate]; ok {
+				return ingredient, true, nil
+			}
+		}
+		ingredient, err := s.IngredientRepo.FindByName(name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if seen, ok := byID[ingredient.ID]; ok {
+			return seen, true, nil
+		}
+		byID[ingredient.ID] = ingredient
+		return ingredient, true, nil
+	}
+
+	resp := &dto.NutritionImportResponse{Kept: []string{}, NotFound: []string{}}
+	changed := make(map[*models.Ingredient]bool)
+	var save []*models.Ingredient
+
+	for _, record := range records {
+		facts := models.Nutrition(record.Facts)
+		name := parser.CleanName(record.Name)
+
+		ingredient, ok, err := find(name)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case !ok && !query.Create:
+			resp.NotFound = append(resp.NotFound, name)
+			continue
+		case !ok:
+			ingredient = &models.Ingredient{Name: name}
+			for _, candidate := range parser.NameCandidates(name) {
+				created[candidate] = ingredient
+			}
+			resp.Created++
+		case ingredient.Nutrition != nil && !query.Overwrite && !changed[ingredient]:
+			resp.Kept = append(resp.Kept, ingredient.Name)
+			continue
+		case !changed[ingredient]:
+			resp.Updated++
+		}
+
+		// a name repeated in the file takes its last row
+		ingredient.Nutrition = &facts
+		if !changed[ingredient] {
+			changed[ingredient] = true
+			save = append(save, ingredient)
+		}
+	}
+
+	if err := s.IngredientRepo.SaveAll(save); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AddAlias gives an ingredient another name that recipes and imports can
+// use for it.
+func (s *ingredientService) AddAlias(ingredientID uint, req dto.AliasRequest) (*dto.AliasResponse, error) {
+	if _, err := s.IngredientRepo.FindByID(ingredientID); err != nil {
+		return nil, err
+	}
+
+	name := parser.NameForms(req.Name)[0]
+	_, err := s.IngredientRepo.FindByName(name)
+	if err == nil {
+		return nil, ErrAliasTaken
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	alias := &models.IngredientAlias{Name: name, IngredientID: ingredientID}
+	if err := s.IngredientRepo.CreateAlias(alias); err != nil {
+		return nil, err
+	}
+	return &dto.AliasResponse{ID: alias.ID, Name: alias.Name}, nil
+}
+
+func (s *ingredientService) DeleteAlias(aliasID uint) error {
+	if _, err := s.IngredientRepo.FindAliasByID(aliasID); err != nil {
+		return err
+	}
+	return s.IngredientRepo.DeleteAlias(aliasID)
+}
+
+// MergeIngredients folds duplicates into the canonical ingredient. Recipe
+// lines, shopping list items, prices, pantry stock and aliases move over,
+// the duplicates' names become aliases, and details the canonical row lacks
+// are taken from them; allergens are combined so none is lost.
+func (s *ingredientService) MergeIngredients(req dto.MergeIngredientsRequest) (*dto.MergeIngredientsResponse, error) {
+	canonical, err := s.IngredientRepo.FindByID(req.CanonicalID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool)
+	var duplicates []models.Ingredient
+	for _, id := range req.DuplicateIDs {
+		if id == canonical.ID {
+			return nil, ErrInvalidMerge
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		dup, err := s.IngredientRepo.FindByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := fillIngredientGaps(canonical, dup); err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, *dup)
+	}
+
+	result, err := s.IngredientRepo.Merge(canonical, duplicates)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := s.IngredientRepo.FindByID(canonical.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.MergeIngredientsResponse{
+		Ingredient:        ingredientMasterResponse(*merged),
+		Merged:            []string{},
+		RecipeIngredients: result.RecipeIngredients,
+		ShoppingListItems: result.ShoppingListItems,
+		Prices:            result.Prices,
+		PantryItems:       result.PantryItems,
+	}
+	for _, d := range duplicates {
+		resp.Merged = append(resp.Merged, d.Name)
+	}
+	return resp, nil
+}
+
+func fillIngredientGaps(canonical, dup *models.Ingredient) error {
+	if canonical.Density == nil {
+		canonical.Density = dup.Density
+	}
+	if canonical.PieceWeight == nil {
+		canonical.PieceWeight = dup.PieceWeight
+	}
+	if canonical.Nutrition == nil {
+		canonical.Nutrition = dup.Nutrition
+	}
+
+	diets := canonical.Diets
+	if diets == nil {
+		diets = dup.Diets
+	}
+	flags, err := ingredientFlags(append(append([]string{}, canonical.Allergens...), dup.Allergens...), diets)
+	if err != nil {
+		return err
+	}
+	canonical.Allergens = flags.Allergens
+	canonical.Diets = flags.Diets
+	return nil
+}
+
+// SuggestDuplicates pairs up ingredients whose names look like the same
+// thing, best match first, suggesting the more used of each pair as the one
+// to keep.
+func (s *ingredientService) SuggestDuplicates(query dto.DuplicateQuery) ([]dto.DuplicateSuggestion, error) {
+	threshold := query.Threshold
+	if threshold == 0 {
+		threshold = defaultDuplicateThreshold
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultDuplicateLimit
+	}
+
+	ingredients, err := s.IngredientRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	uses, err := s.IngredientRepo.UsageCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []dto.DuplicateSuggestion{}
+	for i := range ingredients {
+		for j := i + 1; j < len(ingredients); j++ {
+			a, b := ingredients[i], ingredients[j]
+			score, reason := duplicateScore(a.Name, b.Name)
+			if score < threshold {
+				continue
+			}
+
+			keep, drop := a, b
+			if uses[b.ID] > uses[a.ID] || (uses[b.ID] == uses[a.ID] && b.ID < a.ID) {
+				keep, drop = b, a
+			}
+			suggestions = append(suggestions, dto.DuplicateSuggestion{
+				Canonical: dto.DuplicateIngredient{ID: keep.ID, Name: keep.Name, Uses: uses[keep.ID]},
+				Duplicate: dto.DuplicateIngredient{ID: drop.ID, Name: drop.Name, Uses: uses[drop.ID]},
+				Score:     roundScore(score),
+				Reason:    reason,
+			})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return strings.ToLower(suggestions[i].Canonical.Name) < strings.ToLower(suggestions[j].Canonical.Name)
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// duplicateScore rates two names as the same ingredient, also trying their
+// words sorted so "pepper, black" meets "black pepper".
+func duplicateScore(a, b string) (float64, string) {
+	if parser.SameName(a, b) {
+		return 1, "same name"
+	}
+	score, reason := parser.Similarity(a, b), "spelling"
+	if sorted := parser.Similarity(sortedWords(a), sortedWords(b)); sorted > score {
+		score, reason = sorted, "word order"
+	}
+	return score, reason
+}
+
+func sortedWords(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// ingredientFlags normalises allergen and diet names as given for an
+// ingredient and drops diets its allergens rule out. Diets stay nil when
+// none were given, as nobody has said which the ingredient suits.
+func ingredientFlags(allergens, diets []string) (diet.Flags, error) {
+	var flags diet.Flags
+	var err error
+	if flags.Allergens, err = diet.NormalizeAllergens(allergens); err != nil {
+		return flags, err
+	}
+	if diets != nil {
+		if flags.Diets, err = diet.NormalizeDiets(diets); err != nil {
+			return flags, err
+		}
+	}
+	return diet.Resolve(flags), nil
+}
+
+func nutritionModel(facts *dto.NutritionFacts) *models.Nutrition {
+	if facts == nil {
+		return nil
+	}
+	n := models.Nutrition(*facts)
+	return &n
+}
+
+func nutritionDTO(n *models.Nutrition) *dto.NutritionFacts {
+	if n == nil {
+		return nil
+	}
+	facts := dto.NutritionFacts(*n)
+	return &facts
+}
+
 func (s *ingredientService) AddIngredientToRecipe(
 	recipeID uint,
 	userID uint,
@@ -109,6 +634,7 @@ func (s *ingredientService) GetRecipeIngredients(
 			Name:     item.Ingredient.Name,
 			Quantity: item.Quantity,
 			Unit:     item.Unit,
+			Note:     item.Note,
 		})
 	}
 
@@ -2,10 +2,14 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
 	"gorm.io/gorm"
 )
 
@@ -13,6 +17,18 @@ type MockIngredientRepository struct {
 	CreateFn   func(*models.Ingredient) error
 	FindAllFn  func() ([]models.Ingredient, error)
 	FindByIDFn func(uint) (*models.Ingredient, error)
+	UpdateFn   func(*models.Ingredient) error
+	SaveAllFn  func([]*models.Ingredient) error
+
+	FindByNameFn    func(string) (*models.Ingredient, error)
+	UsageCountsFn   func() (map[uint]int64, error)
+	CreateAliasFn   func(*models.IngredientAlias) error
+	FindAliasByIDFn func(uint) (*models.IngredientAlias, error)
+	DeleteAliasFn   func(uint) error
+	MergeFn         func(*models.Ingredient, []models.Ingredient) (*repository.MergeResult, error)
+
+	SearchFn          func(string, int) ([]models.Ingredient, error)
+	UserUsageCountsFn func(uint, []uint) (map[uint]int64, error)
 }
 
 func (m *MockIngredientRepository) Create(i *models.Ingredient) error {
@@ -34,6 +50,76 @@ func (m *MockIngredientRepository) FindByID(id uint) (*models.Ingredient, error)
 	return nil, gorm.ErrRecordNotFound
 }
 
+func (m *MockIngredientRepository) Update(i *models.Ingredient) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(i)
+	}
+	return nil
+}
+
+func (m *MockIngredientRepository) SaveAll(is []*models.Ingredient) error {
+	if m.SaveAllFn != nil {
+		return m.SaveAllFn(is)
+	}
+	return nil
+}
+
+func (m *MockIngredientRepository) FindByName(name string) (*models.Ingredient, error) {
+	if m.FindByNameFn != nil {
+		return m.FindByNameFn(name)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockIngredientRepository) UsageCounts() (map[uint]int64, error) {
+	if m.UsageCountsFn != nil {
+		return m.UsageCountsFn()
+	}
+	return map[uint]int64{}, nil
+}
+
+func (m *MockIngredientRepository) UserUsageCounts(userID uint, ids []uint) (map[uint]int64, error) {
+	if m.UserUsageCountsFn != nil {
+		return m.UserUsageCountsFn(userID, ids)
+	}
+	return map[uint]int64{}, nil
+}
+
+func (m *MockIngredientRepository) Search(text string, limit int) ([]models.Ingredient, error) {
+	if m.SearchFn != nil {
+		return m.SearchFn(text, limit)
+	}
+	return []models.Ingredient{}, nil
+}
+
+func (m *MockIngredientRepository) CreateAlias(a *models.IngredientAlias) error {
+	if m.CreateAliasFn != nil {
+		return m.CreateAliasFn(a)
+	}
+	return nil
+}
+
+func (m *MockIngredientRepository) FindAliasByID(id uint) (*models.IngredientAlias, error) {
+	if m.FindAliasByIDFn != nil {
+		return m.FindAliasByIDFn(id)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *MockIngredientRepository) DeleteAlias(id uint) error {
+	if m.DeleteAliasFn != nil {
+		return m.DeleteAliasFn(id)
+	}
+	return nil
+}
+
+func (m *MockIngredientRepository) Merge(c *models.Ingredient, d []models.Ingredient) (*repository.MergeResult, error) {
+	if m.MergeFn != nil {
+		return m.MergeFn(c, d)
+	}
+	return &repository.MergeResult{}, nil
+}
+
 type MockRecipeIngredientRepository struct {
 	CreateFn         func(*models.RecipeIngredient) error
 	FindByRecipeIDFn func(uint) ([]models.RecipeIngredient, error)
@@ -75,6 +161,9 @@ func (m *MockRecipeRepoForIngredient) FindByID(id uint) (*models.Recipe, error)
 }
 func (m *MockRecipeRepoForIngredient) Create(*models.Recipe) error                { return nil }
 func (m *MockRecipeRepoForIngredient) FindByUserID(uint) ([]models.Recipe, error) { return nil, nil }
+func (m *MockRecipeRepoForIngredient) Search(uint, repository.RecipeFilter) ([]models.Recipe, int64, error) {
+	return nil, 0, nil
+}
 func (m *MockRecipeRepoForIngredient) FindByIDWithDetails(uint) (*models.Recipe, error) {
 	return nil, gorm.ErrRecordNotFound
 }
@@ -119,6 +208,35 @@ func TestGetIngredients_Error(t *testing.T) {
 	}
 }
 
+func TestUpdateIngredient_Success(t *testing.T) {
+	var saved *models.Ingredient
+	density := 0.85
+	service := NewIngredientService(
+		&MockIngredientRepository{
+			FindByIDFn: func(id uint) (*models.Ingredient, error) {
+				return &models.Ingredient{ID: id, Name: "sugar"}, nil
+			},
+			UpdateFn: func(i *models.Ingredient) error { saved = i; return nil },
+		},
+		&MockRecipeIngredientRepository{}, &MockRecipeRepoForIngredient{},
+	)
+
+	err := service.UpdateIngredient(3, dto.UpdateIngredientRequest{Name: "Sugar", Density: &density})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if saved.Name != "Sugar" || saved.Density == nil || *saved.Density != 0.85 || saved.PieceWeight != nil {
+		t.Fatalf("unexpected saved ingredient %+v", saved)
+	}
+}
+
+func TestUpdateIngredient_NotFound(t *testing.T) {
+	service := NewIngredientService(&MockIngredientRepository{}, &MockRecipeIngredientRepository{}, &MockRecipeRepoForIngredient{})
+	if err := service.UpdateIngredient(3, dto.UpdateIngredientRequest{Name: "X"}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
 func TestAddIngredientToRecipe_Success(t *testing.T) {
 	service := NewIngredientService(
 		&MockIngredientRepository{},
@@ -251,3 +369,230 @@ func TestRemoveRecipeIngredient_RecipeError(t *testing.T) {
 		t.Fatal("expected error when recipe lookup fails")
 	}
 }
+
+func TestParseIngredients(t *testing.T) {
+	service := NewIngredientService(&MockIngredientRepository{}, &MockRecipeIngredientRepository{}, &MockRecipeRepoForIngredient{})
+	res := service.ParseIngredients(dto.ParseIngredientsRequest{Text: "1 ½ cups milk\n2 cups\n3 large eggs"})
+	if len(res.Ingredients) != 2 || len(res.Errors) != 1 {
+		t.Fatalf("expected 2 ingredients and 1 error, got %+v", res)
+	}
+	if res.Ingredients[0].Amount != 1.5 || res.Ingredients[0].Unit != "cup" || res.Ingredients[1].Size != "large" {
+		t.Fatalf("unexpected parse result %+v", res.Ingredients)
+	}
+}
+
+func TestCreateIngredient_NameTaken(t *testing.T) {
+	var looked string
+	service := NewIngredientService(
+		&MockIngredientRepository{
+			FindByNameFn: func(name string) (*models.Ingredient, error) {
+				looked = name
+				return &models.Ingredient{ID: 4, Name: "Onion"}, nil
+			},
+		},
+		&MockRecipeIngredientRepository{}, &MockRecipeRepoForIngredient{},
+	)
+
+	err := service.CreateIngredient(dto.CreateIngredientRequest{Name: "  red   onions "})
+	if err != ErrIngredientExists {
+		t.Fatalf("expected ErrIngredientExists, got %v", err)
+	}
+	if looked != "red onions" {
+		t.Errorf("looked up %q, want the cleaned name", looked)
+	}
+}
+
+func TestIngredientAliasesAndMerge(t *testing.T) {
+	db := setupTestDB()
+	ingredientRepo := repository.NewIngredientRepository(db)
+	service := NewIngredientService(ingredientRepo, repository.NewRecipeIngredientRepository(db), repository.NewRecipeRepository(db))
+	recipes := NewRecipeService(repository.NewRecipeRepository(db), repository.NewCookLogRepository(db), db, nil)
+
+	density := 1.03
+	chickpea := models.Ingredient{Name: "Chickpea", Allergens: []string{"legumes"}}
+	garbanzo := models.Ingredient{Name: "Garbanzo Beans", Density: &density, Allergens: []string{"sesame"}}
+	db.Create(&chickpea)
+	db.Create(&garbanzo)
+
+	t.Run("Recipes Reuse Plural And Spaced Names", func(t *testing.T) {
+		id, err := recipes.CreateRecipe(1, dto.CreateRecipeRequest{
+			Name: "Hummus", Servings: 4, Category: "Snack",
+			Ingredients: []dto.RecipeIngredientRequest{
+				{Name: " chickpeas ", Amount: 400, Unit: "g"},
+				{Name: "garbanzo   beans", Amount: 100, Unit: "g"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var lines []models.RecipeIngredient
+		db.Where("recipe_id = ?", id).Order("id").Find(&lines)
+		if len(lines) != 2 || lines[0].IngredientID != chickpea.ID || lines[1].IngredientID != garbanzo.ID {
+			t.Fatalf("unexpected recipe lines %+v", lines)
+		}
+	})
+
+	t.Run("Suggests Duplicates", func(t *testing.T) {
+		db.Create(&models.Ingredient{Name: "chickpeas"})
+
+		got, err := service.SuggestDuplicates(dto.DuplicateQuery{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("expected one suggestion, got %+v", got)
+		}
+		if got[0].Canonical.ID != chickpea.ID || got[0].Canonical.Uses != 1 || got[0].Score != 1 || got[0].Reason != "same name" {
+			t.Errorf("unexpected suggestion %+v", got[0])
+		}
+		db.Where("name = ?", "chickpeas").Delete(&models.Ingredient{})
+	})
+
+	t.Run("Rejects Alias Already In Use", func(t *testing.T) {
+		if _, err := service.AddAlias(chickpea.ID, dto.AliasRequest{Name: "Garbanzo bean"}); err != ErrAliasTaken {
+			t.Fatalf("expected ErrAliasTaken, got %v", err)
+		}
+		alias, err := service.AddAlias(chickpea.ID, dto.AliasRequest{Name: " Ceci "})
+		if err != nil || alias.Name != "ceci" {
+			t.Fatalf("AddAlias = %+v, %v", alias, err)
+		}
+	})
+
+	t.Run("Merges Into Canonical", func(t *testing.T) {
+		list := models.ShoppingList{UserID: 1}
+		db.Create(&list)
+		item := models.ShoppingListItem{ShoppingListID: list.ID, IngredientID: &garbanzo.ID, Quantity: 1, Unit: "can", Version: 1}
+		db.Create(&item)
+		db.Create(&models.IngredientPrice{UserID: 1, IngredientID: garbanzo.ID, Price: 1, Quantity: 1, Date: time.Now()})
+
+		resp, err := service.MergeIngredients(dto.MergeIngredientsRequest{CanonicalID: chickpea.ID, DuplicateIDs: []uint{garbanzo.ID}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.RecipeIngredients != 1 || resp.ShoppingListItems != 1 || resp.Prices != 1 || resp.PantryItems != 0 {
+			t.Errorf("unexpected counts %+v", resp)
+		}
+		ing := resp.Ingredient
+		if ing.Density == nil || *ing.Density != density || len(ing.Allergens) != 2 {
+			t.Errorf("gaps not filled: %+v", ing)
+		}
+		if len(ing.Aliases) != 2 || ing.Aliases[0].Name != "ceci" || ing.Aliases[1].Name != "garbanzo beans" {
+			t.Errorf("unexpected aliases %+v", ing.Aliases)
+		}
+
+		var count int64
+		db.Model(&models.Ingredient{}).Where("id = ?", garbanzo.ID).Count(&count)
+		if count != 0 {
+			t.Error("duplicate was not deleted")
+		}
+
+		// the repointed item is logged for clients syncing the list
+		db.First(&item, item.ID)
+		var changes []models.ShoppingListChange
+		db.Where("shopping_list_id = ?", list.ID).Find(&changes)
+		if *item.IngredientID != chickpea.ID || item.Version != 2 ||
+			len(changes) != 1 || changes[0].ItemID != item.ID || changes[0].Version != 2 {
+			t.Errorf("unexpected item %+v and changes %+v", item, changes)
+		}
+
+		found, err := ingredientRepo.FindByName("Garbanzo Bean")
+		if err != nil || found.ID != chickpea.ID {
+			t.Errorf("merged name should resolve to canonical, got %+v, %v", found, err)
+		}
+	})
+
+	t.Run("Rejects Merging Into Itself", func(t *testing.T) {
+		_, err := service.MergeIngredients(dto.MergeIngredientsRequest{CanonicalID: chickpea.ID, DuplicateIDs: []uint{chickpea.ID}})
+		if err != ErrInvalidMerge {
+			t.Fatalf("expected ErrInvalidMerge, got %v", err)
+		}
+	})
+}
+
+func TestSearchIngredients(t *testing.T) {
+	db := setupTestDB()
+	service := NewIngredientService(repository.NewIngredientRepository(db), repository.NewRecipeIngredientRepository(db), repository.NewRecipeRepository(db))
+
+	names := []string{"Tomato", "Cherry Tomatoes", "Tomato Paste", "Potato", "Chickpea"}
+	ids := map[string]uint{}
+	for _, name := range names {
+		ing := models.Ingredient{Name: name}
+		db.Create(&ing)
+		ids[name] = ing.ID
+	}
+	db.Create(&models.IngredientAlias{Name: "garbanzo beans", IngredientID: ids["Chickpea"]})
+	for i := 0; i < 3; i++ {
+		recipe := models.Recipe{UserID: 1, Name: "Sauce", Servings: 2, Category: "Dinner"}
+		db.Create(&recipe)
+		db.Create(&models.RecipeIngredient{RecipeID: recipe.ID, IngredientID: ids["Tomato Paste"], Quantity: 1, Unit: "tbsp"})
+	}
+
+	search := func(userID uint, q string) []dto.IngredientSearchResult {
+		t.Helper()
+		got, err := service.SearchIngredients(userID, dto.IngredientSearchQuery{Q: q})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+	resultNames := func(results []dto.IngredientSearchResult) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Name)
+		}
+		return out
+	}
+
+	t.Run("Ranks Prefix Before Word And Skips Non Matches", func(t *testing.T) {
+		got := resultNames(search(2, "Tom"))
+		want := []string{"Tomato", "Tomato Paste", "Cherry Tomatoes"}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Boosts The User's Own Ingredients", func(t *testing.T) {
+		got := search(1, "tom")
+		if got[0].Name != "Tomato Paste" || got[0].Uses != 3 {
+			t.Errorf("expected the user's tomato paste first, got %+v", got)
+		}
+		if exact := search(1, "tomatoes"); exact[0].Name != "Tomato" {
+			t.Errorf("usage should not beat an exact name, got %v", resultNames(exact))
+		}
+		// three uses of a prefix match still rank below an unused exact name
+		if exact := search(1, "tomato"); exact[0].Name != "Tomato" || exact[0].Uses != 0 || exact[1].Name != "Tomato Paste" {
+			t.Errorf("usage should not beat an exact name, got %+v", exact)
+		}
+	})
+
+	t.Run("Tolerates Typos", func(t *testing.T) {
+		got := search(2, "tomtao")
+		if len(got) == 0 || got[0].Name != "Tomato" {
+			t.Errorf("expected Tomato first, got %v", resultNames(got))
+		}
+	})
+
+	t.Run("Matches Aliases", func(t *testing.T) {
+		got := search(2, "garbanzo")
+		if len(got) != 1 || got[0].Name != "Chickpea" || got[0].Alias != "garbanzo beans" {
+			t.Errorf("unexpected results %+v", got)
+		}
+	})
+
+	t.Run("Finds Exact Names In A Large Catalog", func(t *testing.T) {
+		// short names sharing a common trigram fill more than the candidate window
+		for i := 0; i < searchCandidates+50; i++ {
+			db.Create(&models.Ingredient{Name: fmt.Sprintf("ast%03d", i)})
+		}
+		breast := models.Ingredient{Name: "Chicken Breast"}
+		db.Create(&breast)
+		db.Create(&models.IngredientAlias{Name: "supreme", IngredientID: breast.ID})
+
+		if got := search(2, "chicken breast"); len(got) == 0 || got[0].Name != "Chicken Breast" {
+			t.Errorf("expected Chicken Breast first, got %v", resultNames(got))
+		}
+		if got := search(2, "supreme"); len(got) == 0 || got[0].Name != "Chicken Breast" {
+			t.Errorf("expected the alias to find Chicken Breast, got %v", resultNames(got))
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/storage"
 	"gorm.io/gorm"
 )
 
@@ -21,12 +22,17 @@ type InstructionService interface {
 type instructionService struct {
 	InstructionRepo repository.InstructionRepository
 	RecipeRepo      repository.RecipeRepository
+	PhotoRepo       repository.PhotoRepository
+	Storage         storage.Storage
 }
 
-func NewInstructionService(instRepo repository.InstructionRepository, recipeRepo repository.RecipeRepository) InstructionService {
+func NewInstructionService(instRepo repository.InstructionRepository, recipeRepo repository.RecipeRepository,
+	photoRepo repository.PhotoRepository, store storage.Storage) InstructionService {
 	return &instructionService{
 		InstructionRepo: instRepo,
 		RecipeRepo:      recipeRepo,
+		PhotoRepo:       photoRepo,
+		Storage:         store,
 	}
 }
 
@@ -76,10 +82,30 @@ func (s *instructionService) UpdateInstruction(instructionID uint, userID uint,
 		return ErrUnauthorizedInstruction
 	}
 
+	step := ins.StepNumber
 	ins.StepNumber = req.StepNumber
 	ins.Text = req.Text
 
-	return s.InstructionRepo.Update(ins)
+	if err := s.InstructionRepo.Update(ins); err != nil {
+		return err
+	}
+	if step == ins.StepNumber {
+		return nil
+	}
+
+	// the step's photo follows the instruction to its new number
+	photo, err := s.stepPhoto(ins.RecipeID, step)
+	if photo == nil || err != nil {
+		return err
+	}
+	replaced, err := s.PhotoRepo.Move(photo, ins.StepNumber)
+	if err != nil {
+		return err
+	}
+	if replaced != nil {
+		removePhotoFiles(s.Storage, []models.Photo{*replaced})
+	}
+	return nil
 }
 
 func (s *instructionService) DeleteInstruction(instructionID uint, userID uint) error {
@@ -99,5 +125,37 @@ func (s *instructionService) DeleteInstruction(instructionID uint, userID uint)
 		return ErrUnauthorizedInstruction
 	}
 
-	return s.InstructionRepo.Delete(ins.ID)
+	if err := s.InstructionRepo.Delete(ins.ID); err != nil {
+		return err
+	}
+
+	photo, err := s.stepPhoto(ins.RecipeID, ins.StepNumber)
+	if photo == nil || err != nil {
+		return err
+	}
+	if err := s.PhotoRepo.Delete(photo); err != nil {
+		return err
+	}
+	removePhotoFiles(s.Storage, []models.Photo{*photo})
+	return nil
+}
+
+// stepPhoto finds the photo of a step that no instruction holds any more,
+// or nil when the step has no photo or is still in use.
+func (s *instructionService) stepPhoto(recipeID uint, step int) (*models.Photo, error) {
+	instructions, err := s.InstructionRepo.FindByRecipeID(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ins := range instructions {
+		if ins.StepNumber == step {
+			return nil, nil
+		}
+	}
+
+	photo, err := s.PhotoRepo.FindByStep(recipeID, step)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return photo, err
 }
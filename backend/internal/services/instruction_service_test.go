@@ -6,6 +6,7 @@ import (
 
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
 	"gorm.io/gorm"
 )
 
@@ -60,18 +61,49 @@ func (m *MockRecipeRepoForInstruction) FindByID(id uint) (*models.Recipe, error)
 }
 func (m *MockRecipeRepoForInstruction) Create(*models.Recipe) error                { return nil }
 func (m *MockRecipeRepoForInstruction) FindByUserID(uint) ([]models.Recipe, error) { return nil, nil }
+func (m *MockRecipeRepoForInstruction) Search(uint, repository.RecipeFilter) ([]models.Recipe, int64, error) {
+	return nil, 0, nil
+}
 func (m *MockRecipeRepoForInstruction) FindByIDWithDetails(uint) (*models.Recipe, error) {
 	return nil, nil
 }
 func (m *MockRecipeRepoForInstruction) Update(*models.Recipe) error { return nil }
 func (m *MockRecipeRepoForInstruction) Delete(*models.Recipe) error { return nil }
 
+type MockPhotoRepository struct {
+	FindByStepFn func(uint, int) (*models.Photo, error)
+	MoveFn       func(*models.Photo, int) (*models.Photo, error)
+	DeleteFn     func(*models.Photo) error
+}
+
+func (m *MockPhotoRepository) FindByRecipeID(uint) ([]models.Photo, error) { return nil, nil }
+func (m *MockPhotoRepository) FindByStep(recipeID uint, step int) (*models.Photo, error) {
+	if m.FindByStepFn != nil {
+		return m.FindByStepFn(recipeID, step)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *MockPhotoRepository) Save(*models.Photo) error { return nil }
+func (m *MockPhotoRepository) Move(photo *models.Photo, step int) (*models.Photo, error) {
+	if m.MoveFn != nil {
+		return m.MoveFn(photo, step)
+	}
+	return nil, nil
+}
+func (m *MockPhotoRepository) Delete(photo *models.Photo) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(photo)
+	}
+	return nil
+}
+
 func TestAddInstruction_Success(t *testing.T) {
 	service := NewInstructionService(
 		&MockInstructionRepository{CreateFn: func(i *models.Instruction) error { return nil }},
 		&MockRecipeRepoForInstruction{FindByIDFn: func(id uint) (*models.Recipe, error) {
 			return &models.Recipe{ID: id, UserID: 1}, nil
 		}},
+		&MockPhotoRepository{}, nil,
 	)
 	err := service.AddInstruction(1, 1, dto.CreateInstructionRequest{StepNumber: 1, Text: "Test"})
 	if err != nil {
@@ -82,7 +114,7 @@ func TestAddInstruction_Success(t *testing.T) {
 func TestAddInstruction_RecipeNotFound(t *testing.T) {
 	service := NewInstructionService(&MockInstructionRepository{}, &MockRecipeRepoForInstruction{
 		FindByIDFn: func(id uint) (*models.Recipe, error) { return nil, errors.New("db error") },
-	})
+	}, &MockPhotoRepository{}, nil)
 	err := service.AddInstruction(1, 1, dto.CreateInstructionRequest{})
 	if err == nil {
 		t.Fatal("expected error")
@@ -92,7 +124,7 @@ func TestAddInstruction_RecipeNotFound(t *testing.T) {
 func TestAddInstruction_Unauthorized(t *testing.T) {
 	service := NewInstructionService(&MockInstructionRepository{}, &MockRecipeRepoForInstruction{
 		FindByIDFn: func(id uint) (*models.Recipe, error) { return &models.Recipe{UserID: 2}, nil },
-	})
+	}, &MockPhotoRepository{}, nil)
 	err := service.AddInstruction(1, 1, dto.CreateInstructionRequest{})
 	if err != ErrUnauthorizedInstruction && err != ErrUnauthorized {
 		t.Fatal("expected unauthorized")
@@ -107,6 +139,7 @@ func TestGetInstructions_Success(t *testing.T) {
 		&MockRecipeRepoForInstruction{FindByIDFn: func(id uint) (*models.Recipe, error) {
 			return &models.Recipe{UserID: 1}, nil
 		}},
+		&MockPhotoRepository{}, nil,
 	)
 	res, err := service.GetInstructions(1, 1)
 	if err != nil || len(res) != 1 {
@@ -117,7 +150,7 @@ func TestGetInstructions_Success(t *testing.T) {
 func TestGetInstructions_RecipeError(t *testing.T) {
 	service := NewInstructionService(&MockInstructionRepository{}, &MockRecipeRepoForInstruction{
 		FindByIDFn: func(id uint) (*models.Recipe, error) { return nil, errors.New("err") },
-	})
+	}, &MockPhotoRepository{}, nil)
 	_, err := service.GetInstructions(1, 1)
 	if err == nil {
 		t.Fatal("expected error")
@@ -127,7 +160,7 @@ func TestGetInstructions_RecipeError(t *testing.T) {
 func TestGetInstructions_Unauthorized(t *testing.T) {
 	service := NewInstructionService(&MockInstructionRepository{}, &MockRecipeRepoForInstruction{
 		FindByIDFn: func(id uint) (*models.Recipe, error) { return &models.Recipe{UserID: 2}, nil },
-	})
+	}, &MockPhotoRepository{}, nil)
 	_, err := service.GetInstructions(1, 1)
 	if err != ErrUnauthorizedInstruction && err != ErrUnauthorized {
 		t.Fatal("expected unauthorized")
@@ -143,6 +176,7 @@ func TestUpdateInstruction_Success(t *testing.T) {
 		&MockRecipeRepoForInstruction{FindByIDFn: func(id uint) (*models.Recipe, error) {
 			return &models.Recipe{UserID: 1}, nil
 		}},
+		&MockPhotoRepository{}, nil,
 	)
 	err := service.UpdateInstruction(1, 1, dto.UpdateInstructionRequest{Text: "Update"})
 	if err != nil {
@@ -153,7 +187,7 @@ func TestUpdateInstruction_Success(t *testing.T) {
 func TestUpdateInstruction_InstructionNotFound(t *testing.T) {
 	service := NewInstructionService(&MockInstructionRepository{
 		FindByIDFn: func(u uint) (*models.Instruction, error) { return nil, errors.New("not found") },
-	}, &MockRecipeRepoForInstruction{})
+	}, &MockRecipeRepoForInstruction{}, &MockPhotoRepository{}, nil)
 	err := service.UpdateInstruction(1, 1, dto.UpdateInstructionRequest{})
 	if err == nil {
 		t.Fatal("expected error")
@@ -164,6 +198,7 @@ func TestUpdateInstruction_RecipeError(t *testing.T) {
 	service := NewInstructionService(
 		&MockInstructionRepository{FindByIDFn: func(u uint) (*models.Instruction, error) { return &models.Instruction{RecipeID: 1}, nil }},
 		&MockRecipeRepoForInstruction{FindByIDFn: func(id uint) (*models.Recipe, error) { return nil, errors.New("err") }},
+		&MockPhotoRepository{}, nil,
 	)
 	err := service.UpdateInstruction(1, 1, dto.UpdateInstructionRequest{})
 	if err == nil {
@@ -180,6 +215,7 @@ func TestDeleteInstruction_Success(t *testing.T) {
 		&MockRecipeRepoForInstruction{FindByIDFn: func(id uint) (*models.Recipe, error) {
 			return &models.Recipe{UserID: 1}, nil
 		}},
+		&MockPhotoRepository{}, nil,
 	)
 	err := service.DeleteInstruction(1, 1)
 	if err != nil {
@@ -190,7 +226,7 @@ func TestDeleteInstruction_Success(t *testing.T) {
 func TestDeleteInstruction_RecordNotFound(t *testing.T) {
 	service := NewInstructionService(&MockInstructionRepository{
 		FindByIDFn: func(u uint) (*models.Instruction, error) { return nil, gorm.ErrRecordNotFound },
-	}, &MockRecipeRepoForInstruction{})
+	}, &MockRecipeRepoForInstruction{}, &MockPhotoRepository{}, nil)
 	err := service.DeleteInstruction(1, 1)
 	if err != nil {
 		t.Fatal("expected nil for record not found")
@@ -200,7 +236,7 @@ func TestDeleteInstruction_RecordNotFound(t *testing.T) {
 func TestDeleteInstruction_GeneralError(t *testing.T) {
 	service := NewInstructionService(&MockInstructionRepository{
 		FindByIDFn: func(u uint) (*models.Instruction, error) { return nil, errors.New("db error") },
-	}, &MockRecipeRepoForInstruction{})
+	}, &MockRecipeRepoForInstruction{}, &MockPhotoRepository{}, nil)
 	err := service.DeleteInstruction(1, 1)
 	if err == nil || err.Error() != "db error" {
 		t.Fatal("expected error to propagate")
@@ -211,6 +247,7 @@ func TestDeleteInstruction_RecipeError(t *testing.T) {
 	service := NewInstructionService(
 		&MockInstructionRepository{FindByIDFn: func(u uint) (*models.Instruction, error) { return &models.Instruction{RecipeID: 1}, nil }},
 		&MockRecipeRepoForInstruction{FindByIDFn: func(id uint) (*models.Recipe, error) { return nil, errors.New("err") }},
+		&MockPhotoRepository{}, nil,
 	)
 	err := service.DeleteInstruction(1, 1)
 	if err == nil {
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/parser"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+)
+
+var ErrNoIngredientsGiven = errors.New("give at least one ingredient name or id")
+
+const defaultMatchLimit = 20
+
+// substitutes lists what can stand in for an ingredient in most recipes.
+var substitutes = map[string][]string{
+	"butter":            {"margarine", "coconut oil", "vegetable oil", "olive oil"},
+	"milk":              {"oat milk", "soy milk", "almond milk", "coconut milk"},
+	"cream":             {"coconut milk", "milk"},
+	"heavy cream":       {"cream", "coconut milk"},
+	"buttermilk":        {"yogurt", "milk"},
+	"sour cream":        {"yogurt", "greek yogurt", "creme fraiche"},
+	"yogurt":            {"sour cream", "greek yogurt"},
+	"sugar":             {"brown sugar", "honey", "maple syrup"},
+	"brown sugar":       {"sugar"},
+	"powdered sugar":    {"sugar"},
+	"honey":             {"maple syrup", "sugar"},
+	"maple syrup":       {"honey"},
+	"olive oil":         {"vegetable oil"},
+	"vegetable oil":     {"olive oil", "canola oil", "sunflower oil"},
+	"flour":             {"whole wheat flour"},
+	"cornstarch":        {"flour", "arrowroot"},
+	"lemon":             {"lime"},
+	"lime":              {"lemon"},
+	"lemon juice":       {"lime juice", "vinegar"},
+	"lime juice":        {"lemon juice"},
+	"onion":             {"shallot", "green onion"},
+	"shallot":           {"onion"},
+	"green onion":       {"onion", "shallot", "chive"},
+	"cilantro":          {"parsley"},
+	"parsley":           {"cilantro"},
+	"basil":             {"oregano"},
+	"soy sauce":         {"tamari"},
+	"vinegar":           {"lemon juice"},
+	"chicken stock":     {"vegetable stock", "chicken broth"},
+	"vegetable stock":   {"chicken stock", "vegetable broth"},
+	"bread crumbs":      {"panko", "rolled oats"},
+	"cheddar cheese":    {"mozzarella"},
+	"mozzarella":        {"cheddar cheese"},
+	"parmesan":          {"pecorino"},
+	"red lentils":       {"lentils"},
+	"lentils":           {"red lentils"},
+	"black beans":       {"kidney beans", "pinto beans"},
+	"baking powder":     {"baking soda"},
+	"vanilla extract":   {"vanilla"},
+	"whole wheat flour": {"flour"},
+}
+
+type MatchService interface {
+	// MatchRecipes ranks the user's recipes by how much of each is at hand.
+	MatchRecipes(userID uint, req dto.MatchRecipesRequest) (*dto.MatchRecipesResponse, error)
+}
+
+type matchService struct {
+	RecipeRepo     repository.RecipeRepository
+	IngredientRepo repository.IngredientRepository
+}
+
+func NewMatchService(recipeRepo repository.RecipeRepository, ingredientRepo repository.IngredientRepository) MatchService {
+	return &matchService{RecipeRepo: recipeRepo, IngredientRepo: ingredientRepo}
+}
+
+func (s *matchService) MatchRecipes(userID uint, req dto.MatchRecipesRequest) (*dto.MatchRecipesResponse, error) {
+	if len(req.Ingredients) == 0 && len(req.IngredientIDs) == 0 {
+		return nil, ErrNoIngredientsGiven
+	}
+
+	page, limit := req.Page, req.Limit
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = defaultMatchLimit
+	}
+
+	all, err := s.IngredientRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.MatchRecipesResponse{
+		Page:       page,
+		Limit:      limit,
+		Unknown:    []string{},
+		UnknownIDs: []uint{},
+		Recipes:    []dto.RecipeMatch{},
+	}
+
+	// at hand, by ingredient ID and by every name given or found
+	have := make(map[uint]bool)
+	var names []string
+	byID := make(map[uint]models.Ingredient, len(all))
+	for _, ing := range all {
+		byID[ing.ID] = ing
+	}
+	for _, id := range req.IngredientIDs {
+		ing, ok := byID[id]
+		if !ok {
+			resp.UnknownIDs = append(resp.UnknownIDs, id)
+			continue
+		}
+		have[ing.ID] = true
+		names = append(names, ing.Name)
+	}
+	for _, name := range req.Ingredients {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		names = append(names, name)
+		found := false
+		for _, ing := range all {
+			if parser.SameName(ing.Name, name) {
+				have[ing.ID] = true
+				found = true
+			}
+		}
+		if !found {
+			resp.Unknown = append(resp.Unknown, strings.TrimSpace(name))
+		}
+	}
+
+	recipes, err := s.RecipeRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range recipes {
+		m, ok := matchRecipe(r, have, names)
+		if !ok || m.Missing > req.MaxMissing {
+			continue
+		}
+		resp.Recipes = append(resp.Recipes, m)
+	}
+
+	sort.SliceStable(resp.Recipes, func(i, j int) bool {
+		a, b := resp.Recipes[i], resp.Recipes[j]
+		switch {
+		case a.Coverage != b.Coverage:
+			return a.Coverage > b.Coverage
+		case a.Missing != b.Missing:
+			return a.Missing < b.Missing
+		case a.Matched != b.Matched:
+			return a.Matched > b.Matched
+		}
+		return strings.ToLower(a.Recipe.Name) < strings.ToLower(b.Recipe.Name)
+	})
+
+	resp.Total = int64(len(resp.Recipes))
+	start := min((page-1)*limit, len(resp.Recipes))
+	end := min(start+limit, len(resp.Recipes))
+	resp.Recipes = resp.Recipes[start:end]
+
+	return resp, nil
+}
+
+// matchRecipe sorts a recipe's ingredients into at hand, substitutable,
+// optional and missing. Recipes with nothing at hand don't match.
+func matchRecipe(r models.Recipe, have map[uint]bool, names []string) (dto.RecipeMatch, bool) {
+	m := dto.RecipeMatch{
+		Recipe: dto.RecipeResponse{
+			ID:          r.ID,
+			Name:        r.Name,
+			Servings:    r.Servings,
+			TotalTime:   r.PrepTime + r.CookTime,
+			Description: r.Description,
+			Category:    r.Category,
+			Tags:        tagNames(r.Tags),
+		},
+		MatchedIngredients:    []string{},
+		MissingIngredients:    []string{},
+		SubstituteIngredients: []dto.IngredientSubstitute{},
+		OptionalIngredients:   []string{},
+	}
+
+	seen := make(map[uint]bool)
+	required := 0
+	for _, ri := range r.Ingredients {
+		if seen[ri.IngredientID] {
+			continue
+		}
+		seen[ri.IngredientID] = true
+		name := ri.Ingredient.Name
+		optional := strings.Contains(strings.ToLower(ri.Note), "optional")
+		if !optional {
+			required++
+		}
+
+		if have[ri.IngredientID] {
+			m.Matched++
+			m.MatchedIngredients = append(m.MatchedIngredients, name)
+			continue
+		}
+		if optional {
+			m.Optional++
+			m.OptionalIngredients = append(m.OptionalIngredients, name)
+			continue
+		}
+		if use, ok := substituteFor(name, names); ok {
+			m.Substitutes++
+			m.SubstituteIngredients = append(m.SubstituteIngredients, dto.IngredientSubstitute{Ingredient: name, Use: use})
+			continue
+		}
+		m.Missing++
+		m.MissingIngredients = append(m.MissingIngredients, name)
+	}
+
+	if m.Matched == 0 {
+		return m, false
+	}
+
+	m.Coverage = 1
+	if required > 0 {
+		covered := required - m.Missing
+		m.Coverage = math.Round(float64(covered)/float64(required)*1000) / 1000
+	}
+	return m, true
+}
+
+// substituteFor finds something at hand that can replace name.
+func substituteFor(name string, have []string) (string, bool) {
+	for key, subs := range substitutes {
+		if !parser.SameName(key, name) {
+			continue
+		}
+		for _, sub := range subs {
+			for _, h := range have {
+				if parser.SameName(sub, h) {
+					return strings.TrimSpace(h), true
+				}
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+)
+
+func TestMatchRecipes(t *testing.T) {
+	master := []models.Ingredient{
+		{ID: 1, Name: "Tomato"}, {ID: 2, Name: "Pasta"}, {ID: 3, Name: "Basil"}, {ID: 4, Name: "Butter"},
+		{ID: 5, Name: "Egg"}, {ID: 6, Name: "Flour"}, {ID: 7, Name: "Milk"}, {ID: 8, Name: "Parmesan"}, {ID: 9, Name: "Sugar"},
+	}
+	uses := func(ids ...uint) []models.RecipeIngredient {
+		var ris []models.RecipeIngredient
+		for _, id := range ids {
+			ris = append(ris, models.RecipeIngredient{IngredientID: id, Ingredient: master[id-1]})
+		}
+		return ris
+	}
+	pomodoro := uses(1, 2, 3, 8)
+	pomodoro[2].Note = "optional"
+	recipes := []models.Recipe{
+		{ID: 1, Name: "Pasta al Pomodoro", Ingredients: pomodoro},
+		{ID: 2, Name: "Pancakes", Ingredients: uses(6, 5, 7, 4)},
+		{ID: 3, Name: "Omelette", Ingredients: uses(5, 4, 7)},
+		{ID: 4, Name: "Cake", Ingredients: uses(6, 9, 5, 4, 7)},
+	}
+
+	service := NewMatchService(
+		&MockRecipeRepository{FindByUserIDFn: func(uint) ([]models.Recipe, error) { return recipes, nil }},
+		&MockIngredientRepository{FindAllFn: func() ([]models.Ingredient, error) { return master, nil }},
+	)
+	req := dto.MatchRecipesRequest{Ingredients: []string{"Tomatoes", "pasta", "eggs", "margarine", "Milk"}, IngredientIDs: []uint{6, 99}}
+
+	resp, err := service.MatchRecipes(1, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Unknown) != 1 || resp.Unknown[0] != "margarine" || len(resp.UnknownIDs) != 1 {
+		t.Errorf("unexpected unknown %v / %v", resp.Unknown, resp.UnknownIDs)
+	}
+	if resp.Total != 2 || resp.Recipes[0].Recipe.Name != "Pancakes" || resp.Recipes[1].Recipe.Name != "Omelette" {
+		t.Fatalf("unexpected matches %+v", resp.Recipes)
+	}
+	pancakes := resp.Recipes[0]
+	if pancakes.Coverage != 1 || pancakes.Matched != 3 || pancakes.Substitutes != 1 ||
+		pancakes.SubstituteIngredients[0] != (dto.IngredientSubstitute{Ingredient: "Butter", Use: "margarine"}) {
+		t.Errorf("unexpected pancakes %+v", pancakes)
+	}
+
+	req.MaxMissing, req.Page, req.Limit = 1, 2, 2
+	resp, err = service.MatchRecipes(1, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 4 || len(resp.Recipes) != 2 || resp.Recipes[0].Recipe.Name != "Cake" || resp.Recipes[0].Coverage != 0.8 {
+		t.Fatalf("unexpected second page %+v", resp.Recipes)
+	}
+	pasta := resp.Recipes[1]
+	if pasta.Coverage != 0.667 || pasta.Optional != 1 || pasta.Missing != 1 || pasta.MissingIngredients[0] != "Parmesan" {
+		t.Errorf("unexpected pasta %+v", pasta)
+	}
+
+	if _, err := service.MatchRecipes(1, dto.MatchRecipesRequest{}); err != ErrNoIngredientsGiven {
+		t.Errorf("expected ErrNoIngredientsGiven, got %v", err)
+	}
+}
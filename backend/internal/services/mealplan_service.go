@@ -2,8 +2,11 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/diet"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
@@ -12,11 +15,16 @@ import (
 
 var ErrMealExists = errors.New("meal already planned for this date and meal type")
 var ErrMealUnauthorized = errors.New("not authorized")
+var ErrMealExcluded = errors.New("recipe breaks your dietary exclusions")
 
 type MealPlanService interface {
-	Create(userID uint, req dto.CreateMealPlanRequest) error
+	// Create plans a meal, returning warnings when the recipe breaks the
+	// user's exclusions, or ErrMealExcluded when they ask for a refusal.
+	Create(userID uint, req dto.CreateMealPlanRequest) ([]string, error)
 	GetByDate(userID uint, date string) ([]dto.MealPlanResponse, error)
-	Update(id uint, userID uint, req dto.UpdateMealPlanRequest) error
+	// Update changes a planned meal. A new recipe is checked against the
+	// user's exclusions as in Create.
+	Update(id uint, userID uint, req dto.UpdateMealPlanRequest) ([]string, error)
 	Delete(id uint, userID uint) error
 	GetByDateRange(userID uint, startDateStr, endDateStr string) ([]dto.MealPlanResponse, error)
 }
@@ -24,32 +32,42 @@ type MealPlanService interface {
 type mealPlanService struct {
 	Repo       repository.MealPlanRepository
 	RecipeRepo repository.RecipeRepository
+	UserRepo   repository.UserRepository
 }
 
-func NewMealPlanService(repo repository.MealPlanRepository, recipeRepo repository.RecipeRepository) MealPlanService {
-	return &mealPlanService{Repo: repo, RecipeRepo: recipeRepo}
+func NewMealPlanService(
+	repo repository.MealPlanRepository,
+	recipeRepo repository.RecipeRepository,
+	userRepo repository.UserRepository,
+) MealPlanService {
+	return &mealPlanService{Repo: repo, RecipeRepo: recipeRepo, UserRepo: userRepo}
 }
 
-func (s *mealPlanService) Create(userID uint, req dto.CreateMealPlanRequest) error {
+func (s *mealPlanService) Create(userID uint, req dto.CreateMealPlanRequest) ([]string, error) {
 	date, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	recipe, err := s.RecipeRepo.FindByID(req.RecipeID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if recipe.UserID != userID {
-		return ErrMealUnauthorized
+		return nil, ErrMealUnauthorized
 	}
 
 	err = s.Repo.FindDuplicate(userID, date, req.MealType)
 	if err == nil {
-		return ErrMealExists
+		return nil, ErrMealExists
 	}
 	if err != gorm.ErrRecordNotFound {
-		return err
+		return nil, err
+	}
+
+	warnings, err := s.exclusionViolations(userID, req.RecipeID)
+	if err != nil {
+		return nil, err
 	}
 
 	mp := &models.MealPlan{
@@ -60,7 +78,32 @@ func (s *mealPlanService) Create(userID uint, req dto.CreateMealPlanRequest) err
 		TargetServings: req.TargetServings,
 	}
 
-	return s.Repo.Create(mp)
+	return warnings, s.Repo.Create(mp)
+}
+
+// exclusionViolations checks a recipe against the user's exclusions. It
+// returns ErrMealExcluded, listing the violations, when the user asked for
+// those recipes to be refused.
+func (s *mealPlanService) exclusionViolations(userID, recipeID uint) ([]string, error) {
+	user, err := s.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	warnings := []string{}
+	if user.Exclusions == nil {
+		return warnings, nil
+	}
+
+	recipe, err := s.RecipeRepo.FindByIDWithDetails(recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := diet.Violations(recipeLabels(recipe), user.Exclusions.Allergens, user.Exclusions.Diets)
+	if len(violations) > 0 && user.Exclusions.Refuse {
+		return nil, fmt.Errorf("%w: %s", ErrMealExcluded, strings.Join(violations, ", "))
+	}
+	return append(warnings, violations...), nil
 }
 
 func (s *mealPlanService) GetByDateRange(userID uint, startDateStr, endDateStr string) ([]dto.MealPlanResponse, error) {
@@ -125,17 +168,28 @@ func (s *mealPlanService) GetByDate(userID uint, dateStr string) ([]dto.MealPlan
 	return resp, nil
 }
 
-func (s *mealPlanService) Update(id uint, userID uint, req dto.UpdateMealPlanRequest) error {
+func (s *mealPlanService) Update(id uint, userID uint, req dto.UpdateMealPlanRequest) ([]string, error) {
 	mp, err := s.Repo.FindByID(id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if mp.UserID != userID {
-		return ErrMealUnauthorized
-	}
-
-	if req.RecipeID != 0 {
+		return nil, ErrMealUnauthorized
+	}
+
+	warnings := []string{}
+	if req.RecipeID != 0 && req.RecipeID != mp.RecipeID {
+		recipe, err := s.RecipeRepo.FindByID(req.RecipeID)
+		if err != nil {
+			return nil, err
+		}
+		if recipe.UserID != userID {
+			return nil, ErrMealUnauthorized
+		}
+		if warnings, err = s.exclusionViolations(userID, req.RecipeID); err != nil {
+			return nil, err
+		}
 		mp.RecipeID = req.RecipeID
 	}
 	if req.MealType != "" {
@@ -145,7 +199,7 @@ func (s *mealPlanService) Update(id uint, userID uint, req dto.UpdateMealPlanReq
 		mp.TargetServings = req.TargetServings
 	}
 
-	return s.Repo.Update(mp)
+	return warnings, s.Repo.Update(mp)
 }
 
 func (s *mealPlanService) Delete(id uint, userID uint) error {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
 	"gorm.io/gorm"
 )
 
@@ -38,7 +39,8 @@ func (m *MockMealPlanRepo) FindByUserAndDateRange(u uint, s, e time.Time) ([]mod
 }
 
 type MockRecipeRepoForMealPlan struct {
-	FindByIDFn func(uint) (*models.Recipe, error)
+	FindByIDFn            func(uint) (*models.Recipe, error)
+	FindByIDWithDetailsFn func(uint) (*models.Recipe, error)
 }
 
 func (m *MockRecipeRepoForMealPlan) FindByID(id uint) (*models.Recipe, error) {
@@ -46,7 +48,13 @@ func (m *MockRecipeRepoForMealPlan) FindByID(id uint) (*models.Recipe, error) {
 }
 func (m *MockRecipeRepoForMealPlan) Create(*models.Recipe) error                { return nil }
 func (m *MockRecipeRepoForMealPlan) FindByUserID(uint) ([]models.Recipe, error) { return nil, nil }
-func (m *MockRecipeRepoForMealPlan) FindByIDWithDetails(uint) (*models.Recipe, error) {
+func (m *MockRecipeRepoForMealPlan) Search(uint, repository.RecipeFilter) ([]models.Recipe, int64, error) {
+	return nil, 0, nil
+}
+func (m *MockRecipeRepoForMealPlan) FindByIDWithDetails(id uint) (*models.Recipe, error) {
+	if m.FindByIDWithDetailsFn != nil {
+		return m.FindByIDWithDetailsFn(id)
+	}
 	return nil, nil
 }
 func (m *MockRecipeRepoForMealPlan) Update(*models.Recipe) error { return nil }
@@ -65,9 +73,12 @@ func TestCreateMealPlan_Success(t *testing.T) {
 				return &models.Recipe{ID: id, UserID: 1}, nil
 			},
 		},
+		&MockUserRepo{
+			FindByIDFn: func(id uint) (*models.User, error) { return &models.User{ID: id}, nil },
+		},
 	)
 
-	err := service.Create(1, dto.CreateMealPlanRequest{
+	_, err := service.Create(1, dto.CreateMealPlanRequest{
 		RecipeID: 1, Date: "2025-01-01", MealType: "dinner", TargetServings: 4,
 	})
 
@@ -76,11 +87,46 @@ func TestCreateMealPlan_Success(t *testing.T) {
 	}
 }
 
+func TestCreateMealPlan_Exclusions(t *testing.T) {
+	pesto := func(id uint) (*models.Recipe, error) {
+		return &models.Recipe{ID: id, UserID: 1, Ingredients: []models.RecipeIngredient{
+			{Ingredient: models.Ingredient{Name: "Basil", Diets: []string{"vegan"}}},
+			{Ingredient: models.Ingredient{Name: "Pine Nuts", Allergens: []string{"nuts"}, Diets: []string{"vegan"}}},
+			{Ingredient: models.Ingredient{Name: "Parmesan", Allergens: []string{"milk"}, Diets: []string{}}},
+		}}, nil
+	}
+	exclusions := &models.Exclusions{Allergens: []string{"nuts", "peanuts"}, Diets: []string{"vegetarian"}}
+
+	created := false
+	service := NewMealPlanService(
+		&MockMealPlanRepo{CreateFn: func(*models.MealPlan) error { created = true; return nil }},
+		&MockRecipeRepoForMealPlan{FindByIDFn: pesto, FindByIDWithDetailsFn: pesto},
+		&MockUserRepo{FindByIDFn: func(id uint) (*models.User, error) {
+			return &models.User{ID: id, Exclusions: exclusions}, nil
+		}},
+	)
+	req := dto.CreateMealPlanRequest{RecipeID: 3, Date: "2025-01-01", MealType: "dinner", TargetServings: 2}
+
+	warnings, err := service.Create(1, req)
+	if err != nil || !created {
+		t.Fatalf("expected the meal to be planned with warnings, got %v", err)
+	}
+	if len(warnings) != 2 || warnings[0] != "contains nuts" || warnings[1] != "not known to be vegetarian" {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+
+	created = false
+	exclusions.Refuse = true
+	if _, err := service.Create(1, req); !errors.Is(err, ErrMealExcluded) || created {
+		t.Errorf("expected ErrMealExcluded, got %v", err)
+	}
+}
+
 func TestCreateMealPlan_Errors(t *testing.T) {
-	service := NewMealPlanService(&MockMealPlanRepo{}, &MockRecipeRepoForMealPlan{})
+	service := NewMealPlanService(&MockMealPlanRepo{}, &MockRecipeRepoForMealPlan{}, &MockUserRepo{})
 
 	t.Run("Invalid Date Format", func(t *testing.T) {
-		err := service.Create(1, dto.CreateMealPlanRequest{Date: "01-01-2025"})
+		_, err := service.Create(1, dto.CreateMealPlanRequest{Date: "01-01-2025"})
 		if err == nil {
 			t.Fatal("expected error for invalid date format")
 		}
@@ -90,7 +136,7 @@ func TestCreateMealPlan_Errors(t *testing.T) {
 		service.(*mealPlanService).RecipeRepo = &MockRecipeRepoForMealPlan{
 			FindByIDFn: func(id uint) (*models.Recipe, error) { return nil, errors.New("db error") },
 		}
-		err := service.Create(1, dto.CreateMealPlanRequest{Date: "2025-01-01", RecipeID: 1})
+		_, err := service.Create(1, dto.CreateMealPlanRequest{Date: "2025-01-01", RecipeID: 1})
 		if err == nil {
 			t.Fatal("expected error when recipe lookup fails")
 		}
@@ -100,7 +146,7 @@ func TestCreateMealPlan_Errors(t *testing.T) {
 		service.(*mealPlanService).RecipeRepo = &MockRecipeRepoForMealPlan{
 			FindByIDFn: func(id uint) (*models.Recipe, error) { return &models.Recipe{UserID: 2}, nil },
 		}
-		err := service.Create(1, dto.CreateMealPlanRequest{Date: "2025-01-01", RecipeID: 1})
+		_, err := service.Create(1, dto.CreateMealPlanRequest{Date: "2025-01-01", RecipeID: 1})
 		if err != ErrMealUnauthorized {
 			t.Fatal("expected unauthorized error")
 		}
@@ -117,6 +163,7 @@ func TestGetByDateRange_Success(t *testing.T) {
 			},
 		},
 		&MockRecipeRepoForMealPlan{},
+		&MockUserRepo{},
 	)
 
 	res, err := service.GetByDateRange(1, "2025-01-01", "2025-01-07")
@@ -126,7 +173,7 @@ func TestGetByDateRange_Success(t *testing.T) {
 }
 
 func TestGetByDateRange_DateError(t *testing.T) {
-	service := NewMealPlanService(&MockMealPlanRepo{}, &MockRecipeRepoForMealPlan{})
+	service := NewMealPlanService(&MockMealPlanRepo{}, &MockRecipeRepoForMealPlan{}, &MockUserRepo{})
 	_, err := service.GetByDateRange(1, "invalid", "2025-01-07")
 	if err == nil {
 		t.Fatal("expected error for invalid start date")
@@ -138,7 +185,7 @@ func TestGetByDateRange_DateError(t *testing.T) {
 }
 
 func TestGetByDate_Errors(t *testing.T) {
-	service := NewMealPlanService(&MockMealPlanRepo{}, &MockRecipeRepoForMealPlan{})
+	service := NewMealPlanService(&MockMealPlanRepo{}, &MockRecipeRepoForMealPlan{}, &MockUserRepo{})
 
 	t.Run("Invalid Date", func(t *testing.T) {
 		_, err := service.GetByDate(1, "invalid")
@@ -164,8 +211,8 @@ func TestUpdateMealPlan_Failures(t *testing.T) {
 	t.Run("Record Not Found", func(t *testing.T) {
 		service := NewMealPlanService(&MockMealPlanRepo{
 			FindByIDFn: func(id uint) (*models.MealPlan, error) { return nil, gorm.ErrRecordNotFound },
-		}, &MockRecipeRepoForMealPlan{})
-		err := service.Update(1, 1, dto.UpdateMealPlanRequest{})
+		}, &MockRecipeRepoForMealPlan{}, &MockUserRepo{})
+		_, err := service.Update(1, 1, dto.UpdateMealPlanRequest{})
 		if err != gorm.ErrRecordNotFound {
 			t.Fatal("expected record not found")
 		}
@@ -174,18 +221,68 @@ func TestUpdateMealPlan_Failures(t *testing.T) {
 	t.Run("Unauthorized", func(t *testing.T) {
 		service := NewMealPlanService(&MockMealPlanRepo{
 			FindByIDFn: func(id uint) (*models.MealPlan, error) { return &models.MealPlan{UserID: 2}, nil },
-		}, &MockRecipeRepoForMealPlan{})
-		err := service.Update(1, 1, dto.UpdateMealPlanRequest{})
+		}, &MockRecipeRepoForMealPlan{}, &MockUserRepo{})
+		_, err := service.Update(1, 1, dto.UpdateMealPlanRequest{})
 		if err != ErrMealUnauthorized {
 			t.Fatal("expected unauthorized")
 		}
 	})
 }
 
+func TestUpdateMealPlan_NewRecipe(t *testing.T) {
+	recipes := map[uint]*models.Recipe{
+		1: {ID: 1, UserID: 1, Ingredients: []models.RecipeIngredient{
+			{Ingredient: models.Ingredient{Name: "Rice", Diets: []string{"vegan", "vegetarian"}}},
+		}},
+		2: {ID: 2, UserID: 1, Ingredients: []models.RecipeIngredient{
+			{Ingredient: models.Ingredient{Name: "Pine Nuts", Allergens: []string{"nuts"}, Diets: []string{"vegan", "vegetarian"}}},
+		}},
+		3: {ID: 3, UserID: 2},
+	}
+	find := func(id uint) (*models.Recipe, error) { return recipes[id], nil }
+	exclusions := &models.Exclusions{Allergens: []string{"nuts"}}
+
+	plan := &models.MealPlan{ID: 1, UserID: 1, RecipeID: 1, MealType: "dinner", TargetServings: 2}
+	updated := false
+	service := NewMealPlanService(
+		&MockMealPlanRepo{
+			FindByIDFn: func(uint) (*models.MealPlan, error) { p := *plan; return &p, nil },
+			UpdateFn:   func(*models.MealPlan) error { updated = true; return nil },
+		},
+		&MockRecipeRepoForMealPlan{FindByIDFn: find, FindByIDWithDetailsFn: find},
+		&MockUserRepo{FindByIDFn: func(id uint) (*models.User, error) {
+			return &models.User{ID: id, Exclusions: exclusions}, nil
+		}},
+	)
+
+	if _, err := service.Update(1, 1, dto.UpdateMealPlanRequest{RecipeID: 3}); err != ErrMealUnauthorized || updated {
+		t.Errorf("expected another user's recipe to be refused, got %v", err)
+	}
+
+	warnings, err := service.Update(1, 1, dto.UpdateMealPlanRequest{RecipeID: 2})
+	if err != nil || !updated {
+		t.Fatalf("expected the meal to be updated with warnings, got %v", err)
+	}
+	if len(warnings) != 1 || warnings[0] != "contains nuts" {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+
+	updated = false
+	exclusions.Refuse = true
+	if _, err := service.Update(1, 1, dto.UpdateMealPlanRequest{RecipeID: 2}); !errors.Is(err, ErrMealExcluded) || updated {
+		t.Errorf("expected ErrMealExcluded, got %v", err)
+	}
+
+	// keeping the planned recipe doesn't check it again
+	if warnings, err := service.Update(1, 1, dto.UpdateMealPlanRequest{RecipeID: 1, TargetServings: 4}); err != nil || len(warnings) != 0 || !updated {
+		t.Errorf("expected a plain update, got %v %v", warnings, err)
+	}
+}
+
 func TestDeleteMealPlan_Unauthorized(t *testing.T) {
 	service := NewMealPlanService(&MockMealPlanRepo{
 		FindByIDFn: func(id uint) (*models.MealPlan, error) { return &models.MealPlan{UserID: 2}, nil },
-	}, &MockRecipeRepoForMealPlan{})
+	}, &MockRecipeRepoForMealPlan{}, &MockUserRepo{})
 
 	err := service.Delete(1, 1)
 	if err != ErrMealUnauthorized {
@@ -196,7 +293,7 @@ func TestDeleteMealPlan_Unauthorized(t *testing.T) {
 func TestDeleteMealPlan_FindError(t *testing.T) {
 	service := NewMealPlanService(&MockMealPlanRepo{
 		FindByIDFn: func(id uint) (*models.MealPlan, error) { return nil, errors.New("find error") },
-	}, &MockRecipeRepoForMealPlan{})
+	}, &MockRecipeRepoForMealPlan{}, &MockUserRepo{})
 
 	err := service.Delete(1, 1)
 	if err == nil {
@@ -0,0 +1,285 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/nutrition"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+)
+
+type NutritionService interface {
+	RecipeNutrition(recipeID uint, userID uint) (*dto.RecipeNutritionResponse, error)
+	MealPlanNutrition(userID uint, startDateStr, endDateStr string) (*dto.MealPlanNutritionResponse, error)
+}
+
+type nutritionService struct {
+	RecipeRepo   repository.RecipeRepository
+	MealPlanRepo repository.MealPlanRepository
+	UserRepo     repository.UserRepository
+}
+
+func NewNutritionService(
+	repo repository.RecipeRepository,
+	mealPlanRepo repository.MealPlanRepository,
+	userRepo repository.UserRepository,
+) NutritionService {
+	return &nutritionService{RecipeRepo: repo, MealPlanRepo: mealPlanRepo, UserRepo: userRepo}
+}
+
+func (s *nutritionService) RecipeNutrition(recipeID uint, userID uint) (*dto.RecipeNutritionResponse, error) {
+	recipe, err := s.RecipeRepo.FindByIDWithDetails(recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if recipe.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return &dto.RecipeNutritionResponse{
+		RecipeID:         recipe.ID,
+		Name:             recipe.Name,
+		NutritionSummary: recipeNutrition(recipe, 1, recipe.Servings),
+	}, nil
+}
+
+// maxNutritionDays bounds the range of MealPlanNutrition, which lists every day.
+const maxNutritionDays = 366
+
+// MealPlanNutrition totals the planned meals for each day from start to end
+// inclusive, by day and by meal type, and compares each day with the user's
+// targets when they have set some.
+func (s *nutritionService) MealPlanNutrition(userID uint, startDateStr, endDateStr string) (*dto.MealPlanNutritionResponse, error) {
+	layout := "2006-01-02"
+	start, err := time.Parse(layout, startDateStr)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(layout, endDateStr)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) || end.Sub(start) >= maxNutritionDays*24*time.Hour {
+		return nil, ErrInvalidDateRange
+	}
+
+	user, err := s.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := s.MealPlanRepo.FindByUserAndDateRange(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type dayTotals struct {
+		total     nutrition.Facts
+		mealTypes map[string]nutrition.Facts
+		meals     []dto.MealNutrition
+	}
+
+	var dates []string
+	days := make(map[string]*dayTotals)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format(layout)
+		dates = append(dates, date)
+		days[date] = &dayTotals{mealTypes: make(map[string]nutrition.Facts)}
+	}
+
+	var total nutrition.Facts
+	mealTypes := make(map[string]nutrition.Facts)
+
+	for _, mp := range plans {
+		day, ok := days[mp.Date.Format(layout)]
+		if !ok {
+			continue
+		}
+
+		meal, facts := mealNutrition(mp)
+		day.meals = append(day.meals, meal)
+		day.total = day.total.Add(facts)
+		day.mealTypes[mp.MealType] = day.mealTypes[mp.MealType].Add(facts)
+		total = total.Add(facts)
+		mealTypes[mp.MealType] = mealTypes[mp.MealType].Add(facts)
+	}
+
+	resp := &dto.MealPlanNutritionResponse{
+		StartDate: startDateStr,
+		EndDate:   endDateStr,
+		Targets:   nutritionDTO(user.Targets),
+		Total:     nutritionFacts(total),
+		MealTypes: mealTypeFacts(mealTypes),
+		Days:      []dto.DayNutrition{},
+	}
+
+	for _, date := range dates {
+		day := days[date]
+		sort.SliceStable(day.meals, func(i, j int) bool {
+			return mealTypeRank(day.meals[i].MealType) < mealTypeRank(day.meals[j].MealType)
+		})
+		if day.meals == nil {
+			day.meals = []dto.MealNutrition{}
+		}
+
+		dn := dto.DayNutrition{
+			Date:      date,
+			Total:     nutritionFacts(day.total),
+			MealTypes: mealTypeFacts(day.mealTypes),
+			Meals:     day.meals,
+		}
+		if user.Targets != nil {
+			dn.Delta = nutritionDelta(day.total, nutrition.Facts(*user.Targets))
+		}
+		resp.Days = append(resp.Days, dn)
+	}
+
+	return resp, nil
+}
+
+// mealNutrition works out one planned meal, scaled from the recipe's servings
+// to the plan's target servings as the shopping list scales ingredients.
+// Per-serving values entered on the recipe win over its ingredients.
+func mealNutrition(mp models.MealPlan) (dto.MealNutrition, nutrition.Facts) {
+	recipe := &mp.Recipe
+
+	baseServings := recipe.Servings
+	if baseServings == 0 {
+		baseServings = 1
+	}
+	ratio := float64(mp.TargetServings) / float64(baseServings)
+
+	meal := dto.MealNutrition{
+		MealPlanID: mp.ID,
+		MealType:   mp.MealType,
+		RecipeID:   recipe.ID,
+		RecipeName: recipe.Name,
+		Servings:   mp.TargetServings,
+	}
+
+	var facts nutrition.Facts
+	if recipe.Nutrition != nil {
+		facts = nutrition.Facts(*recipe.Nutrition).Scale(float64(baseServings) * ratio)
+		meal.Source = "recipe"
+	} else {
+		var resolved []dto.IngredientNutrition
+		facts, resolved, meal.Unresolved = ingredientNutrition(recipe, ratio)
+		meal.Source = "ingredients"
+		if len(resolved) == 0 {
+			meal.Source = "none"
+		}
+	}
+
+	meal.Nutrition = nutritionFacts(facts)
+	return meal, facts
+}
+
+var mealTypeOrder = map[string]int{"breakfast": 1, "lunch": 2, "dinner": 3, "snack": 4}
+
+// mealTypeRank orders the usual meal types through the day, others after.
+func mealTypeRank(mealType string) int {
+	if rank, ok := mealTypeOrder[mealType]; ok {
+		return rank
+	}
+	return len(mealTypeOrder) + 1
+}
+
+func mealTypeFacts(totals map[string]nutrition.Facts) map[string]dto.NutritionFacts {
+	out := make(map[string]dto.NutritionFacts, len(totals))
+	for mealType, facts := range totals {
+		out[mealType] = nutritionFacts(facts)
+	}
+	return out
+}
+
+// nutritionDelta is total minus target for each nutrient that has a target,
+// keyed by the NutritionFacts JSON names.
+func nutritionDelta(total, targets nutrition.Facts) map[string]float64 {
+	delta := make(map[string]float64)
+	for _, n := range []struct {
+		key           string
+		total, target float64
+	}{
+		{"kcal", total.Kcal, targets.Kcal},
+		{"protein_g", total.Protein, targets.Protein},
+		{"fat_g", total.Fat, targets.Fat},
+		{"carbs_g", total.Carbs, targets.Carbs},
+		{"fiber_g", total.Fiber, targets.Fiber},
+		{"sugar_g", total.Sugar, targets.Sugar},
+		{"sodium_mg", total.Sodium, targets.Sodium},
+	} {
+		if n.target > 0 {
+			delta[n.key] = math.Round((n.total-n.target)*10) / 10
+		}
+	}
+	return delta
+}
+
+// recipeNutrition totals a recipe's ingredients, each quantity multiplied by
+// factor, and divides by servings. Ingredients without nutrition data or a
+// way to weigh them are listed as unresolved rather than counted as zero.
+func recipeNutrition(recipe *models.Recipe, factor float64, servings int) dto.NutritionSummary {
+	if servings <= 0 {
+		servings = 1
+	}
+
+	total, ingredients, unresolved := ingredientNutrition(recipe, factor)
+	return dto.NutritionSummary{
+		Servings:    servings,
+		Total:       nutritionFacts(total),
+		PerServing:  nutritionFacts(total.Scale(1 / float64(servings))),
+		Ingredients: ingredients,
+		Unresolved:  unresolved,
+	}
+}
+
+func ingredientNutrition(recipe *models.Recipe, factor float64) (nutrition.Facts, []dto.IngredientNutrition, []dto.UnresolvedIngredient) {
+	var total nutrition.Facts
+	resolved := []dto.IngredientNutrition{}
+	unresolved := []dto.UnresolvedIngredient{}
+
+	for _, ri := range recipe.Ingredients {
+		quantity := ri.Quantity * factor
+		missing := dto.UnresolvedIngredient{Name: ri.Ingredient.Name, Quantity: quantity, Unit: ri.Unit}
+
+		if ri.Ingredient.Nutrition == nil {
+			missing.Reason = "no nutrition data for the ingredient"
+			unresolved = append(unresolved, missing)
+			continue
+		}
+
+		grams, err := nutrition.Grams(quantity, ri.Unit, valueOrZero(ri.Ingredient.Density), valueOrZero(ri.Ingredient.PieceWeight))
+		if err != nil {
+			missing.Reason = err.Error()
+			unresolved = append(unresolved, missing)
+			continue
+		}
+
+		facts := nutrition.Facts(*ri.Ingredient.Nutrition).ForGrams(grams)
+		total = total.Add(facts)
+		resolved = append(resolved, dto.IngredientNutrition{
+			Name:      ri.Ingredient.Name,
+			Quantity:  quantity,
+			Unit:      ri.Unit,
+			Grams:     math.Round(grams*10) / 10,
+			Nutrition: nutritionFacts(facts),
+		})
+	}
+
+	return total, resolved, unresolved
+}
+
+func nutritionFacts(f nutrition.Facts) dto.NutritionFacts {
+	return dto.NutritionFacts(f.Round())
+}
+
+func valueOrZero(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/nutrition"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+// two eggs fried in butter, plus a pinch of something unknown
+func omelette(id uint) (*models.Recipe, error) {
+	return &models.Recipe{
+		ID: id, UserID: 1, Name: "Omelette", Servings: 2,
+		Ingredients: []models.RecipeIngredient{
+			{Quantity: 2, Unit: "", Ingredient: models.Ingredient{Name: "Egg", PieceWeight: ptr(50),
+				Nutrition: &models.Nutrition{Kcal: 143, Protein: 12.6, Fat: 9.5, Carbs: 0.7, Sugar: 0.4, Sodium: 142}}},
+			{Quantity: 1, Unit: "tbsp", Ingredient: models.Ingredient{Name: "Butter", Density: ptr(0.91),
+				Nutrition: &models.Nutrition{Kcal: 717, Protein: 0.9, Fat: 81.1, Carbs: 0.1, Sugar: 0.1, Sodium: 11}}},
+			{Quantity: 1, Unit: "cup", Ingredient: models.Ingredient{Name: "Spinach",
+				Nutrition: &models.Nutrition{Kcal: 23}}},
+			{Quantity: 1, Unit: "pinch", Ingredient: models.Ingredient{Name: "Sumac"}},
+		},
+	}, nil
+}
+
+func TestRecipeNutrition(t *testing.T) {
+	service := NewNutritionService(&MockRecipeRepoForScale{FindByIDWithDetailsFn: omelette}, nil, nil)
+
+	resp, err := service.RecipeNutrition(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 100 g egg + 13.46 g butter
+	if resp.Total.Kcal != 239.5 || resp.PerServing.Kcal != 119.7 || resp.Total.Sodium != 143.5 {
+		t.Errorf("unexpected totals %+v / %+v", resp.Total, resp.PerServing)
+	}
+	if len(resp.Ingredients) != 2 || resp.Ingredients[0].Grams != 100 || resp.Ingredients[1].Grams != 13.5 {
+		t.Errorf("unexpected breakdown %+v", resp.Ingredients)
+	}
+	if len(resp.Unresolved) != 2 || resp.Unresolved[0].Reason != nutrition.ErrNoDensity.Error() ||
+		resp.Unresolved[1].Name != "Sumac" {
+		t.Errorf("unexpected unresolved %+v", resp.Unresolved)
+	}
+
+	if _, err := service.RecipeNutrition(1, 2); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestScaleRecipe_IncludesNutrition(t *testing.T) {
+	service := NewRecipeScaleService(&MockRecipeRepoForScale{FindByIDWithDetailsFn: omelette})
+
+	resp, err := service.ScaleRecipe(1, 1, 4, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Nutrition.Servings != 4 || resp.Nutrition.Total.Kcal != 479 || resp.Nutrition.PerServing.Kcal != 119.7 {
+		t.Errorf("unexpected scaled nutrition %+v", resp.Nutrition)
+	}
+}
+
+func TestMealPlanNutrition(t *testing.T) {
+	breakfast, _ := omelette(1)
+	stew := models.Recipe{ID: 2, UserID: 1, Name: "Stew", Servings: 4,
+		Nutrition: &models.Nutrition{Kcal: 600, Protein: 30, Fat: 20, Carbs: 70}}
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	mealRepo := &MockMealPlanRepo{
+		FindByUserAndDateRangeFn: func(uint, time.Time, time.Time) ([]models.MealPlan, error) {
+			return []models.MealPlan{
+				{ID: 7, Date: day, MealType: "dinner", TargetServings: 2, Recipe: stew},
+				{ID: 8, Date: day, MealType: "breakfast", TargetServings: 4, Recipe: *breakfast},
+			}, nil
+		},
+	}
+	userRepo := &MockUserRepo{
+		FindByIDFn: func(uint) (*models.User, error) {
+			return &models.User{ID: 1, Targets: &models.Nutrition{Kcal: 2000, Protein: 50}}, nil
+		},
+	}
+	service := NewNutritionService(nil, mealRepo, userRepo)
+
+	resp, err := service.MealPlanNutrition(1, "2026-03-02", "2026-03-03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Days) != 2 || resp.Total.Kcal != 1679 || resp.MealTypes["dinner"].Kcal != 1200 {
+		t.Fatalf("unexpected totals %+v", resp)
+	}
+
+	first := resp.Days[0]
+	if len(first.Meals) != 2 || first.Meals[0].MealType != "breakfast" || first.Meals[0].Source != "ingredients" ||
+		first.Meals[1].Source != "recipe" || first.Meals[1].Nutrition.Protein != 60 {
+		t.Errorf("unexpected meals %+v", first.Meals)
+	}
+	if first.Delta["kcal"] != -321 || first.Delta["protein_g"] != 35.4 || len(first.Delta) != 2 {
+		t.Errorf("unexpected delta %+v", first.Delta)
+	}
+	if resp.Days[1].Total.Kcal != 0 || resp.Days[1].Delta["kcal"] != -2000 {
+		t.Errorf("unexpected empty day %+v", resp.Days[1])
+	}
+
+	if _, err := service.MealPlanNutrition(1, "2026-03-03", "2026-03-02"); err != ErrInvalidDateRange {
+		t.Errorf("expected ErrInvalidDateRange, got %v", err)
+	}
+}
+
+func TestImportNutrition(t *testing.T) {
+	var saved []*models.Ingredient
+	repo := &MockIngredientRepository{
+		FindByNameFn: func(name string) (*models.Ingredient, error) {
+			switch strings.ToLower(name) {
+			case "onion":
+				return &models.Ingredient{ID: 1, Name: "Onion"}, nil
+			case "butter":
+				return &models.Ingredient{ID: 2, Name: "Butter", Nutrition: &models.Nutrition{Kcal: 700}}, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		SaveAllFn: func(is []*models.Ingredient) error {
+			saved = is
+			return nil
+		},
+	}
+	service := NewIngredientService(repo, &MockRecipeIngredientRepository{}, &MockRecipeRepository{})
+
+	csv := "name,kcal,protein,fat,carbs\nonion,40,1.1,0.1,9.3\nButter,717,0.9,81.1,0.1\nKale,49,4.3,0.9,8.8\n"
+
+	resp, err := service.ImportNutrition(strings.NewReader(csv), dto.NutritionImportQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Updated != 1 || resp.Created != 0 || len(resp.Kept) != 1 || len(resp.NotFound) != 1 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if len(saved) != 1 || saved[0].ID != 1 || saved[0].Nutrition.Kcal != 40 {
+		t.Fatalf("unexpected saves %+v", saved)
+	}
+
+	resp, err = service.ImportNutrition(strings.NewReader(csv), dto.NutritionImportQuery{Overwrite: true, Create: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Updated != 2 || resp.Created != 1 || len(saved) != 3 || saved[2].Name != "Kale" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	if _, err := service.ImportNutrition(strings.NewReader("name\nx\n"), dto.NutritionImportQuery{}); !errors.Is(err, nutrition.ErrInvalidCSV) {
+		t.Fatalf("expected ErrInvalidCSV, got %v", err)
+	}
+}
+
+func TestImportNutrition_MatchesLikeRecipes(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewIngredientRepository(db)
+	service := NewIngredientService(repo, &MockRecipeIngredientRepository{}, &MockRecipeRepository{})
+
+	tomato := models.Ingredient{Name: "Tomato"}
+	scallion := models.Ingredient{Name: "Scallion"}
+	db.Create(&tomato)
+	db.Create(&scallion)
+	db.Create(&models.IngredientAlias{Name: "spring onion", IngredientID: scallion.ID})
+
+	csv := "name,kcal,protein,fat,carbs\nTomatoes,18,0.9,0.2,3.9\nSpring  Onions,32,1.8,0.2,7.3\n  Red  Lentil ,352,24.6,1.1,63.4\nred lentils,353,24.6,1.1,63.4\n"
+	resp, err := service.ImportNutrition(strings.NewReader(csv), dto.NutritionImportQuery{Create: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Updated != 2 || resp.Created != 1 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	var all []models.Ingredient
+	db.Order("id").Find(&all)
+	if len(all) != 3 || all[2].Name != "Red Lentil" || all[2].Nutrition.Kcal != 353 {
+		t.Fatalf("expected one new ingredient with a clean name, got %+v", all)
+	}
+	if all[0].Nutrition == nil || all[0].Nutrition.Kcal != 18 || all[1].Nutrition == nil || all[1].Nutrition.Kcal != 32 {
+		t.Errorf("expected plurals and aliases to find their ingredient, got %+v", all)
+	}
+}
+
+func TestAccountArchive_CarriesNutrition(t *testing.T) {
+	source := setupTestDB()
+	seedAccount(t, source, 1)
+	source.Model(&models.Ingredient{}).Where("name = ?", "Onion").
+		Select("Nutrition").Updates(&models.Ingredient{Nutrition: &models.Nutrition{Kcal: 40, Protein: 1.1}})
+
+	target := setupTestDB()
+	target.Create(&models.Ingredient{Name: "onion"})
+
+	data := exportAccount(t, NewAccountService(source), 1)
+	if _, err := NewAccountService(target).ImportArchive(1, data, data.Size(), dto.AccountImportQuery{}); err != nil {
+		t.Fatal(err)
+	}
+
+	ingredients, _ := repository.NewIngredientRepository(target).FindAll()
+	for _, ing := range ingredients {
+		if ing.Name == "onion" && (ing.Nutrition == nil || ing.Nutrition.Kcal != 40 || ing.Nutrition.Protein != 1.1) {
+			t.Fatalf("nutrition not filled in: %+v", ing.Nutrition)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+)
+
+var ErrInvalidExpiryDate = errors.New("expires_at must be YYYY-MM-DD")
+
+type PantryService interface {
+	AddItem(userID uint, req dto.CreatePantryItemRequest) (*dto.PantryItemResponse, error)
+	GetItems(userID uint) ([]dto.PantryItemResponse, error)
+	UpdateItem(itemID uint, userID uint, req dto.UpdatePantryItemRequest) (*dto.PantryItemResponse, error)
+	DeleteItem(itemID uint, userID uint) error
+}
+
+type pantryService struct {
+	PantryRepo     repository.PantryRepository
+	IngredientRepo repository.IngredientRepository
+}
+
+func NewPantryService(pantryRepo repository.PantryRepository, ingredientRepo repository.IngredientRepository) PantryService {
+	return &pantryService{PantryRepo: pantryRepo, IngredientRepo: ingredientRepo}
+}
+
+func (s *pantryService) AddItem(userID uint, req dto.CreatePantryItemRequest) (*dto.PantryItemResponse, error) {
+	expiresAt, err := parseExpiry(req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredient, err := s.IngredientRepo.FindByID(req.IngredientID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.PantryItem{
+		UserID:       userID,
+		IngredientID: ingredient.ID,
+		Ingredient:   *ingredient,
+		Quantity:     req.Quantity,
+		Unit:         strings.TrimSpace(req.Unit),
+		ExpiresAt:    expiresAt,
+	}
+	if err := s.PantryRepo.Create(item); err != nil {
+		return nil, err
+	}
+
+	resp := pantryItemResponse(*item)
+	return &resp, nil
+}
+
+func (s *pantryService) GetItems(userID uint) ([]dto.PantryItemResponse, error) {
+	items, err := s.PantryRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.PantryItemResponse{}
+	for _, item := range items {
+		response = append(response, pantryItemResponse(item))
+	}
+	return response, nil
+}
+
+func (s *pantryService) UpdateItem(itemID uint, userID uint, req dto.UpdatePantryItemRequest) (*dto.PantryItemResponse, error) {
+	item, err := s.PantryRepo.FindByID(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	if req.Quantity != nil {
+		item.Quantity = *req.Quantity
+	}
+	if req.Unit != nil {
+		item.Unit = strings.TrimSpace(*req.Unit)
+	}
+	if req.ExpiresAt != nil {
+		if item.ExpiresAt, err = parseExpiry(*req.ExpiresAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.PantryRepo.Update(item); err != nil {
+		return nil, err
+	}
+
+	resp := pantryItemResponse(*item)
+	return &resp, nil
+}
+
+func (s *pantryService) DeleteItem(itemID uint, userID uint) error {
+	item, err := s.PantryRepo.FindByID(itemID)
+	if err != nil {
+		return err
+	}
+	if item.UserID != userID {
+		return ErrUnauthorized
+	}
+
+	return s.PantryRepo.Delete(item)
+}
+
+// parseExpiry reads an optional YYYY-MM-DD date; empty means no expiry.
+func parseExpiry(s string) (*time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+	if err != nil {
+		return nil, ErrInvalidExpiryDate
+	}
+	return &date, nil
+}
+
+// expiredBy reports whether stock is past its expiry date on day.
+func expiredBy(item models.PantryItem, day time.Time) bool {
+	return item.ExpiresAt != nil && item.ExpiresAt.Before(day)
+}
+
+func pantryItemResponse(item models.PantryItem) dto.PantryItemResponse {
+	resp := dto.PantryItemResponse{
+		ID:           item.ID,
+		IngredientID: item.IngredientID,
+		Ingredient:   item.Ingredient.Name,
+		Quantity:     item.Quantity,
+		Unit:         item.Unit,
+		Expired:      expiredBy(item, time.Now().UTC().Truncate(24*time.Hour)),
+	}
+	if item.ExpiresAt != nil {
+		resp.ExpiresAt = item.ExpiresAt.Format("2006-01-02")
+	}
+	return resp
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type MockPantryRepo struct {
+	CreateFn     func(*models.PantryItem) error
+	FindByIDFn   func(uint) (*models.PantryItem, error)
+	FindByUserFn func(uint) ([]models.PantryItem, error)
+	UpdateFn     func(*models.PantryItem) error
+}
+
+func (m *MockPantryRepo) Create(item *models.PantryItem) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(item)
+	}
+	return nil
+}
+func (m *MockPantryRepo) FindByID(id uint) (*models.PantryItem, error) {
+	if m.FindByIDFn != nil {
+		return m.FindByIDFn(id)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *MockPantryRepo) FindByUser(u uint) ([]models.PantryItem, error) {
+	if m.FindByUserFn != nil {
+		return m.FindByUserFn(u)
+	}
+	return []models.PantryItem{}, nil
+}
+func (m *MockPantryRepo) Update(item *models.PantryItem) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(item)
+	}
+	return nil
+}
+func (m *MockPantryRepo) Delete(*models.PantryItem) error { return nil }
+
+func TestPantryItems(t *testing.T) {
+	flour := &models.Ingredient{ID: 10, Name: "Flour"}
+	service := NewPantryService(&MockPantryRepo{}, &MockIngredientRepository{
+		FindByIDFn: func(uint) (*models.Ingredient, error) { return flour, nil },
+	})
+
+	item, err := service.AddItem(1, dto.CreatePantryItemRequest{IngredientID: 10, Quantity: 1, Unit: " kg ", ExpiresAt: "2000-01-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Ingredient != "Flour" || item.Unit != "kg" || item.ExpiresAt != "2000-01-01" || !item.Expired {
+		t.Errorf("unexpected item %+v", item)
+	}
+
+	if _, err := service.AddItem(1, dto.CreatePantryItemRequest{IngredientID: 10, Quantity: 1, ExpiresAt: "soon"}); err != ErrInvalidExpiryDate {
+		t.Errorf("expected ErrInvalidExpiryDate, got %v", err)
+	}
+}
+
+func TestUpdatePantryItem(t *testing.T) {
+	expires := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	stored := &models.PantryItem{ID: 1, UserID: 1, IngredientID: 10, Quantity: 6, ExpiresAt: &expires,
+		Ingredient: models.Ingredient{ID: 10, Name: "Egg"}}
+	service := NewPantryService(&MockPantryRepo{
+		FindByIDFn: func(uint) (*models.PantryItem, error) { return stored, nil },
+	}, nil)
+
+	quantity, clear := 4.0, ""
+	item, err := service.UpdateItem(1, 1, dto.UpdatePantryItemRequest{Quantity: &quantity, ExpiresAt: &clear})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Quantity != 4 || item.ExpiresAt != "" || stored.ExpiresAt != nil {
+		t.Errorf("unexpected item %+v", item)
+	}
+
+	if _, err := service.UpdateItem(1, 2, dto.UpdatePantryItemRequest{}); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if err := service.DeleteItem(1, 2); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
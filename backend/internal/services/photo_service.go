@@ -0,0 +1,175 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/images"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/storage"
+	"gorm.io/gorm"
+)
+
+var ErrStepNotFound = errors.New("recipe has no such step")
+var ErrPhotoNotFound = errors.New("photo not found")
+
+// PhotoService manages a recipe's cover photo (step 0) and step photos.
+type PhotoService interface {
+	SetPhoto(recipeID uint, step int, userID uint, r io.Reader) (*dto.PhotoResponse, error)
+	DeletePhoto(recipeID uint, step int, userID uint) error
+}
+
+type photoService struct {
+	PhotoRepo       repository.PhotoRepository
+	RecipeRepo      repository.RecipeRepository
+	InstructionRepo repository.InstructionRepository
+	Storage         storage.Storage
+}
+
+func NewPhotoService(photoRepo repository.PhotoRepository, recipeRepo repository.RecipeRepository,
+	instRepo repository.InstructionRepository, store storage.Storage) PhotoService {
+	return &photoService{
+		PhotoRepo:       photoRepo,
+		RecipeRepo:      recipeRepo,
+		InstructionRepo: instRepo,
+		Storage:         store,
+	}
+}
+
+func (s *photoService) SetPhoto(recipeID uint, step int, userID uint, r io.Reader) (*dto.PhotoResponse, error) {
+	if err := s.authorize(recipeID, step, userID); err != nil {
+		return nil, err
+	}
+
+	img, err := images.Process(r)
+	if err != nil {
+		return nil, err
+	}
+
+	token := make([]byte, 12)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	photo := &models.Photo{
+		RecipeID:    recipeID,
+		Step:        step,
+		Key:         fmt.Sprintf("recipes/%d/%s%s", recipeID, hex.EncodeToString(token), img.Extension),
+		ContentType: img.ContentType,
+		Width:       img.Width,
+		Height:      img.Height,
+	}
+
+	if err := s.Storage.Put(photo.Key, img.Data, img.ContentType); err != nil {
+		return nil, err
+	}
+	for width, data := range img.Thumbnails {
+		if err := s.Storage.Put(images.ThumbnailKey(photo.Key, width), data, "image/jpeg"); err != nil {
+			removePhotoFiles(s.Storage, []models.Photo{*photo})
+			return nil, err
+		}
+	}
+
+	previous, err := s.PhotoRepo.FindByStep(recipeID, step)
+	hadPrevious := err == nil
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		removePhotoFiles(s.Storage, []models.Photo{*photo})
+		return nil, err
+	}
+
+	if err := s.PhotoRepo.Save(photo); err != nil {
+		removePhotoFiles(s.Storage, []models.Photo{*photo})
+		return nil, err
+	}
+	if hadPrevious {
+		removePhotoFiles(s.Storage, []models.Photo{*previous})
+	}
+
+	return photoResponse(s.Storage, photo), nil
+}
+
+func (s *photoService) DeletePhoto(recipeID uint, step int, userID uint) error {
+	if err := s.authorize(recipeID, step, userID); err != nil {
+		return err
+	}
+
+	photo, err := s.PhotoRepo.FindByStep(recipeID, step)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPhotoNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.PhotoRepo.Delete(photo); err != nil {
+		return err
+	}
+	removePhotoFiles(s.Storage, []models.Photo{*photo})
+	return nil
+}
+
+// authorize checks the recipe belongs to the user and, for step photos,
+// that the step exists.
+func (s *photoService) authorize(recipeID uint, step int, userID uint) error {
+	recipe, err := s.RecipeRepo.FindByID(recipeID)
+	if err != nil {
+		return err
+	}
+	if recipe.UserID != userID {
+		return ErrUnauthorized
+	}
+	if step == 0 {
+		return nil
+	}
+
+	instructions, err := s.InstructionRepo.FindByRecipeID(recipeID)
+	if err != nil {
+		return err
+	}
+	for _, ins := range instructions {
+		if ins.StepNumber == step {
+			return nil
+		}
+	}
+	return ErrStepNotFound
+}
+
+// photoResponse builds the URLs for a photo; nil when there's no photo or no storage.
+func photoResponse(store storage.Storage, photo *models.Photo) *dto.PhotoResponse {
+	if photo == nil || store == nil {
+		return nil
+	}
+
+	thumbnails := map[string]string{}
+	for _, width := range images.ThumbnailWidths {
+		thumbnails[strconv.Itoa(width)] = store.URL(images.ThumbnailKey(photo.Key, width))
+	}
+	return &dto.PhotoResponse{
+		URL:        store.URL(photo.Key),
+		Width:      photo.Width,
+		Height:     photo.Height,
+		Thumbnails: thumbnails,
+	}
+}
+
+// removePhotoFiles deletes stored originals and thumbnails once their rows
+// are gone. Failures only leave orphaned files behind, so they are logged
+// rather than undoing the delete.
+func removePhotoFiles(store storage.Storage, photos []models.Photo) {
+	if store == nil {
+		return
+	}
+	for _, photo := range photos {
+		for _, key := range images.Keys(photo.Key) {
+			if err := store.Delete(key); err != nil {
+				log.Printf("photo cleanup: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/images"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/storage"
+)
+
+func testPhoto(t *testing.T) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 320, 240))); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+// storedFiles lists every file under root, relative and slash separated.
+func storedFiles(t *testing.T, root string) []string {
+	t.Helper()
+	var files []string
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(root, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files
+}
+
+func TestPhotos_Lifecycle(t *testing.T) {
+	db := setupTestDB()
+	root := t.TempDir()
+	store := storage.NewLocal(root, "/uploads")
+
+	recipeRepo := repository.NewRecipeRepository(db)
+	recipes := NewRecipeService(recipeRepo, repository.NewCookLogRepository(db), db, store)
+	photos := NewPhotoService(repository.NewPhotoRepository(db), recipeRepo, repository.NewInstructionRepository(db), store)
+
+	id, err := recipes.CreateRecipe(1, dto.CreateRecipeRequest{
+		Name: "Toast", Servings: 1, Category: "Breakfast",
+		Ingredients:  []dto.RecipeIngredientRequest{{Name: "Bread", Amount: 2, Unit: "slice"}},
+		Instructions: []string{"Slice.", "Toast."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cover, err := photos.SetPhoto(id, 0, 1, testPhoto(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(cover.URL, "/uploads/recipes/") || !strings.HasSuffix(cover.URL, ".png") ||
+		cover.Width != 320 || len(cover.Thumbnails) != len(images.ThumbnailWidths) {
+		t.Fatalf("unexpected cover %+v", cover)
+	}
+	if _, err := photos.SetPhoto(id, 2, 1, testPhoto(t)); err != nil {
+		t.Fatal(err)
+	}
+	perPhoto := 1 + len(images.ThumbnailWidths)
+	if files := storedFiles(t, root); len(files) != 2*perPhoto {
+		t.Fatalf("expected %d files, got %v", 2*perPhoto, files)
+	}
+
+	// replacing the cover removes the old files
+	replaced, err := photos.SetPhoto(id, 0, 1, testPhoto(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := storedFiles(t, root); len(files) != 2*perPhoto {
+		t.Fatalf("old cover left behind: %v", files)
+	}
+
+	detail, _ := recipes.GetRecipeByID(id, 1)
+	if detail.Photo == nil || detail.Photo.URL != replaced.URL {
+		t.Fatalf("detail has cover %+v, want %+v", detail.Photo, replaced)
+	}
+	if detail.Instructions[0].Photo != nil || detail.Instructions[1].Photo == nil {
+		t.Fatalf("step photo on the wrong step: %+v", detail.Instructions)
+	}
+
+	// dropping step 2 drops its photo
+	err = recipes.UpdateRecipe(id, 1, dto.UpdateRecipeRequest{
+		Name: "Toast", Servings: 1, Category: "Breakfast",
+		Ingredients:  []dto.RecipeIngredientRequest{{Name: "Bread", Amount: 2, Unit: "slice"}},
+		Instructions: []string{"Toast."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := storedFiles(t, root); len(files) != perPhoto {
+		t.Fatalf("step photo left behind: %v", files)
+	}
+
+	if err := recipes.DeleteRecipe(id, 1); err != nil {
+		t.Fatal(err)
+	}
+	if files := storedFiles(t, root); len(files) != 0 {
+		t.Fatalf("files left after deleting the recipe: %v", files)
+	}
+}
+
+func TestPhotos_FollowInstructions(t *testing.T) {
+	db := setupTestDB()
+	root := t.TempDir()
+	store := storage.NewLocal(root, "/uploads")
+
+	recipeRepo := repository.NewRecipeRepository(db)
+	instRepo := repository.NewInstructionRepository(db)
+	photoRepo := repository.NewPhotoRepository(db)
+	recipes := NewRecipeService(recipeRepo, repository.NewCookLogRepository(db), db, store)
+	photos := NewPhotoService(photoRepo, recipeRepo, instRepo, store)
+	instructions := NewInstructionService(instRepo, recipeRepo, photoRepo, store)
+
+	id, err := recipes.CreateRecipe(1, dto.CreateRecipeRequest{
+		Name: "Toast", Servings: 1, Category: "Breakfast",
+		Ingredients:  []dto.RecipeIngredientRequest{{Name: "Bread", Amount: 2, Unit: "slice"}},
+		Instructions: []string{"Slice.", "Toast.", "Butter."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	toast, err := photos.SetPhoto(id, 2, 1, testPhoto(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := photos.SetPhoto(id, 3, 1, testPhoto(t)); err != nil {
+		t.Fatal(err)
+	}
+	steps, _ := instRepo.FindByRecipeID(id)
+
+	// renumbering a step takes its photo along
+	if err := instructions.UpdateInstruction(steps[1].ID, 1, dto.UpdateInstructionRequest{StepNumber: 4, Text: "Toast."}); err != nil {
+		t.Fatal(err)
+	}
+	detail, _ := recipes.GetRecipeByID(id, 1)
+	photoAt := map[int]string{}
+	for _, ins := range detail.Instructions {
+		if ins.Photo != nil {
+			photoAt[ins.StepNumber] = ins.Photo.URL
+		}
+	}
+	if len(photoAt) != 2 || photoAt[4] != toast.URL || photoAt[3] == "" {
+		t.Errorf("unexpected step photos %v", photoAt)
+	}
+
+	// deleting a step deletes its photo and files
+	if err := instructions.DeleteInstruction(steps[2].ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := photoRepo.FindByStep(id, 3); err == nil {
+		t.Error("expected the photo of the deleted step to be gone")
+	}
+	if files := storedFiles(t, root); len(files) != 1+len(images.ThumbnailWidths) {
+		t.Errorf("expected only the moved photo's files, got %v", files)
+	}
+}
+
+func TestPhotos_Errors(t *testing.T) {
+	db := setupTestDB()
+	store := storage.NewLocal(t.TempDir(), "/uploads")
+
+	recipeRepo := repository.NewRecipeRepository(db)
+	recipes := NewRecipeService(recipeRepo, repository.NewCookLogRepository(db), db, store)
+	photos := NewPhotoService(repository.NewPhotoRepository(db), recipeRepo, repository.NewInstructionRepository(db), store)
+
+	id, err := recipes.CreateRecipe(1, dto.CreateRecipeRequest{
+		Name: "Toast", Servings: 1, Category: "Breakfast",
+		Ingredients:  []dto.RecipeIngredientRequest{{Name: "Bread", Amount: 2, Unit: "slice"}},
+		Instructions: []string{"Toast."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := photos.SetPhoto(id, 0, 2, testPhoto(t)); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if _, err := photos.SetPhoto(id, 5, 1, testPhoto(t)); err != ErrStepNotFound {
+		t.Errorf("expected ErrStepNotFound, got %v", err)
+	}
+	if _, err := photos.SetPhoto(id, 0, 1, strings.NewReader("not an image")); err != images.ErrUnsupportedType {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+	if err := photos.DeletePhoto(id, 0, 1); err != ErrPhotoNotFound {
+		t.Errorf("expected ErrPhotoNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/pricing"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+)
+
+var ErrInvalidPriceDate = errors.New("date must be YYYY-MM-DD")
+
+type PriceService interface {
+	AddPrice(userID uint, req dto.CreatePriceRequest) (*dto.PriceResponse, error)
+	GetPrices(userID uint, query dto.PriceListQuery) ([]dto.PriceResponse, error)
+	DeletePrice(priceID uint, userID uint) error
+}
+
+type priceService struct {
+	PriceRepo      repository.PriceRepository
+	IngredientRepo repository.IngredientRepository
+}
+
+func NewPriceService(priceRepo repository.PriceRepository, ingredientRepo repository.IngredientRepository) PriceService {
+	return &priceService{PriceRepo: priceRepo, IngredientRepo: ingredientRepo}
+}
+
+func (s *priceService) AddPrice(userID uint, req dto.CreatePriceRequest) (*dto.PriceResponse, error) {
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.Date != "" {
+		parsed, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return nil, ErrInvalidPriceDate
+		}
+		date = parsed
+	}
+
+	ingredient, err := s.IngredientRepo.FindByID(req.IngredientID)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	price := &models.IngredientPrice{
+		UserID:       userID,
+		IngredientID: ingredient.ID,
+		Ingredient:   *ingredient,
+		Price:        req.Price,
+		Quantity:     quantity,
+		Unit:         strings.TrimSpace(req.Unit),
+		Store:        strings.TrimSpace(req.Store),
+		Date:         date,
+	}
+	if err := s.PriceRepo.Create(price); err != nil {
+		return nil, err
+	}
+
+	resp := priceResponse(*price)
+	return &resp, nil
+}
+
+func (s *priceService) GetPrices(userID uint, query dto.PriceListQuery) ([]dto.PriceResponse, error) {
+	prices, err := s.PriceRepo.FindByUser(userID, query.IngredientID, query.Store)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.PriceResponse{}
+	for _, p := range prices {
+		response = append(response, priceResponse(p))
+	}
+	return response, nil
+}
+
+func (s *priceService) DeletePrice(priceID uint, userID uint) error {
+	price, err := s.PriceRepo.FindByID(priceID)
+	if err != nil {
+		return err
+	}
+	if price.UserID != userID {
+		return ErrUnauthorized
+	}
+
+	return s.PriceRepo.Delete(price)
+}
+
+func priceResponse(p models.IngredientPrice) dto.PriceResponse {
+	return dto.PriceResponse{
+		ID:           p.ID,
+		IngredientID: p.IngredientID,
+		Ingredient:   p.Ingredient.Name,
+		Price:        p.Price,
+		Quantity:     p.Quantity,
+		Unit:         p.Unit,
+		Store:        p.Store,
+		Date:         p.Date.Format("2006-01-02"),
+	}
+}
+
+var errNoPrice = errors.New("no price recorded for the ingredient")
+
+// priceIngredient costs an amount of an ingredient with the latest of the
+// given prices.
+func priceIngredient(prices map[uint]models.IngredientPrice, ing models.Ingredient, quantity float64, unit string) (dto.CostItem, error) {
+	item := dto.CostItem{Name: ing.Name, Quantity: quantity, Unit: unit}
+
+	p, ok := prices[ing.ID]
+	if !ok {
+		return item, errNoPrice
+	}
+
+	cost, err := pricing.Cost(quantity, unit, pricing.Price{Amount: p.Price, Quantity: p.Quantity, Unit: p.Unit},
+		valueOrZero(ing.Density), valueOrZero(ing.PieceWeight))
+	if err != nil {
+		return item, err
+	}
+
+	item.Cost = pricing.Round(cost)
+	item.PriceID = p.ID
+	item.Store = p.Store
+	return item, nil
+}
+
+func unpricedItem(item dto.CostItem, err error) dto.UnpricedItem {
+	return dto.UnpricedItem{Name: item.Name, Quantity: item.Quantity, Unit: item.Unit, Reason: err.Error()}
+}
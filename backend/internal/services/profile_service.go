@@ -0,0 +1,82 @@
+package services
+
+import (
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/diet"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+)
+
+type ProfileService interface {
+	GetProfile(userID uint) (*dto.ProfileResponse, error)
+	SetTargets(userID uint, targets dto.NutritionFacts) (*dto.ProfileResponse, error)
+	SetExclusions(userID uint, exclusions dto.Exclusions) (*dto.ProfileResponse, error)
+}
+
+type profileService struct {
+	UserRepo repository.UserRepository
+}
+
+func NewProfileService(repo repository.UserRepository) ProfileService {
+	return &profileService{UserRepo: repo}
+}
+
+func (s *profileService) GetProfile(userID uint) (*dto.ProfileResponse, error) {
+	user, err := s.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ProfileResponse{
+		ID:         user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		Targets:    nutritionDTO(user.Targets),
+		Exclusions: exclusionsDTO(user.Exclusions),
+	}, nil
+}
+
+// SetTargets stores the user's daily targets. All zeros clears them.
+func (s *profileService) SetTargets(userID uint, targets dto.NutritionFacts) (*dto.ProfileResponse, error) {
+	var stored *models.Nutrition
+	if targets != (dto.NutritionFacts{}) {
+		stored = nutritionModel(&targets)
+	}
+
+	if err := s.UserRepo.UpdateTargets(userID, stored); err != nil {
+		return nil, err
+	}
+
+	return s.GetProfile(userID)
+}
+
+// SetExclusions normalises and stores the allergens and diets the user
+// declares. Empty lists clear them.
+func (s *profileService) SetExclusions(userID uint, exclusions dto.Exclusions) (*dto.ProfileResponse, error) {
+	allergens, err := diet.NormalizeAllergens(exclusions.Allergens)
+	if err != nil {
+		return nil, err
+	}
+	diets, err := diet.NormalizeDiets(exclusions.Diets)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored *models.Exclusions
+	if len(allergens) > 0 || len(diets) > 0 {
+		stored = &models.Exclusions{Allergens: allergens, Diets: diets, Refuse: exclusions.Refuse}
+	}
+
+	if err := s.UserRepo.UpdateExclusions(userID, stored); err != nil {
+		return nil, err
+	}
+
+	return s.GetProfile(userID)
+}
+
+func exclusionsDTO(e *models.Exclusions) dto.Exclusions {
+	if e == nil {
+		return dto.Exclusions{Allergens: []string{}, Diets: []string{}}
+	}
+	return dto.Exclusions{Allergens: e.Allergens, Diets: e.Diets, Refuse: e.Refuse}
+}
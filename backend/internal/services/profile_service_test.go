@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+)
+
+func TestSetTargets(t *testing.T) {
+	user := &models.User{ID: 1, Name: "Test", Email: "test@ex.com"}
+	repo := &MockUserRepo{
+		FindByIDFn: func(uint) (*models.User, error) { return user, nil },
+		UpdateTargetsFn: func(_ uint, targets *models.Nutrition) error {
+			user.Targets = targets
+			return nil
+		},
+	}
+	service := NewProfileService(repo)
+
+	profile, err := service.SetTargets(1, dto.NutritionFacts{Kcal: 2200, Protein: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Targets == nil || profile.Targets.Kcal != 2200 || profile.Targets.Protein != 90 {
+		t.Fatalf("unexpected targets %+v", profile.Targets)
+	}
+
+	profile, err = service.SetTargets(1, dto.NutritionFacts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Targets != nil || user.Targets != nil {
+		t.Fatalf("expected targets to be cleared, got %+v", profile.Targets)
+	}
+}
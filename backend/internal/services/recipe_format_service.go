@@ -0,0 +1,180 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/formats"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/parser"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+)
+
+var ErrImportNoIngredients = errors.New("imported recipe has no usable ingredients")
+var ErrFormatNotSupported = errors.New("format does not support this direction")
+var ErrInvalidDocument = errors.New("could not read document")
+
+type RecipeFormatService interface {
+	ImportRecipe(userID uint, format string, r io.Reader, dryRun bool) (*dto.RecipeImportResponse, error)
+	ExportRecipe(recipeID uint, userID uint, format string) (*dto.RecipeExport, error)
+}
+
+type recipeFormatService struct {
+	Recipes    RecipeService
+	RecipeRepo repository.RecipeRepository
+}
+
+func NewRecipeFormatService(recipes RecipeService, recipeRepo repository.RecipeRepository) RecipeFormatService {
+	return &recipeFormatService{Recipes: recipes, RecipeRepo: recipeRepo}
+}
+
+// ImportRecipe decodes a document and creates the recipe. An empty format
+// means a saved HTML page, which is what the endpoint first accepted.
+func (s *recipeFormatService) ImportRecipe(userID uint, format string, r io.Reader, dryRun bool) (*dto.RecipeImportResponse, error) {
+	if format == "" {
+		format = "html"
+	}
+	f, err := formats.Lookup(format)
+	if err != nil {
+		return nil, err
+	}
+	if f.Decoder == nil {
+		return nil, fmt.Errorf("%w: %s export only", ErrFormatNotSupported, f.Name)
+	}
+
+	recipe, warnings, err := f.Decoder.Decode(r)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.Is(err, formats.ErrNoRecipe) || errors.As(err, &tooLarge) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+
+	return s.create(userID, recipe, warnings, dryRun)
+}
+
+// create maps a decoded recipe onto a create request, parsing each free-text
+// ingredient line on its own so one bad line only costs a warning.
+func (s *recipeFormatService) create(userID uint, recipe *formats.Recipe, warnings []string, dryRun bool) (*dto.RecipeImportResponse, error) {
+	req := dto.CreateRecipeRequest{
+		Name:         recipe.Name,
+		Description:  recipe.Description,
+		Servings:     recipe.Servings,
+		PrepTime:     recipe.PrepTime,
+		CookTime:     recipe.CookTime,
+		Category:     recipe.Category,
+		Instructions: recipe.Instructions,
+		Tags:         recipe.Tags,
+	}
+
+	for _, ing := range recipe.Ingredients {
+		name := strings.TrimSpace(ing.Name)
+		if name == "" {
+			warnings = append(warnings, "skipped an ingredient without a name")
+			continue
+		}
+		req.Ingredients = append(req.Ingredients, dto.RecipeIngredientRequest{
+			Name:   name,
+			Amount: ing.Amount,
+			Unit:   ing.Unit,
+			Note:   ing.Note,
+		})
+	}
+	for _, line := range recipe.IngredientLines {
+		parsed, err := parser.ParseLine(line)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipped ingredient %q: %v", line, err))
+			continue
+		}
+		req.Ingredients = append(req.Ingredients, ingredientRequest(parsed))
+	}
+	if len(req.Ingredients) == 0 {
+		return nil, ErrImportNoIngredients
+	}
+
+	response := &dto.RecipeImportResponse{Recipe: req, Warnings: warnings}
+	if response.Warnings == nil {
+		response.Warnings = []string{}
+	}
+	if dryRun {
+		return response, nil
+	}
+
+	id, err := s.Recipes.CreateRecipe(userID, req)
+	if err != nil {
+		return nil, err
+	}
+	response.ID = id
+
+	return response, nil
+}
+
+func (s *recipeFormatService) ExportRecipe(recipeID uint, userID uint, format string) (*dto.RecipeExport, error) {
+	f, err := formats.Lookup(format)
+	if err != nil {
+		return nil, err
+	}
+	if f.Encoder == nil {
+		return nil, fmt.Errorf("%w: %s import only", ErrFormatNotSupported, f.Name)
+	}
+
+	recipe, err := s.RecipeRepo.FindByIDWithDetails(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	doc := &formats.Recipe{
+		Name:        recipe.Name,
+		Description: recipe.Description,
+		Servings:    recipe.Servings,
+		PrepTime:    recipe.PrepTime,
+		CookTime:    recipe.CookTime,
+		Category:    recipe.Category,
+		Tags:        tagNames(recipe.Tags),
+	}
+	for _, ri := range recipe.Ingredients {
+		doc.Ingredients = append(doc.Ingredients, formats.Ingredient{
+			Name:   ri.Ingredient.Name,
+			Amount: ri.Quantity,
+			Unit:   ri.Unit,
+			Note:   ri.Note,
+		})
+	}
+
+	steps := recipe.Instructions
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].StepNumber < steps[j].StepNumber })
+	for _, step := range steps {
+		doc.Instructions = append(doc.Instructions, step.Text)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Encoder.Encode(&buf, doc); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecipeExport{
+		Filename:    exportFilename(recipe.Name) + "." + f.Extension,
+		ContentType: f.ContentType,
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+func exportFilename(name string) string {
+	slug := strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		return "recipe"
+	}
+	return slug
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/formats"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+)
+
+const importPage = `<script type="application/ld+json">
+{"@type": "Recipe", "name": "Garlic Toast", "recipeYield": "2", "recipeCategory": "Snack",
+ "recipeIngredient": ["2 slices bread", "2-3 cloves garlic, minced", "2 cups"],
+ "recipeInstructions": [{"@type": "HowToStep", "text": "Toast the bread."}]}
+</script>`
+
+func TestImportRecipe_HTMLCreatesRecipe(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewRecipeRepository(db)
+	recipes := NewRecipeService(repo, repository.NewCookLogRepository(db), db, nil)
+	service := NewRecipeFormatService(recipes, repo)
+
+	resp, err := service.ImportRecipe(1, "", strings.NewReader(importPage), false)
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if resp.ID == 0 || len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], `"2 cups"`) {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	detail, err := recipes.GetRecipeByID(resp.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Name != "Garlic Toast" || detail.Category != "Snack" || len(detail.Ingredients) != 2 || len(detail.Instructions) != 1 {
+		t.Fatalf("unexpected stored recipe %+v", detail)
+	}
+	if garlic := detail.Ingredients[1]; garlic.Quantity != 3 || garlic.Unit != "clove" || garlic.Note != "minced" {
+		t.Fatalf("unexpected garlic line %+v", garlic)
+	}
+}
+
+func TestImportRecipe_DryRun(t *testing.T) {
+	service := NewRecipeFormatService(NewRecipeService(&MockRecipeRepository{}, nil, nil, nil), &MockRecipeRepository{})
+
+	resp, err := service.ImportRecipe(1, "", strings.NewReader(importPage), true)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if resp.ID != 0 || resp.Recipe.Name != "Garlic Toast" || len(resp.Recipe.Ingredients) != 2 {
+		t.Fatalf("unexpected preview %+v", resp)
+	}
+}
+
+func TestImportRecipe_Errors(t *testing.T) {
+	service := NewRecipeFormatService(NewRecipeService(&MockRecipeRepository{}, nil, nil, nil), &MockRecipeRepository{})
+
+	page := `<script type="application/ld+json">{"@type": "Recipe", "name": "Air"}</script>`
+	if _, err := service.ImportRecipe(1, "", strings.NewReader(page), true); err != ErrImportNoIngredients {
+		t.Fatalf("expected ErrImportNoIngredients, got %v", err)
+	}
+	if _, err := service.ImportRecipe(1, "pdf", strings.NewReader(""), true); !errors.Is(err, formats.ErrUnknownFormat) {
+		t.Fatalf("expected ErrUnknownFormat, got %v", err)
+	}
+	if _, err := service.ImportRecipe(1, "json", strings.NewReader("{"), true); !errors.Is(err, ErrInvalidDocument) {
+		t.Fatalf("expected ErrInvalidDocument, got %v", err)
+	}
+}
+
+func TestExportRecipe_Unauthorized(t *testing.T) {
+	repo := &MockRecipeRepository{
+		FindByIDWithDetailsFn: func(id uint) (*models.Recipe, error) {
+			return &models.Recipe{ID: id, UserID: 2}, nil
+		},
+	}
+	service := NewRecipeFormatService(NewRecipeService(repo, nil, nil, nil), repo)
+
+	if _, err := service.ExportRecipe(1, 1, "json"); err != ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+	if _, err := service.ExportRecipe(1, 2, "html"); !errors.Is(err, ErrFormatNotSupported) {
+		t.Fatalf("expected html export to be rejected, got %v", err)
+	}
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewRecipeRepository(db)
+	recipes := NewRecipeService(repo, repository.NewCookLogRepository(db), db, nil)
+	service := NewRecipeFormatService(recipes, repo)
+
+	id, err := recipes.CreateRecipe(1, dto.CreateRecipeRequest{
+		Name: "Weeknight Dal", Description: "Quick red lentil dal.\nServe with rice.",
+		Servings: 4, PrepTime: 10, CookTime: 25, Category: "Dinner", Tags: []string{"Vegan", "Indian"},
+		Ingredients: []dto.RecipeIngredientRequest{
+			{Name: "Red lentils", Amount: 1.5, Unit: "cups"},
+			{Name: "Onion", Amount: 1, Unit: "pcs", Note: "diced"},
+			{Name: "Garlic", Amount: 3, Unit: "clove", Note: "minced | crushed"},
+			{Name: "Salt", Amount: 0, Unit: ""},
+			{Name: "Olive oil", Amount: 0.333, Unit: "tbsp"},
+			{Name: "Garlic", Amount: 1, Unit: "clove", Note: "whole (skin on) -- optional"},
+		},
+		Instructions: []string{"Rinse the lentils.", "Fry the onion, garlic and salt -- gently.",
+			"= Simmer for 20 minutes.", "Ask @chef to taste with spoon #2 [- not the ladle -] and season."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, _ := recipes.GetRecipeByID(id, 1)
+
+	for _, format := range []string{"json", "yaml", "markdown", "cooklang"} {
+		export, err := service.ExportRecipe(id, 1, format)
+		if err != nil {
+			t.Fatalf("%s export failed: %v", format, err)
+		}
+		if !strings.HasPrefix(export.Filename, "weeknight-dal.") {
+			t.Errorf("%s: unexpected filename %q", format, export.Filename)
+		}
+
+		resp, err := service.ImportRecipe(1, format, bytes.NewReader(export.Data), false)
+		if err != nil {
+			t.Fatalf("%s import failed: %v\n%s", format, err, export.Data)
+		}
+		if len(resp.Warnings) != 0 {
+			t.Errorf("%s: unexpected warnings %v", format, resp.Warnings)
+		}
+
+		copied, _ := recipes.GetRecipeByID(resp.ID, 1)
+		if format == "cooklang" {
+			// Cooklang steps are single paragraphs
+			original.Description = strings.ReplaceAll(original.Description, "\n", " ")
+		}
+		copied.ID = original.ID
+		if !reflect.DeepEqual(original, copied) {
+			t.Errorf("%s round trip differs\nwant %+v\n got %+v\n%s", format, original, copied, export.Data)
+		}
+	}
+}
@@ -5,12 +5,13 @@ import (
 
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/units"
 )
 
 var ErrInvalidServings = errors.New("invalid servings")
 
 type RecipeScaleService interface {
-	ScaleRecipe(recipeID uint, userID uint, newServings int) (*dto.ScaledRecipeResponse, error)
+	ScaleRecipe(recipeID uint, userID uint, newServings int, system string) (*dto.ScaledRecipeResponse, error)
 }
 
 type recipeScaleService struct {
@@ -25,12 +26,22 @@ func (s *recipeScaleService) ScaleRecipe(
 	recipeID uint,
 	userID uint,
 	newServings int,
+	system string,
 ) (*dto.ScaledRecipeResponse, error) {
 
 	if newServings <= 0 {
 		return nil, ErrInvalidServings
 	}
 
+	var target units.System
+	if system != "" {
+		parsed, err := units.ParseSystem(system)
+		if err != nil {
+			return nil, err
+		}
+		target = parsed
+	}
+
 	recipe, err := s.RecipeRepo.FindByIDWithDetails(recipeID)
 	if err != nil {
 		return nil, err
@@ -45,11 +56,17 @@ func (s *recipeScaleService) ScaleRecipe(
 	var ingredients []dto.ScaledIngredientResponse
 
 	for _, ri := range recipe.Ingredients {
+		quantity := ri.Quantity * scaleFactor
+		unit := ri.Unit
+		if target != "" {
+			quantity, unit = units.ConvertToSystem(quantity, unit, target)
+		}
+
 		ingredients = append(ingredients, dto.ScaledIngredientResponse{
 			ID:       ri.IngredientID,
 			Name:     ri.Ingredient.Name,
-			Quantity: ri.Quantity * scaleFactor,
-			Unit:     ri.Unit,
+			Quantity: quantity,
+			Unit:     unit,
 		})
 	}
 
@@ -59,5 +76,6 @@ func (s *recipeScaleService) ScaleRecipe(
 		OriginalServings: recipe.Servings,
 		ScaledServings:   newServings,
 		Ingredients:      ingredients,
+		Nutrition:        recipeNutrition(recipe, scaleFactor, newServings),
 	}, nil
 }
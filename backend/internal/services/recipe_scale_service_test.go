@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/units"
 	"gorm.io/gorm"
 )
 
@@ -19,6 +21,9 @@ func (m *MockRecipeRepoForScale) Create(*models.Recipe) error {
 	return nil
 }
 
+func (m *MockRecipeRepoForScale) Search(uint, repository.RecipeFilter) ([]models.Recipe, int64, error) {
+	return nil, 0, nil
+}
 func (m *MockRecipeRepoForScale) FindByUserID(uint) ([]models.Recipe, error) {
 	return nil, nil
 }
@@ -59,7 +64,7 @@ func TestScaleRecipe_Success(t *testing.T) {
 		},
 	)
 
-	resp, err := service.ScaleRecipe(1, 1, 4)
+	resp, err := service.ScaleRecipe(1, 1, 4, "")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -86,7 +91,7 @@ func TestScaleRecipe_Unauthorized(t *testing.T) {
 		},
 	)
 
-	_, err := service.ScaleRecipe(1, 1, 4)
+	_, err := service.ScaleRecipe(1, 1, 4, "")
 	if err != ErrUnauthorized {
 		t.Fatalf("expected ErrUnauthorized, got %v", err)
 	}
@@ -95,8 +100,55 @@ func TestScaleRecipe_Unauthorized(t *testing.T) {
 func TestScaleRecipe_InvalidServings(t *testing.T) {
 	service := NewRecipeScaleService(&MockRecipeRepoForScale{})
 
-	_, err := service.ScaleRecipe(1, 1, 0)
+	_, err := service.ScaleRecipe(1, 1, 0, "")
 	if err != ErrInvalidServings {
 		t.Fatalf("expected ErrInvalidServings, got %v", err)
 	}
 }
+
+func TestScaleRecipe_ConvertsSystem(t *testing.T) {
+	service := NewRecipeScaleService(
+		&MockRecipeRepoForScale{
+			FindByIDWithDetailsFn: func(id uint) (*models.Recipe, error) {
+				return &models.Recipe{
+					ID:       id,
+					UserID:   1,
+					Servings: 2,
+					Ingredients: []models.RecipeIngredient{
+						{IngredientID: 1, Quantity: 1, Unit: "cup", Ingredient: models.Ingredient{Name: "Milk"}},
+						{IngredientID: 2, Quantity: 500, Unit: "g", Ingredient: models.Ingredient{Name: "Flour"}},
+						{IngredientID: 3, Quantity: 2, Unit: "cloves", Ingredient: models.Ingredient{Name: "Garlic"}},
+					},
+				}, nil
+			},
+		},
+	)
+
+	resp, err := service.ScaleRecipe(1, 1, 4, "metric")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Ingredients[0].Unit != "ml" || resp.Ingredients[0].Quantity < 473 || resp.Ingredients[0].Quantity > 474 {
+		t.Fatalf("expected ~473 ml milk, got %+v", resp.Ingredients[0])
+	}
+	if resp.Ingredients[1].Unit != "g" || resp.Ingredients[1].Quantity != 1000 {
+		t.Fatalf("expected metric flour kept in g, got %+v", resp.Ingredients[1])
+	}
+	if resp.Ingredients[2].Unit != "cloves" || resp.Ingredients[2].Quantity != 4 {
+		t.Fatalf("expected garlic untouched, got %+v", resp.Ingredients[2])
+	}
+
+	resp, _ = service.ScaleRecipe(1, 1, 2, "us")
+	if resp.Ingredients[1].Unit != "lb" {
+		t.Fatalf("expected flour in lb, got %+v", resp.Ingredients[1])
+	}
+}
+
+func TestScaleRecipe_InvalidSystem(t *testing.T) {
+	service := NewRecipeScaleService(&MockRecipeRepoForScale{})
+
+	_, err := service.ScaleRecipe(1, 1, 2, "nautical")
+	if err != units.ErrUnknownSystem {
+		t.Fatalf("expected ErrUnknownSystem, got %v", err)
+	}
+}
@@ -2,34 +2,49 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/diet"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/parser"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/storage"
 	"gorm.io/gorm"
 )
 
 var ErrUnauthorized = errors.New("not authorized")
+var ErrInvalidRecipeQuery = errors.New("invalid recipe query")
+var ErrIngredientsText = errors.New("could not parse ingredients_text")
 
 type RecipeService interface {
 	CreateRecipe(userID uint, req dto.CreateRecipeRequest) (uint, error)
-	GetMyRecipes(userID uint) ([]dto.RecipeResponse, error)
+	GetMyRecipes(userID uint, query dto.RecipeListQuery) ([]dto.RecipeResponse, int64, error)
 	UpdateRecipe(recipeID uint, userID uint, req dto.UpdateRecipeRequest) error
 	DeleteRecipe(recipeID uint, userID uint) error
 	GetRecipeByID(recipeID uint, userID uint) (*dto.RecipeDetailResponse, error)
 }
 
 type recipeService struct {
-	Repo repository.RecipeRepository
-	DB   *gorm.DB
+	Repo        repository.RecipeRepository
+	CookLogRepo repository.CookLogRepository
+	DB          *gorm.DB
+	Storage     storage.Storage
 }
 
-func NewRecipeService(repo repository.RecipeRepository, db *gorm.DB) RecipeService {
-	return &recipeService{Repo: repo, DB: db}
+func NewRecipeService(repo repository.RecipeRepository, cookLogRepo repository.CookLogRepository, db *gorm.DB, store storage.Storage) RecipeService {
+	return &recipeService{Repo: repo, CookLogRepo: cookLogRepo, DB: db, Storage: store}
 }
 
 func (s *recipeService) CreateRecipe(userID uint, req dto.CreateRecipeRequest) (uint, error) {
 
+	parsed, err := parseIngredientsText(req.IngredientsText)
+	if err != nil {
+		return 0, err
+	}
+	req.Ingredients = append(req.Ingredients, parsed...)
+
 	if len(req.Ingredients) == 0 {
 		return 0, errors.New("at least one ingredient is required")
 	}
@@ -42,14 +57,15 @@ func (s *recipeService) CreateRecipe(userID uint, req dto.CreateRecipeRequest) (
 		CookTime:    req.CookTime,
 		Servings:    req.Servings,
 		Category:    req.Category,
+		Nutrition:   recipeNutritionModel(req.Nutrition),
 	}
 
 	for _, ingDTO := range req.Ingredients {
 		if ingDTO.Name == "" {
 			continue
 		}
-		var ingredient models.Ingredient
-		if err := s.DB.FirstOrCreate(&ingredient, models.Ingredient{Name: ingDTO.Name}).Error; err != nil {
+		ingredient, err := resolveIngredient(s.DB, ingDTO.Name)
+		if err != nil {
 			return 0, err
 		}
 
@@ -57,6 +73,7 @@ func (s *recipeService) CreateRecipe(userID uint, req dto.CreateRecipeRequest) (
 			IngredientID: ingredient.ID,
 			Quantity:     ingDTO.Amount,
 			Unit:         ingDTO.Unit,
+			Note:         ingDTO.Note,
 		})
 	}
 
@@ -67,6 +84,12 @@ func (s *recipeService) CreateRecipe(userID uint, req dto.CreateRecipeRequest) (
 		})
 	}
 
+	tags, err := resolveTags(s.DB, userID, req.Tags)
+	if err != nil {
+		return 0, err
+	}
+	recipe.Tags = tags
+
 	if err := s.Repo.Create(&recipe); err != nil {
 		return 0, err
 	}
@@ -74,10 +97,15 @@ func (s *recipeService) CreateRecipe(userID uint, req dto.CreateRecipeRequest) (
 	return recipe.ID, nil
 }
 
-func (s *recipeService) GetMyRecipes(userID uint) ([]dto.RecipeResponse, error) {
-	recipes, err := s.Repo.FindByUserID(userID)
+func (s *recipeService) GetMyRecipes(userID uint, query dto.RecipeListQuery) ([]dto.RecipeResponse, int64, error) {
+	filter, err := buildRecipeFilter(query)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	recipes, total, err := s.Repo.Search(userID, filter)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	var response []dto.RecipeResponse
@@ -89,10 +117,89 @@ func (s *recipeService) GetMyRecipes(userID uint) ([]dto.RecipeResponse, error)
 			TotalTime:   r.PrepTime + r.CookTime,
 			Description: r.Description,
 			Category:    r.Category,
+			Tags:        tagNames(r.Tags),
 		})
 	}
 
-	return response, nil
+	return response, total, nil
+}
+
+func buildRecipeFilter(query dto.RecipeListQuery) (repository.RecipeFilter, error) {
+	filter := repository.RecipeFilter{
+		Search:             strings.TrimSpace(query.Q),
+		Category:           strings.TrimSpace(query.Category),
+		MaxTotalTime:       query.MaxTotalTime,
+		MinServings:        query.MinServings,
+		MaxServings:        query.MaxServings,
+		IncludeIngredients: compactTerms(query.Ingredients),
+		ExcludeIngredients: compactTerms(query.ExcludeIngredients),
+		Tags:               compactTerms(query.Tags),
+		SortBy:             "created",
+		SortDesc:           true,
+	}
+
+	if query.MaxServings > 0 && query.MinServings > query.MaxServings {
+		return filter, ErrInvalidRecipeQuery
+	}
+
+	if query.Sort != "" {
+		if _, ok := repository.RecipeSortColumns[query.Sort]; !ok {
+			return filter, ErrInvalidRecipeQuery
+		}
+		filter.SortBy = query.Sort
+		// names and times read naturally ascending, dates newest first
+		filter.SortDesc = query.Sort == "created" || query.Sort == "updated"
+	}
+
+	var err error
+	if filter.Diets, err = diet.NormalizeDiets(compactTerms(query.Diets)); err != nil {
+		return filter, ErrInvalidRecipeQuery
+	}
+	if filter.ExcludeAllergens, err = diet.NormalizeAllergens(compactTerms(query.ExcludeAllergens)); err != nil {
+		return filter, ErrInvalidRecipeQuery
+	}
+
+	switch strings.ToLower(query.TagMatch) {
+	case "", "any":
+	case "all":
+		filter.MatchAllTags = true
+	default:
+		return filter, ErrInvalidRecipeQuery
+	}
+
+	switch strings.ToLower(query.Order) {
+	case "":
+	case "asc":
+		filter.SortDesc = false
+	case "desc":
+		filter.SortDesc = true
+	default:
+		return filter, ErrInvalidRecipeQuery
+	}
+
+	if query.Limit > 0 {
+		page := query.Page
+		if page < 1 {
+			page = 1
+		}
+		filter.Limit = query.Limit
+		filter.Offset = (page - 1) * query.Limit
+	}
+
+	return filter, nil
+}
+
+// compactTerms splits comma separated values and drops blanks.
+func compactTerms(values []string) []string {
+	var terms []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				terms = append(terms, part)
+			}
+		}
+	}
+	return terms
 }
 
 func (s *recipeService) UpdateRecipe(recipeID uint, userID uint, req dto.UpdateRecipeRequest) error {
@@ -108,6 +215,12 @@ func (s *recipeService) UpdateRecipe(recipeID uint, userID uint, req dto.UpdateR
 		return ErrUnauthorized
 	}
 
+	parsed, err := parseIngredientsText(req.IngredientsText)
+	if err != nil {
+		return err
+	}
+	req.Ingredients = append(req.Ingredients, parsed...)
+
 	tx := s.DB.Begin()
 
 	recipe.Name = req.Name
@@ -116,6 +229,9 @@ func (s *recipeService) UpdateRecipe(recipeID uint, userID uint, req dto.UpdateR
 	recipe.PrepTime = req.PrepTime
 	recipe.CookTime = req.CookTime
 	recipe.Category = req.Category
+	if req.Nutrition != nil {
+		recipe.Nutrition = recipeNutritionModel(req.Nutrition)
+	}
 
 	if err := tx.Save(recipe).Error; err != nil {
 		tx.Rollback()
@@ -145,9 +261,8 @@ func (s *recipeService) UpdateRecipe(recipeID uint, userID uint, req dto.UpdateR
 	}
 
 	for _, ingDTO := range req.Ingredients {
-		var ingredient models.Ingredient
-
-		if err := tx.FirstOrCreate(&ingredient, models.Ingredient{Name: ingDTO.Name}).Error; err != nil {
+		ingredient, err := resolveIngredient(tx, ingDTO.Name)
+		if err != nil {
 			tx.Rollback()
 			return err
 		}
@@ -157,6 +272,7 @@ func (s *recipeService) UpdateRecipe(recipeID uint, userID uint, req dto.UpdateR
 			IngredientID: ingredient.ID,
 			Quantity:     ingDTO.Amount,
 			Unit:         ingDTO.Unit,
+			Note:         ingDTO.Note,
 		}
 		if err := tx.Create(&ri).Error; err != nil {
 			tx.Rollback()
@@ -164,7 +280,36 @@ func (s *recipeService) UpdateRecipe(recipeID uint, userID uint, req dto.UpdateR
 		}
 	}
 
-	return tx.Commit().Error
+	if req.Tags != nil {
+		tags, err := resolveTags(tx, userID, req.Tags)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Model(recipe).Association("Tags").Replace(tags); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// photos of steps that no longer exist
+	var dropped []models.Photo
+	if err := tx.Where("recipe_id = ? AND step > ?", recipeID, len(req.Instructions)).Find(&dropped).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(dropped) > 0 {
+		if err := tx.Delete(&dropped).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	removePhotoFiles(s.Storage, dropped)
+	return nil
 }
 
 func (s *recipeService) DeleteRecipe(recipeID uint, userID uint) error {
@@ -177,7 +322,11 @@ func (s *recipeService) DeleteRecipe(recipeID uint, userID uint) error {
 		return ErrUnauthorized
 	}
 
-	return s.Repo.Delete(recipe)
+	if err := s.Repo.Delete(recipe); err != nil {
+		return err
+	}
+	removePhotoFiles(s.Storage, recipe.Photos)
+	return nil
 }
 
 func (s *recipeService) GetRecipeByID(recipeID uint, userID uint) (*dto.RecipeDetailResponse, error) {
@@ -196,14 +345,21 @@ func (s *recipeService) GetRecipeByID(recipeID uint, userID uint) (*dto.RecipeDe
 			Name:     ri.Ingredient.Name,
 			Quantity: ri.Quantity,
 			Unit:     ri.Unit,
+			Note:     ri.Note,
 		})
 	}
 
+	photos := map[int]*models.Photo{}
+	for i := range recipe.Photos {
+		photos[recipe.Photos[i].Step] = &recipe.Photos[i]
+	}
+
 	var instructions []dto.InstructionResponse
 	for _, ins := range recipe.Instructions {
 		instructions = append(instructions, dto.InstructionResponse{
 			StepNumber: ins.StepNumber,
 			Text:       ins.Text,
+			Photo:      photoResponse(s.Storage, photos[ins.StepNumber]),
 		})
 	}
 
@@ -218,7 +374,128 @@ func (s *recipeService) GetRecipeByID(recipeID uint, userID uint) (*dto.RecipeDe
 		TotalTime:    recipe.PrepTime + recipe.CookTime,
 		Ingredients:  ingredients,
 		Instructions: instructions,
+		Tags:         tagNames(recipe.Tags),
+		Photo:        photoResponse(s.Storage, photos[0]),
+		Nutrition:    nutritionDTO(recipe.Nutrition),
+	}
+	labels := recipeLabels(recipe)
+	response.Allergens, response.Diets = labels.Allergens, labels.Diets
+
+	cooked, last, err := s.CookLogRepo.RecipeStats(recipe.ID)
+	if err != nil {
+		return nil, err
+	}
+	response.TimesCooked = int(cooked)
+	if last != nil {
+		response.LastCooked = last.Format("2006-01-02")
 	}
 
 	return response, nil
 }
+
+// recipeNutritionModel converts entered per-serving values, treating all
+// zeros as none given.
+func recipeNutritionModel(facts *dto.NutritionFacts) *models.Nutrition {
+	if facts == nil || *facts == (dto.NutritionFacts{}) {
+		return nil
+	}
+	return nutritionModel(facts)
+}
+
+// resolveTags finds the user's tags by name, case-insensitively, creating missing ones.
+func resolveTags(db *gorm.DB, userID uint, names []string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	seen := make(map[string]bool)
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var tag models.Tag
+		err := db.Where("user_id = ? AND LOWER(name) = ?", userID, key).First(&tag).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			tag = models.Tag{UserID: userID, Name: name}
+			err = db.Create(&tag).Error
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// resolveIngredient finds the ingredient a recipe line names, ignoring case,
+// spacing and plurals and following aliases, creating it when there is none.
+func resolveIngredient(db *gorm.DB, name string) (*models.Ingredient, error) {
+	name = parser.CleanName(name)
+	ingredient, err := repository.NewIngredientRepository(db).FindByName(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ingredient = &models.Ingredient{Name: name}
+		err = db.Create(ingredient).Error
+	}
+	return ingredient, err
+}
+
+func tagNames(tags []models.Tag) []string {
+	names := []string{}
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// recipeLabels works out a recipe's allergens and diets from its loaded
+// ingredients.
+func recipeLabels(recipe *models.Recipe) diet.Flags {
+	flags := make([]diet.Flags, 0, len(recipe.Ingredients))
+	for _, ri := range recipe.Ingredients {
+		flags = append(flags, diet.Flags{Allergens: ri.Ingredient.Allergens, Diets: ri.Ingredient.Diets})
+	}
+	return diet.Labels(flags)
+}
+
+// parseIngredientsText turns pasted ingredient lines into ingredient requests.
+func parseIngredientsText(text string) ([]dto.RecipeIngredientRequest, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	lines, errs := parser.ParseLines(text)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrIngredientsText, errs[0])
+	}
+
+	var result []dto.RecipeIngredientRequest
+	for _, line := range lines {
+		result = append(result, ingredientRequest(line))
+	}
+
+	return result, nil
+}
+
+// ingredientRequest maps a parsed line onto the request shape. Ranges use
+// their upper bound so shopping lists don't come up short.
+func ingredientRequest(line parser.Ingredient) dto.RecipeIngredientRequest {
+	amount := line.Amount
+	if line.AmountMax > 0 {
+		amount = line.AmountMax
+	}
+
+	note := line.Note
+	if line.Size != "" {
+		note = strings.TrimSuffix(line.Size+", "+note, ", ")
+	}
+
+	return dto.RecipeIngredientRequest{
+		Name:   line.Name,
+		Amount: amount,
+		Unit:   line.Unit,
+		Note:   note,
+	}
+}
@@ -2,23 +2,31 @@ package services
 
 import (
 	"errors"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 func setupTestDB() *gorm.DB {
-	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	db.AutoMigrate(&models.Ingredient{}, &models.Recipe{}, &models.Instruction{}, &models.RecipeIngredient{})
+	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
+	db.AutoMigrate(&models.Ingredient{}, &models.Recipe{}, &models.Instruction{}, &models.RecipeIngredient{}, &models.Tag{},
+		&models.MealPlan{}, &models.ShoppingList{}, &models.ShoppingListItem{}, &models.Photo{}, &models.CookEvent{},
+		&models.IngredientAlias{}, &models.IngredientPrice{}, &models.PantryItem{},
+		&models.ShoppingListChange{}, &models.ShoppingListSyncOp{})
 	return db
 }
 
 type MockRecipeRepository struct {
 	CreateFn              func(recipe *models.Recipe) error
 	FindByUserIDFn        func(userID uint) ([]models.Recipe, error)
+	SearchFn              func(userID uint, filter repository.RecipeFilter) ([]models.Recipe, int64, error)
 	FindByIDFn            func(id uint) (*models.Recipe, error)
 	FindByIDWithDetailsFn func(id uint) (*models.Recipe, error)
 	UpdateFn              func(recipe *models.Recipe) error
@@ -29,6 +37,9 @@ func (m *MockRecipeRepository) Create(r *models.Recipe) error { return m.CreateF
 func (m *MockRecipeRepository) FindByUserID(u uint) ([]models.Recipe, error) {
 	return m.FindByUserIDFn(u)
 }
+func (m *MockRecipeRepository) Search(u uint, f repository.RecipeFilter) ([]models.Recipe, int64, error) {
+	return m.SearchFn(u, f)
+}
 func (m *MockRecipeRepository) FindByID(id uint) (*models.Recipe, error) { return m.FindByIDFn(id) }
 func (m *MockRecipeRepository) FindByIDWithDetails(id uint) (*models.Recipe, error) {
 	if m.FindByIDWithDetailsFn != nil {
@@ -44,7 +55,7 @@ func TestCreateRecipe_Success(t *testing.T) {
 	repo := &MockRecipeRepository{
 		CreateFn: func(r *models.Recipe) error { r.ID = 1; return nil },
 	}
-	service := NewRecipeService(repo, db)
+	service := NewRecipeService(repo, repository.NewCookLogRepository(db), db, nil)
 
 	req := dto.CreateRecipeRequest{
 		Name: "Pasta", Servings: 2,
@@ -59,7 +70,7 @@ func TestCreateRecipe_Success(t *testing.T) {
 }
 
 func TestCreateRecipe_NoIngredients(t *testing.T) {
-	service := NewRecipeService(&MockRecipeRepository{}, nil)
+	service := NewRecipeService(&MockRecipeRepository{}, nil, nil, nil)
 	_, err := service.CreateRecipe(1, dto.CreateRecipeRequest{Ingredients: []dto.RecipeIngredientRequest{}})
 	if err == nil || err.Error() != "at least one ingredient is required" {
 		t.Fatal("expected error for no ingredients")
@@ -71,7 +82,7 @@ func TestCreateRecipe_RepoError(t *testing.T) {
 	repo := &MockRecipeRepository{
 		CreateFn: func(r *models.Recipe) error { return errors.New("db error") },
 	}
-	service := NewRecipeService(repo, db)
+	service := NewRecipeService(repo, repository.NewCookLogRepository(db), db, nil)
 	req := dto.CreateRecipeRequest{Ingredients: []dto.RecipeIngredientRequest{{Name: "A", Amount: 1}}}
 
 	_, err := service.CreateRecipe(1, req)
@@ -82,17 +93,175 @@ func TestCreateRecipe_RepoError(t *testing.T) {
 
 func TestGetMyRecipes_Success(t *testing.T) {
 	repo := &MockRecipeRepository{
-		FindByUserIDFn: func(u uint) ([]models.Recipe, error) {
-			return []models.Recipe{{ID: 1, Name: "A", PrepTime: 5, CookTime: 5}}, nil
+		SearchFn: func(u uint, f repository.RecipeFilter) ([]models.Recipe, int64, error) {
+			return []models.Recipe{{ID: 1, Name: "A", PrepTime: 5, CookTime: 5}}, 1, nil
 		},
 	}
-	service := NewRecipeService(repo, nil)
-	res, err := service.GetMyRecipes(1)
-	if err != nil || len(res) != 1 || res[0].TotalTime != 10 {
+	service := NewRecipeService(repo, nil, nil, nil)
+	res, total, err := service.GetMyRecipes(1, dto.RecipeListQuery{})
+	if err != nil || len(res) != 1 || res[0].TotalTime != 10 || total != 1 {
 		t.Fatal("failed to get recipes or calculate total time")
 	}
 }
 
+func TestGetMyRecipes_BuildsFilter(t *testing.T) {
+	var got repository.RecipeFilter
+	repo := &MockRecipeRepository{
+		SearchFn: func(u uint, f repository.RecipeFilter) ([]models.Recipe, int64, error) {
+			got = f
+			return nil, 0, nil
+		},
+	}
+	service := NewRecipeService(repo, nil, nil, nil)
+
+	_, _, err := service.GetMyRecipes(1, dto.RecipeListQuery{
+		Q:           " curry ",
+		Ingredients: []string{"onion, garlic", " "},
+		Sort:        "name",
+		Page:        3,
+		Limit:       10,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Search != "curry" || got.SortBy != "name" || got.SortDesc {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+	if len(got.IncludeIngredients) != 2 || got.IncludeIngredients[1] != "garlic" {
+		t.Fatalf("expected split ingredients, got %v", got.IncludeIngredients)
+	}
+	if got.Limit != 10 || got.Offset != 20 {
+		t.Fatalf("expected limit 10 offset 20, got %d/%d", got.Limit, got.Offset)
+	}
+}
+
+func TestGetMyRecipes_InvalidQuery(t *testing.T) {
+	service := NewRecipeService(&MockRecipeRepository{}, nil, nil, nil)
+
+	queries := []dto.RecipeListQuery{
+		{Sort: "calories"},
+		{Order: "sideways"},
+		{MinServings: 6, MaxServings: 2},
+	}
+	for _, q := range queries {
+		if _, _, err := service.GetMyRecipes(1, q); err != ErrInvalidRecipeQuery {
+			t.Fatalf("expected ErrInvalidRecipeQuery for %+v, got %v", q, err)
+		}
+	}
+}
+
+func TestGetMyRecipes_RepositorySearch(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewRecipeRepository(db)
+	service := NewRecipeService(repo, repository.NewCookLogRepository(db), db, nil)
+
+	recipes := []dto.CreateRecipeRequest{
+		{Name: "Tomato Soup", Description: "Warming", Servings: 2, PrepTime: 10, CookTime: 20, Category: "Soup",
+			Ingredients: []dto.RecipeIngredientRequest{{Name: "Tomato", Amount: 4, Unit: "pcs"}, {Name: "Cream", Amount: 100, Unit: "ml"}}},
+		{Name: "Pasta Pomodoro", Description: "Quick tomato pasta", Servings: 4, PrepTime: 5, CookTime: 10, Category: "Dinner",
+			Ingredients: []dto.RecipeIngredientRequest{{Name: "Tomato", Amount: 6, Unit: "pcs"}, {Name: "Pasta", Amount: 400, Unit: "g"}}},
+		{Name: "Pancakes", Servings: 4, PrepTime: 10, CookTime: 15, Category: "Breakfast",
+			Ingredients: []dto.RecipeIngredientRequest{{Name: "Flour", Amount: 200, Unit: "g"}}},
+	}
+	for _, r := range recipes {
+		if _, err := service.CreateRecipe(1, r); err != nil {
+			t.Fatalf("seed failed: %v", err)
+		}
+	}
+	if _, err := service.CreateRecipe(2, recipes[0]); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	res, total, err := service.GetMyRecipes(1, dto.RecipeListQuery{Q: "TOMATO", Sort: "name"})
+	if err != nil || total != 2 || res[0].Name != "Pasta Pomodoro" {
+		t.Fatalf("text search failed: %v %d %+v", err, total, res)
+	}
+
+	res, total, _ = service.GetMyRecipes(1, dto.RecipeListQuery{Ingredients: []string{"tomato"}, ExcludeIngredients: []string{"cream"}})
+	if total != 1 || res[0].Name != "Pasta Pomodoro" {
+		t.Fatalf("ingredient filter failed: %d %+v", total, res)
+	}
+
+	res, total, _ = service.GetMyRecipes(1, dto.RecipeListQuery{MaxTotalTime: 25, MinServings: 4})
+	if total != 2 || len(res) != 2 {
+		t.Fatalf("time/servings filter failed: %d %+v", total, res)
+	}
+
+	res, total, _ = service.GetMyRecipes(1, dto.RecipeListQuery{Sort: "total_time", Order: "desc", Page: 2, Limit: 2})
+	if total != 3 || len(res) != 1 || res[0].Name != "Pasta Pomodoro" {
+		t.Fatalf("pagination failed: %d %+v", total, res)
+	}
+}
+
+func TestRecipeLabels_DetailAndFilter(t *testing.T) {
+	db := setupTestDB()
+	service := NewRecipeService(repository.NewRecipeRepository(db), repository.NewCookLogRepository(db), db, nil)
+
+	db.Create(&models.Ingredient{Name: "Tomato", Diets: []string{"gluten-free", "keto", "vegan", "vegetarian"}})
+	db.Create(&models.Ingredient{Name: "Pasta", Allergens: []string{"gluten"}, Diets: []string{"vegan", "vegetarian"}})
+	db.Create(&models.Ingredient{Name: "Cream", Allergens: []string{"milk"}, Diets: []string{"gluten-free", "keto", "vegetarian"}})
+
+	create := func(name string, ingredients ...string) uint {
+		req := dto.CreateRecipeRequest{Name: name, Servings: 2, Category: "Dinner"}
+		for _, i := range ingredients {
+			req.Ingredients = append(req.Ingredients, dto.RecipeIngredientRequest{Name: i, Amount: 1, Unit: "cup"})
+		}
+		id, err := service.CreateRecipe(1, req)
+		if err != nil {
+			t.Fatalf("seed failed: %v", err)
+		}
+		return id
+	}
+	pomodoro := create("Pasta Pomodoro", "Tomato", "Pasta")
+	create("Tomato Soup", "Tomato", "Cream")
+	create("Mystery Stew", "Tomato", "Turnip") // nobody has said what turnip suits
+
+	detail, err := service.GetRecipeByID(pomodoro, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(detail.Allergens, ",") != "gluten" || strings.Join(detail.Diets, ",") != "vegan,vegetarian" {
+		t.Errorf("unexpected labels %v %v", detail.Allergens, detail.Diets)
+	}
+
+	names := func(query dto.RecipeListQuery) string {
+		res, _, err := service.GetMyRecipes(1, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, r := range res {
+			out = append(out, r.Name)
+		}
+		sort.Strings(out)
+		return strings.Join(out, ",")
+	}
+
+	if got := names(dto.RecipeListQuery{Diets: []string{"Vegetarian"}}); got != "Pasta Pomodoro,Tomato Soup" {
+		t.Errorf("diet filter: %s", got)
+	}
+	if got := names(dto.RecipeListQuery{Diets: []string{"vegetarian,gluten-free"}}); got != "Tomato Soup" {
+		t.Errorf("combined diet filter: %s", got)
+	}
+	if got := names(dto.RecipeListQuery{ExcludeAllergens: []string{"dairy", "wheat"}}); got != "Mystery Stew" {
+		t.Errorf("allergen filter: %s", got)
+	}
+
+	if _, _, err := service.GetMyRecipes(1, dto.RecipeListQuery{Diets: []string{"paleo"}}); err != ErrInvalidRecipeQuery {
+		t.Errorf("expected ErrInvalidRecipeQuery, got %v", err)
+	}
+}
+
+// MockCookLogRepository answers the recipe detail's cook stats.
+type MockCookLogRepository struct {
+	repository.CookLogRepository
+	RecipeStatsFn func(uint) (int64, *time.Time, error)
+}
+
+func (m *MockCookLogRepository) RecipeStats(recipeID uint) (int64, *time.Time, error) {
+	return m.RecipeStatsFn(recipeID)
+}
+
 func TestGetRecipeByID_Success(t *testing.T) {
 	repo := &MockRecipeRepository{
 		FindByIDWithDetailsFn: func(id uint) (*models.Recipe, error) {
@@ -103,11 +272,16 @@ func TestGetRecipeByID_Success(t *testing.T) {
 			}, nil
 		},
 	}
-	service := NewRecipeService(repo, nil)
+	last := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	cookLog := &MockCookLogRepository{RecipeStatsFn: func(uint) (int64, *time.Time, error) { return 2, &last, nil }}
+	service := NewRecipeService(repo, cookLog, nil, nil)
 	res, err := service.GetRecipeByID(1, 1)
 	if err != nil || res.Name != "A" || len(res.Ingredients) != 1 {
 		t.Fatal("failed to get recipe details")
 	}
+	if res.TimesCooked != 2 || res.LastCooked != "2025-03-01" {
+		t.Errorf("unexpected cook stats %d, %q", res.TimesCooked, res.LastCooked)
+	}
 }
 
 func TestUpdateRecipe_Success(t *testing.T) {
@@ -117,7 +291,7 @@ func TestUpdateRecipe_Success(t *testing.T) {
 			return &models.Recipe{ID: 1, UserID: 1}, nil
 		},
 	}
-	service := NewRecipeService(repo, db)
+	service := NewRecipeService(repo, repository.NewCookLogRepository(db), db, nil)
 
 	req := dto.UpdateRecipeRequest{
 		Name:         "New Name",
@@ -135,7 +309,7 @@ func TestUpdateRecipe_NotFound(t *testing.T) {
 	repo := &MockRecipeRepository{
 		FindByIDFn: func(id uint) (*models.Recipe, error) { return nil, gorm.ErrRecordNotFound },
 	}
-	service := NewRecipeService(repo, nil)
+	service := NewRecipeService(repo, nil, nil, nil)
 	err := service.UpdateRecipe(1, 1, dto.UpdateRecipeRequest{})
 	if !errors.Is(err, gorm.ErrRecordNotFound) {
 		t.Fatal("expected record not found error")
@@ -146,7 +320,7 @@ func TestUpdateRecipe_Unauthorized(t *testing.T) {
 	repo := &MockRecipeRepository{
 		FindByIDFn: func(id uint) (*models.Recipe, error) { return &models.Recipe{UserID: 2}, nil },
 	}
-	service := NewRecipeService(repo, nil)
+	service := NewRecipeService(repo, nil, nil, nil)
 	err := service.UpdateRecipe(1, 1, dto.UpdateRecipeRequest{})
 	if err != ErrUnauthorized {
 		t.Fatal("expected unauthorized error")
@@ -158,7 +332,7 @@ func TestDeleteRecipe_Success(t *testing.T) {
 		FindByIDFn: func(id uint) (*models.Recipe, error) { return &models.Recipe{ID: 1, UserID: 1}, nil },
 		DeleteFn:   func(r *models.Recipe) error { return nil },
 	}
-	service := NewRecipeService(repo, nil)
+	service := NewRecipeService(repo, nil, nil, nil)
 	err := service.DeleteRecipe(1, 1)
 	if err != nil {
 		t.Fatal("expected successful delete")
@@ -169,9 +343,100 @@ func TestDeleteRecipe_NotFound(t *testing.T) {
 	repo := &MockRecipeRepository{
 		FindByIDFn: func(id uint) (*models.Recipe, error) { return nil, errors.New("not found") },
 	}
-	service := NewRecipeService(repo, nil)
+	service := NewRecipeService(repo, nil, nil, nil)
 	err := service.DeleteRecipe(1, 1)
 	if err == nil {
 		t.Fatal("expected error for non-existent recipe")
 	}
 }
+
+func TestRecipeTags_CreateUpdateAndFilter(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewRecipeRepository(db)
+	service := NewRecipeService(repo, repository.NewCookLogRepository(db), db, nil)
+
+	ing := []dto.RecipeIngredientRequest{{Name: "Rice", Amount: 1, Unit: "cup"}}
+	dal, err := service.CreateRecipe(1, dto.CreateRecipeRequest{Name: "Dal", Servings: 2, Category: "Dinner",
+		Ingredients: ing, Tags: []string{"Vegetarian", "Indian", "vegetarian"}})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if _, err := service.CreateRecipe(1, dto.CreateRecipeRequest{Name: "Omelette", Servings: 1, Category: "Breakfast",
+		Ingredients: ing, Tags: []string{"Quick", "vegetarian"}}); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	var count int64
+	db.Model(&models.Tag{}).Where("user_id = ?", 1).Count(&count)
+	if count != 3 {
+		t.Fatalf("expected 3 distinct tags, got %d", count)
+	}
+
+	detail, _ := service.GetRecipeByID(dal, 1)
+	if len(detail.Tags) != 2 {
+		t.Fatalf("expected 2 tags on detail, got %v", detail.Tags)
+	}
+
+	res, total, _ := service.GetMyRecipes(1, dto.RecipeListQuery{Tags: []string{"indian", "quick"}})
+	if total != 2 || len(res) != 2 {
+		t.Fatalf("any-of filter failed: %d", total)
+	}
+	res, total, _ = service.GetMyRecipes(1, dto.RecipeListQuery{Tags: []string{"vegetarian,indian"}, TagMatch: "all"})
+	if total != 1 || res[0].Name != "Dal" {
+		t.Fatalf("all-of filter failed: %d %+v", total, res)
+	}
+	res, total, _ = service.GetMyRecipes(1, dto.RecipeListQuery{Tags: []string{"indian", "Indian", "vegetarian"}, TagMatch: "all"})
+	if total != 1 || res[0].Name != "Dal" {
+		t.Fatalf("repeated tags should count once: %d %+v", total, res)
+	}
+
+	update := dto.UpdateRecipeRequest{Name: "Dal", Servings: 2, Category: "Dinner", Ingredients: ing}
+	if err := service.UpdateRecipe(dal, 1, update); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	detail, _ = service.GetRecipeByID(dal, 1)
+	if len(detail.Tags) != 2 {
+		t.Fatalf("omitted tags should be kept, got %v", detail.Tags)
+	}
+
+	update.Tags = []string{"Quick"}
+	if err := service.UpdateRecipe(dal, 1, update); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	detail, _ = service.GetRecipeByID(dal, 1)
+	if len(detail.Tags) != 1 || detail.Tags[0] != "Quick" {
+		t.Fatalf("expected tags replaced, got %v", detail.Tags)
+	}
+}
+
+func TestCreateRecipe_IngredientsText(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewRecipeRepository(db)
+	service := NewRecipeService(repo, repository.NewCookLogRepository(db), db, nil)
+
+	id, err := service.CreateRecipe(1, dto.CreateRecipeRequest{Name: "Soup", Servings: 4, Category: "Dinner",
+		IngredientsText: "2-3 cloves garlic, minced\n\n1 (14 oz) can diced tomatoes\nSalt to taste"})
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	detail, _ := service.GetRecipeByID(id, 1)
+	if len(detail.Ingredients) != 3 {
+		t.Fatalf("expected 3 ingredients, got %+v", detail.Ingredients)
+	}
+	garlic, tomatoes := detail.Ingredients[0], detail.Ingredients[1]
+	if garlic.Name != "garlic" || garlic.Quantity != 3 || garlic.Unit != "clove" || garlic.Note != "minced" {
+		t.Errorf("unexpected garlic line %+v", garlic)
+	}
+	if tomatoes.Name != "tomatoes" || tomatoes.Unit != "can" || tomatoes.Note != "14 oz, diced" {
+		t.Errorf("unexpected tomatoes line %+v", tomatoes)
+	}
+}
+
+func TestCreateRecipe_IngredientsTextError(t *testing.T) {
+	service := NewRecipeService(&MockRecipeRepository{}, nil, nil, nil)
+	_, err := service.CreateRecipe(1, dto.CreateRecipeRequest{Name: "Soup", IngredientsText: "1 onion\n2 cups"})
+	if !errors.Is(err, ErrIngredientsText) {
+		t.Fatalf("expected ErrIngredientsText, got %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+)
+
+var ErrInvalidWeights = errors.New("at least one recommendation weight must be positive")
+
+// DefaultRecommendationWeights favour variety over time, then reusing
+// groceries, then spreading categories.
+var DefaultRecommendationWeights = dto.RecommendationWeights{Recency: 0.5, Overlap: 0.3, Variety: 0.2}
+
+const (
+	defaultExcludeDays     = 7
+	defaultRecommendations = 10
+	// a recipe last planned this many days away or more scores full recency
+	recencyHorizonDays = 30
+)
+
+type RecommendationService interface {
+	Recommend(userID uint, query dto.RecommendationQuery) (*dto.RecommendationResponse, error)
+}
+
+type recommendationService struct {
+	RecipeRepo   repository.RecipeRepository
+	MealPlanRepo repository.MealPlanRepository
+}
+
+func NewRecommendationService(recipeRepo repository.RecipeRepository, mealPlanRepo repository.MealPlanRepository) RecommendationService {
+	return &recommendationService{RecipeRepo: recipeRepo, MealPlanRepo: mealPlanRepo}
+}
+
+// Recommend ranks the user's recipes for the week around query.Date, the
+// Monday to Sunday that contains it.
+func (s *recommendationService) Recommend(userID uint, query dto.RecommendationQuery) (*dto.RecommendationResponse, error) {
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if query.Date != "" {
+		parsed, err := time.Parse("2006-01-02", query.Date)
+		if err != nil {
+			return nil, err
+		}
+		date = parsed
+	}
+
+	weights := DefaultRecommendationWeights
+	if query.Recency != nil {
+		weights.Recency = *query.Recency
+	}
+	if query.Overlap != nil {
+		weights.Overlap = *query.Overlap
+	}
+	if query.Variety != nil {
+		weights.Variety = *query.Variety
+	}
+	totalWeight := weights.Recency + weights.Overlap + weights.Variety
+	if totalWeight <= 0 {
+		return nil, ErrInvalidWeights
+	}
+
+	excludeDays := defaultExcludeDays
+	if query.ExcludeDays != nil {
+		excludeDays = *query.ExcludeDays
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultRecommendations
+	}
+
+	weekStart := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+	weekEnd := weekStart.AddDate(0, 0, 6)
+
+	recipes, err := s.RecipeRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	plans, err := s.MealPlanRepo.FindByUserAndDateRange(userID, time.Time{}, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	week := summariseWeek(plans, weekStart)
+	last, upcoming := lastPlans(plans, date)
+
+	resp := &dto.RecommendationResponse{
+		Date:            date.Format("2006-01-02"),
+		WeekStart:       weekStart.Format("2006-01-02"),
+		WeekEnd:         weekEnd.Format("2006-01-02"),
+		Weights:         weights,
+		Recommendations: []dto.RecipeRecommendation{},
+	}
+
+	for _, r := range recipes {
+		rec := dto.RecipeRecommendation{
+			Recipe: dto.RecipeResponse{
+				ID:          r.ID,
+				Name:        r.Name,
+				Servings:    r.Servings,
+				TotalTime:   r.PrepTime + r.CookTime,
+				Description: r.Description,
+				Category:    r.Category,
+				Tags:        tagNames(r.Tags),
+			},
+			SharedIngredients: []string{},
+		}
+
+		// a recipe already on the calendar later this week is planned
+		if excludeDays > 0 && upcoming[r.ID] {
+			continue
+		}
+		recency := 1.0
+		if planned, ok := last[r.ID]; ok {
+			since := daysBetween(planned, date)
+			if excludeDays > 0 && since <= excludeDays {
+				continue
+			}
+			recency = math.Min(float64(since)/recencyHorizonDays, 1)
+			rec.LastPlanned = planned.Format("2006-01-02")
+		}
+
+		var overlap float64
+		rec.SharedIngredients, overlap = week.shared(r)
+
+		variety := 1.0
+		if week.meals > 0 {
+			variety = 1 - float64(week.categories[categoryKey(r.Category)])/float64(week.meals)
+		}
+
+		rec.Signals = dto.RecommendationSignals{
+			Recency: signalScore(recency, weights.Recency, totalWeight),
+			Overlap: signalScore(overlap, weights.Overlap, totalWeight),
+			Variety: signalScore(variety, weights.Variety, totalWeight),
+		}
+		rec.Score = roundScore((recency*weights.Recency + overlap*weights.Overlap + variety*weights.Variety) / totalWeight)
+
+		resp.Recommendations = append(resp.Recommendations, rec)
+	}
+
+	sort.SliceStable(resp.Recommendations, func(i, j int) bool {
+		a, b := resp.Recommendations[i], resp.Recommendations[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return strings.ToLower(a.Recipe.Name) < strings.ToLower(b.Recipe.Name)
+	})
+	if len(resp.Recommendations) > limit {
+		resp.Recommendations = resp.Recommendations[:limit]
+	}
+
+	return resp, nil
+}
+
+// plannedWeek is what is already on the plan for the week.
+type plannedWeek struct {
+	meals       int
+	categories  map[string]int
+	ingredients map[uint]map[uint]bool // ingredient -> recipes using it
+}
+
+func summariseWeek(plans []models.MealPlan, weekStart time.Time) plannedWeek {
+	week := plannedWeek{categories: make(map[string]int), ingredients: make(map[uint]map[uint]bool)}
+	for _, mp := range plans {
+		if mp.Date.Before(weekStart) {
+			continue
+		}
+		week.meals++
+		week.categories[categoryKey(mp.Recipe.Category)]++
+		for _, ri := range mp.Recipe.Ingredients {
+			if week.ingredients[ri.IngredientID] == nil {
+				week.ingredients[ri.IngredientID] = make(map[uint]bool)
+			}
+			week.ingredients[ri.IngredientID][mp.RecipeID] = true
+		}
+	}
+	return week
+}
+
+// shared lists the recipe's ingredients that other recipes planned this
+// week also use, and their share of the recipe's ingredients.
+func (w plannedWeek) shared(recipe models.Recipe) ([]string, float64) {
+	names := []string{}
+	seen := make(map[uint]bool)
+	for _, ri := range recipe.Ingredients {
+		if seen[ri.IngredientID] {
+			continue
+		}
+		seen[ri.IngredientID] = true
+
+		for id := range w.ingredients[ri.IngredientID] {
+			if id != recipe.ID {
+				names = append(names, ri.Ingredient.Name)
+				break
+			}
+		}
+	}
+	if len(seen) == 0 {
+		return names, 0
+	}
+	sort.Strings(names)
+	return names, float64(len(names)) / float64(len(seen))
+}
+
+// lastPlans finds, per recipe, the latest date it was planned on or
+// before day, and which recipes are planned after it.
+func lastPlans(plans []models.MealPlan, day time.Time) (map[uint]time.Time, map[uint]bool) {
+	last := make(map[uint]time.Time)
+	upcoming := make(map[uint]bool)
+	for _, mp := range plans {
+		if mp.Date.After(day) {
+			upcoming[mp.RecipeID] = true
+			continue
+		}
+		if current, ok := last[mp.RecipeID]; !ok || mp.Date.After(current) {
+			last[mp.RecipeID] = mp.Date
+		}
+	}
+	return last, upcoming
+}
+
+// daysBetween counts whole days from a to b, negative when b is earlier.
+func daysBetween(a, b time.Time) int {
+	return int(math.Round(b.Sub(a).Hours() / 24))
+}
+
+func categoryKey(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+func signalScore(value, weight, totalWeight float64) dto.SignalScore {
+	return dto.SignalScore{
+		Value:  roundScore(value),
+		Weight: weight,
+		Points: roundScore(value * weight / totalWeight),
+	}
+}
+
+func roundScore(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
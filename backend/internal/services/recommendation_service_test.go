@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+)
+
+func TestRecommend(t *testing.T) {
+	ing := func(id uint, name string) models.RecipeIngredient {
+		return models.RecipeIngredient{IngredientID: id, Ingredient: models.Ingredient{ID: id, Name: name}}
+	}
+	recipes := []models.Recipe{
+		{ID: 1, Name: "Pasta", Category: "Dinner", Ingredients: []models.RecipeIngredient{ing(1, "Tomato"), ing(2, "Pasta")}},
+		{ID: 2, Name: "Soup", Category: "Dinner", Ingredients: []models.RecipeIngredient{ing(1, "Tomato"), ing(3, "Onion")}},
+		{ID: 3, Name: "Pancakes", Category: "Breakfast", Ingredients: []models.RecipeIngredient{ing(4, "Flour"), ing(5, "Egg")}},
+		{ID: 4, Name: "Omelette", Category: "Breakfast", Ingredients: []models.RecipeIngredient{ing(5, "Egg"), ing(3, "Onion")}},
+		{ID: 5, Name: "Salad", Category: "Lunch", Ingredients: []models.RecipeIngredient{ing(6, "Lettuce")}},
+	}
+	day := func(s string) time.Time { d, _ := time.Parse("2006-01-02", s); return d }
+	plans := []models.MealPlan{
+		{RecipeID: 1, Recipe: recipes[0], Date: day("2025-01-06")},
+		{RecipeID: 4, Recipe: recipes[3], Date: day("2025-01-05")},
+		{RecipeID: 2, Recipe: recipes[1], Date: day("2024-11-01")},
+	}
+
+	service := NewRecommendationService(
+		&MockRecipeRepository{FindByUserIDFn: func(uint) ([]models.Recipe, error) { return recipes, nil }},
+		&MockMealPlanRepo{FindByUserAndDateRangeFn: func(uint, time.Time, time.Time) ([]models.MealPlan, error) {
+			return plans, nil
+		}},
+	)
+
+	resp, err := service.Recommend(1, dto.RecommendationQuery{Date: "2025-01-08"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.WeekStart != "2025-01-06" || resp.WeekEnd != "2025-01-12" {
+		t.Errorf("unexpected week %s to %s", resp.WeekStart, resp.WeekEnd)
+	}
+
+	// pasta and the omelette were planned in the last 7 days
+	var names []string
+	for _, r := range resp.Recommendations {
+		names = append(names, r.Recipe.Name)
+	}
+	if len(names) != 3 || names[0] != "Pancakes" || names[1] != "Salad" || names[2] != "Soup" {
+		t.Fatalf("unexpected ranking %v", names)
+	}
+
+	soup := resp.Recommendations[2]
+	if soup.Score != 0.65 || soup.LastPlanned != "2024-11-01" || len(soup.SharedIngredients) != 1 || soup.SharedIngredients[0] != "Tomato" {
+		t.Errorf("unexpected soup %+v", soup)
+	}
+	if soup.Signals.Overlap.Value != 0.5 || soup.Signals.Overlap.Points != 0.15 || soup.Signals.Variety.Value != 0 {
+		t.Errorf("unexpected soup signals %+v", soup.Signals)
+	}
+
+	zero, one, none := 0.0, 1.0, 0
+	resp, err = service.Recommend(1, dto.RecommendationQuery{Date: "2025-01-08", ExcludeDays: &none,
+		Recency: &zero, Overlap: &one, Variety: &zero, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Recommendations) != 2 || resp.Recommendations[0].Recipe.Name != "Soup" || resp.Recommendations[0].Score != 0.5 {
+		t.Errorf("unexpected overlap ranking %+v", resp.Recommendations)
+	}
+
+	if _, err := service.Recommend(1, dto.RecommendationQuery{Recency: &zero, Overlap: &zero, Variety: &zero}); err != ErrInvalidWeights {
+		t.Errorf("expected ErrInvalidWeights, got %v", err)
+	}
+}
+
+func TestRecommend_PastAndUpcomingPlans(t *testing.T) {
+	recipes := []models.Recipe{
+		{ID: 1, Name: "Pasta", Category: "Dinner"},
+		{ID: 2, Name: "Salad", Category: "Lunch"},
+		{ID: 3, Name: "Soup", Category: "Dinner"},
+	}
+	day := func(s string) time.Time { d, _ := time.Parse("2006-01-02", s); return d }
+	plans := []models.MealPlan{
+		{RecipeID: 1, Recipe: recipes[0], Date: day("2025-01-06")},
+		{RecipeID: 1, Recipe: recipes[0], Date: day("2025-01-09")},
+		{RecipeID: 2, Recipe: recipes[1], Date: day("2024-12-01")},
+		{RecipeID: 2, Recipe: recipes[1], Date: day("2025-01-10")},
+	}
+	service := NewRecommendationService(
+		&MockRecipeRepository{FindByUserIDFn: func(uint) ([]models.Recipe, error) { return recipes, nil }},
+		&MockMealPlanRepo{FindByUserAndDateRangeFn: func(uint, time.Time, time.Time) ([]models.MealPlan, error) {
+			return plans, nil
+		}},
+	)
+
+	// pasta was planned two days ago, and both are on the calendar later this week
+	one := 1
+	for _, exclude := range []*int{nil, &one} {
+		resp, err := service.Recommend(1, dto.RecommendationQuery{Date: "2025-01-08", ExcludeDays: exclude})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Recommendations) != 1 || resp.Recommendations[0].Recipe.Name != "Soup" {
+			t.Errorf("expected only soup, got %+v", resp.Recommendations)
+		}
+	}
+
+	// recency counts back to the last plan, never forward
+	none := 0
+	resp, err := service.Recommend(1, dto.RecommendationQuery{Date: "2025-01-08", ExcludeDays: &none})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := map[string]string{}
+	for _, r := range resp.Recommendations {
+		last[r.Recipe.Name] = r.LastPlanned
+	}
+	if last["Pasta"] != "2025-01-06" || last["Salad"] != "2024-12-01" || last["Soup"] != "" {
+		t.Errorf("unexpected last planned dates %v", last)
+	}
+}
@@ -1,37 +1,91 @@
 package services
 
 import (
+	"bytes"
 	"errors"
+	"log"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/aisle"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/listexport"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/pricing"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/realtime"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/units"
+	"gorm.io/gorm"
 )
 
 var ErrInvalidDateRange = errors.New("invalid date range")
+var ErrItemNameFixed = errors.New("only free-text items can be renamed")
+var ErrItemNameRequired = errors.New("give the item a name or an ingredient_id")
 
 type ShoppingListService interface {
-	Generate(userID uint, startDate string, endDate string) (*dto.ShoppingListResponse, error)
-	GetShoppingListByID(listID uint, userID uint) (*dto.ShoppingListResponse, error)
+	// Generate sums the ingredients planned between the dates. With
+	// usePantry, stock on hand is taken off and fully covered lines dropped.
+	Generate(userID uint, startDate string, endDate string, usePantry bool) (*dto.ShoppingListResponse, error)
+	// GetShoppingListByID returns the list with its items in the aisle order
+	// of the user's store layout, or the default order when storeID is 0.
+	GetShoppingListByID(listID uint, userID uint, storeID uint) (*dto.ShoppingListResponse, error)
+	// Refresh recomputes the list from the meal plans in its date range and
+	// updates the saved items in place, keeping checkmarks and manual items.
+	// The response lists what changed.
+	Refresh(listID uint, userID uint, usePantry bool) (*dto.ShoppingListResponse, error)
+	// ExportShoppingList writes the list as txt, md, csv or pdf, grouped by
+	// the aisles of the store layout, or the default order when storeID is 0.
+	ExportShoppingList(listID uint, userID uint, storeID uint, format string) (*dto.ShoppingListExport, error)
+	// ListShoppingLists pages through the user's lists newest first and
+	// returns the total count alongside.
+	ListShoppingLists(userID uint, query dto.ShoppingListQuery) ([]dto.ShoppingListSummary, int64, error)
+	DeleteShoppingList(listID uint, userID uint) error
+	// AddItem adds a manual item, which later regenerations of the same
+	// dates carry over.
+	AddItem(listID uint, userID uint, req dto.AddManualItemRequest) (*dto.ShoppingListItemResponse, error)
+	UpdateItem(itemID uint, userID uint, req dto.UpdateShoppingListItemRequest) (*dto.ShoppingListItemResponse, error)
+	DeleteItem(itemID uint, userID uint) error
 	ToggleItemChecked(itemID uint, userID uint) error
+	// Subscribe streams the list's changes as they happen, to the owner
+	// only. Call the returned function to stop.
+	Subscribe(listID uint, userID uint) (<-chan realtime.Event, func(), error)
+	// Sync applies operations a client queued offline and returns what
+	// changed on the list since the client's change token.
+	Sync(listID uint, userID uint, req dto.SyncShoppingListRequest) (*dto.SyncShoppingListResponse, error)
 }
 
 type shoppingListService struct {
 	MealPlanRepo         repository.MealPlanRepository
 	RecipeIngredientRepo repository.RecipeIngredientRepository
 	ShoppingListRepo     repository.ShoppingListRepository
+	PriceRepo            repository.PriceRepository
+	PantryRepo           repository.PantryRepository
+	StoreRepo            repository.StoreLayoutRepository
+	IngredientRepo       repository.IngredientRepository
+	Hub                  realtime.Hub
 }
 
 func NewShoppingListService(
 	mealPlanRepo repository.MealPlanRepository,
 	recipeIngredientRepo repository.RecipeIngredientRepository,
 	shoppingListRepo repository.ShoppingListRepository,
+	priceRepo repository.PriceRepository,
+	pantryRepo repository.PantryRepository,
+	storeRepo repository.StoreLayoutRepository,
+	ingredientRepo repository.IngredientRepository,
+	hub realtime.Hub,
 ) ShoppingListService {
 	return &shoppingListService{
 		MealPlanRepo:         mealPlanRepo,
 		RecipeIngredientRepo: recipeIngredientRepo,
 		ShoppingListRepo:     shoppingListRepo,
+		PriceRepo:            priceRepo,
+		PantryRepo:           pantryRepo,
+		StoreRepo:            storeRepo,
+		IngredientRepo:       ingredientRepo,
+		Hub:                  hub,
 	}
 }
 
@@ -39,6 +93,7 @@ func (s *shoppingListService) Generate(
 	userID uint,
 	startDateStr string,
 	endDateStr string,
+	usePantry bool,
 ) (*dto.ShoppingListResponse, error) {
 
 	startDate, err := time.Parse("2006-01-02", startDateStr)
@@ -55,46 +110,37 @@ func (s *shoppingListService) Generate(
 		return nil, ErrInvalidDateRange
 	}
 
-	mealPlans, err := s.MealPlanRepo.FindByUserAndDateRange(userID, startDate, endDate)
+	aggregated, covered, err := s.neededLines(userID, startDate, endDate, usePantry)
 	if err != nil {
 		return nil, err
 	}
 
-	type key struct {
-		IngredientID uint
-		Unit         string
-	}
-	type aggrItem struct {
-		Name     string
-		Quantity float64
-	}
-
-	aggregated := make(map[key]*aggrItem)
-
-	for _, mp := range mealPlans {
-
-		baseServings := mp.Recipe.Servings
-		if baseServings == 0 {
-			baseServings = 1
+	// items the user added to the last list for these dates carry over
+	var manual []models.ShoppingListItem
+	previous, err := s.ShoppingListRepo.FindLatestByRange(userID, startDate, endDate)
+	if err == nil {
+		for _, item := range previous.Items {
+			if item.Manual {
+				manual = append(manual, item)
+			}
 		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
 
-		ratio := float64(mp.TargetServings) / float64(baseServings)
-
-		for _, item := range mp.Recipe.Ingredients {
-			k := key{item.IngredientID, item.Unit}
-
-			scaledQuantity := item.Quantity * ratio
-
-			if v, ok := aggregated[k]; ok {
-				v.Quantity += scaledQuantity
-			} else {
-				aggregated[k] = &aggrItem{
-					Name:     item.Ingredient.Name,
-					Quantity: scaledQuantity,
-				}
-			}
+	var ids []uint
+	for _, v := range aggregated {
+		ids = append(ids, v.IngredientID)
+	}
+	for _, item := range manual {
+		if item.IngredientID != nil {
+			ids = append(ids, *item.IngredientID)
 		}
 	}
+	prices, err := s.PriceRepo.FindLatest(userID, ids)
+	if err != nil {
+		return nil, err
+	}
 
 	list := &models.ShoppingList{
 		UserID:    userID,
@@ -108,12 +154,13 @@ func (s *shoppingListService) Generate(
 
 	var responseItems []dto.ShoppingListItemResponse
 
-	for k, v := range aggregated {
+	for _, v := range aggregated {
 		slItem := &models.ShoppingListItem{
 			ShoppingListID: list.ID,
-			IngredientID:   k.IngredientID,
+			IngredientID:   &v.IngredientID,
 			Quantity:       v.Quantity,
-			Unit:           k.Unit,
+			Needed:         v.Needed,
+			Unit:           v.Unit,
 			Checked:        false,
 		}
 
@@ -123,25 +170,127 @@ func (s *shoppingListService) Generate(
 
 		responseItems = append(responseItems, dto.ShoppingListItemResponse{
 			ID:           slItem.ID,
-			IngredientID: k.IngredientID,
+			Version:      slItem.Version,
+			IngredientID: &v.IngredientID,
 			Name:         v.Name,
 			Quantity:     v.Quantity,
-			Unit:         k.Unit,
+			Unit:         v.Unit,
+			Aisle:        aisle.Of(v.Ingredient.Aisle, v.Name),
+			Needed:       v.Needed,
+			ToBuy:        v.Quantity,
+			Display:      units.FormatQuantity(v.Quantity, v.Unit),
 			Checked:      slItem.Checked,
+			Cost:         itemCost(prices, v.Ingredient, v.Quantity, v.Unit),
 		})
 	}
 
-	return &dto.ShoppingListResponse{
+	for _, item := range manual {
+		slItem := &models.ShoppingListItem{
+			ShoppingListID: list.ID,
+			IngredientID:   item.IngredientID,
+			Name:           item.Name,
+			Quantity:       item.Quantity,
+			Unit:           item.Unit,
+			Note:           item.Note,
+			Manual:         true,
+			Checked:        item.Checked,
+		}
+		if err := s.ShoppingListRepo.CreateItem(slItem); err != nil {
+			return nil, err
+		}
+		slItem.Ingredient = item.Ingredient
+		responseItems = append(responseItems, shoppingItemResponse(*slItem, prices))
+	}
+
+	token, err := s.ShoppingListRepo.LatestChange(list.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ShoppingListResponse{
 		ID:        list.ID,
 		StartDate: startDateStr,
 		EndDate:   endDateStr,
 		Items:     responseItems,
-	}, nil
+		Token:     token,
+	}
+	resp.Covered = coveredResponse(covered)
+	arrangeByAisle(resp, nil)
+	estimateTotal(resp)
+	return resp, nil
+}
+
+// neededLines sums what the meal plans between the dates call for and, with
+// usePantry, takes off the stock on hand. Lines the pantry fully covers are
+// returned separately.
+func (s *shoppingListService) neededLines(
+	userID uint,
+	startDate time.Time,
+	endDate time.Time,
+	usePantry bool,
+) (toBuy, covered []aggregatedIngredient, err error) {
+
+	mealPlans, err := s.MealPlanRepo.FindByUserAndDateRange(userID, startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	toBuy = aggregateMealPlanIngredients(mealPlans)
+	if usePantry {
+		pantry, err := s.PantryRepo.FindByUser(userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		toBuy, covered = subtractPantry(toBuy, pantry, startDate)
+	}
+	return toBuy, covered, nil
+}
+
+func (s *shoppingListService) Refresh(
+	listID uint,
+	userID uint,
+	usePantry bool,
+) (*dto.ShoppingListResponse, error) {
+
+	list, err := s.ownedList(listID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, covered, err := s.neededLines(userID, list.StartDate, list.EndDate, usePantry)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.ShoppingListRepo.FindItemsByListID(list.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffShoppingList(list.ID, items, lines)
+	now := time.Now()
+	for _, change := range diff.updated {
+		change.item.Touch(now, models.FieldQuantity, models.FieldUnit)
+	}
+	if err := s.ShoppingListRepo.SyncItems(diff.saved(), diff.removedItems()); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.GetShoppingListByID(list.ID, userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	resp.Covered = coveredResponse(covered)
+	changes := diff.summary()
+	resp.Changes = &changes
+	s.publish(realtime.NewEvent(realtime.ListRefreshed, list.ID, 0, changes))
+	return resp, nil
 }
 
 func (s *shoppingListService) GetShoppingListByID(
 	listID uint,
 	userID uint,
+	storeID uint,
 ) (*dto.ShoppingListResponse, error) {
 
 	list, err := s.ShoppingListRepo.FindByID(listID)
@@ -153,34 +302,268 @@ func (s *shoppingListService) GetShoppingListByID(
 		return nil, ErrUnauthorized
 	}
 
+	var store *models.StoreLayout
+	if storeID != 0 {
+		if store, err = s.StoreRepo.FindByID(storeID); err != nil {
+			return nil, err
+		}
+		if store.UserID != userID {
+			return nil, ErrUnauthorized
+		}
+	}
+
 	items, err := s.ShoppingListRepo.FindItemsByListID(listID)
 	if err != nil {
 		return nil, err
 	}
 
-	var responseItems []dto.ShoppingListItemResponse
+	var ids []uint
 	for _, item := range items {
-		itemName := "Unknown"
-		if item.IngredientID != 0 {
-			itemName = item.Ingredient.Name
+		if item.IngredientID != nil {
+			ids = append(ids, *item.IngredientID)
 		}
+	}
+	prices, err := s.PriceRepo.FindLatest(userID, ids)
+	if err != nil {
+		return nil, err
+	}
 
-		responseItems = append(responseItems, dto.ShoppingListItemResponse{
-			ID:           item.ID,
-			IngredientID: item.IngredientID,
-			Name:         itemName,
-			Quantity:     item.Quantity,
-			Unit:         item.Unit,
-			Checked:      item.Checked,
-		})
+	var responseItems []dto.ShoppingListItemResponse
+	for _, item := range items {
+		responseItems = append(responseItems, shoppingItemResponse(item, prices))
+	}
+
+	token, err := s.ShoppingListRepo.LatestChange(list.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	return &dto.ShoppingListResponse{
+	resp := &dto.ShoppingListResponse{
 		ID:        list.ID,
 		StartDate: list.StartDate.Format("2006-01-02"),
 		EndDate:   list.EndDate.Format("2006-01-02"),
 		Items:     responseItems,
-	}, nil
+		Token:     token,
+	}
+	var layout []string
+	if store != nil {
+		resp.Store = store.Name
+		layout = store.Aisles
+	}
+	arrangeByAisle(resp, layout)
+	estimateTotal(resp)
+	return resp, nil
+}
+
+func (s *shoppingListService) ListShoppingLists(
+	userID uint,
+	query dto.ShoppingListQuery,
+) ([]dto.ShoppingListSummary, int64, error) {
+
+	page, limit := query.Page, query.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	lists, total, err := s.ShoppingListRepo.FindByUser(userID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	summaries := make([]dto.ShoppingListSummary, 0, len(lists))
+	for _, list := range lists {
+		summary := dto.ShoppingListSummary{
+			ID:        list.ID,
+			StartDate: list.StartDate.Format("2006-01-02"),
+			EndDate:   list.EndDate.Format("2006-01-02"),
+			Items:     len(list.Items),
+			CreatedAt: list.CreatedAt.Format(time.RFC3339),
+		}
+		for _, item := range list.Items {
+			if item.Checked {
+				summary.Checked++
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, total, nil
+}
+
+func (s *shoppingListService) DeleteShoppingList(listID uint, userID uint) error {
+	list, err := s.ownedList(listID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.ShoppingListRepo.Delete(list); err != nil {
+		return err
+	}
+	s.publish(realtime.NewEvent(realtime.ListDeleted, list.ID, 0, nil))
+	return nil
+}
+
+func (s *shoppingListService) AddItem(
+	listID uint,
+	userID uint,
+	req dto.AddManualItemRequest,
+) (*dto.ShoppingListItemResponse, error) {
+
+	list, err := s.ownedList(listID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.manualItem(list.ID, req.IngredientID, req.Name, req.Quantity, req.Unit, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	item.Touch(time.Now(), models.FieldChecked, models.FieldName, models.FieldQuantity, models.FieldUnit, models.FieldNote)
+
+	if err := s.ShoppingListRepo.CreateItem(item); err != nil {
+		return nil, err
+	}
+	resp, err := s.pricedItem(*item, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(realtime.NewEvent(realtime.ItemAdded, item.ShoppingListID, item.ID, resp))
+	return resp, nil
+}
+
+func (s *shoppingListService) UpdateItem(
+	itemID uint,
+	userID uint,
+	req dto.UpdateShoppingListItemRequest,
+) (*dto.ShoppingListItemResponse, error) {
+
+	item, err := s.ownedItem(itemID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if item.IngredientID != nil {
+			return nil, ErrItemNameFixed
+		}
+		if name == "" {
+			return nil, ErrItemNameRequired
+		}
+		item.Name = name
+		item.Touch(now, models.FieldName)
+	}
+	if req.Quantity != nil {
+		item.Quantity = *req.Quantity
+		item.Touch(now, models.FieldQuantity)
+	}
+	if req.Unit != nil {
+		item.Unit = strings.TrimSpace(*req.Unit)
+		item.Touch(now, models.FieldUnit)
+	}
+	if req.Note != nil {
+		item.Note = strings.TrimSpace(*req.Note)
+		item.Touch(now, models.FieldNote)
+	}
+
+	if err := s.ShoppingListRepo.UpdateItem(item); err != nil {
+		return nil, err
+	}
+	if item.IngredientID != nil {
+		ing, err := s.IngredientRepo.FindByID(*item.IngredientID)
+		if err != nil {
+			return nil, err
+		}
+		item.Ingredient = *ing
+	}
+	resp, err := s.pricedItem(*item, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.publish(realtime.NewEvent(realtime.ItemUpdated, item.ShoppingListID, item.ID, resp))
+	return resp, nil
+}
+
+func (s *shoppingListService) DeleteItem(itemID uint, userID uint) error {
+	item, err := s.ownedItem(itemID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.ShoppingListRepo.DeleteItem(item); err != nil {
+		return err
+	}
+	s.publish(realtime.NewEvent(realtime.ItemRemoved, item.ShoppingListID, item.ID, nil))
+	return nil
+}
+
+// manualItem builds an item added by hand, for an ingredient, which must
+// exist, or as free text.
+func (s *shoppingListService) manualItem(
+	listID uint,
+	ingredientID *uint,
+	name string,
+	quantity float64,
+	unit string,
+	note string,
+) (*models.ShoppingListItem, error) {
+
+	item := &models.ShoppingListItem{
+		ShoppingListID: listID,
+		Name:           strings.TrimSpace(name),
+		Quantity:       quantity,
+		Unit:           strings.TrimSpace(unit),
+		Note:           strings.TrimSpace(note),
+		Manual:         true,
+	}
+	if ingredientID != nil {
+		ing, err := s.IngredientRepo.FindByID(*ingredientID)
+		if err != nil {
+			return nil, err
+		}
+		item.IngredientID = &ing.ID
+		item.Ingredient = *ing
+		item.Name = ""
+	} else if item.Name == "" {
+		return nil, ErrItemNameRequired
+	}
+	return item, nil
+}
+
+func (s *shoppingListService) ownedList(listID uint, userID uint) (*models.ShoppingList, error) {
+	list, err := s.ShoppingListRepo.FindByID(listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+	return list, nil
+}
+
+func (s *shoppingListService) ownedItem(itemID uint, userID uint) (*models.ShoppingListItem, error) {
+	item, err := s.ShoppingListRepo.FindItemByID(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.ownedList(item.ShoppingListID, userID); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// pricedItem describes a single item with the user's latest price for it.
+func (s *shoppingListService) pricedItem(item models.ShoppingListItem, userID uint) (*dto.ShoppingListItemResponse, error) {
+	prices := map[uint]models.IngredientPrice{}
+	if item.IngredientID != nil {
+		var err error
+		if prices, err = s.PriceRepo.FindLatest(userID, []uint{*item.IngredientID}); err != nil {
+			return nil, err
+		}
+	}
+	resp := shoppingItemResponse(item, prices)
+	return &resp, nil
 }
 
 func (s *shoppingListService) ToggleItemChecked(
@@ -203,5 +586,535 @@ func (s *shoppingListService) ToggleItemChecked(
 	}
 
 	item.Checked = !item.Checked
-	return s.ShoppingListRepo.UpdateItem(item)
+	item.Touch(time.Now(), models.FieldChecked)
+	if err := s.ShoppingListRepo.UpdateItem(item); err != nil {
+		return err
+	}
+	s.publish(realtime.NewEvent(realtime.ItemChecked, item.ShoppingListID, item.ID, map[string]bool{"checked": item.Checked}))
+	return nil
+}
+
+func (s *shoppingListService) Subscribe(listID uint, userID uint) (<-chan realtime.Event, func(), error) {
+	list, err := s.ownedList(listID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, stop := s.Hub.Subscribe(list.ID)
+	return events, stop, nil
+}
+
+// publish tells the list's subscribers about a change that has already
+// been saved, so a failure is logged rather than returned.
+func (s *shoppingListService) publish(event realtime.Event) {
+	if err := s.Hub.Publish(event); err != nil {
+		log.Printf("shopping list %d: publishing %s: %v", event.ListID, event.Type, err)
+	}
+}
+
+func (s *shoppingListService) ExportShoppingList(
+	listID uint,
+	userID uint,
+	storeID uint,
+	format string,
+) (*dto.ShoppingListExport, error) {
+
+	f, err := listexport.Lookup(format)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.GetShoppingListByID(listID, userID, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := f.Encoder.Encode(&buf, exportDocument(list)); err != nil {
+		return nil, err
+	}
+
+	return &dto.ShoppingListExport{
+		Filename:    "shopping-list-" + list.StartDate + "." + f.Extension,
+		ContentType: f.ContentType,
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// exportDocument turns a list into its printable form. Lines for the same
+// ingredient in different units become one item with several amounts.
+func exportDocument(list *dto.ShoppingListResponse) *listexport.List {
+	doc := &listexport.List{
+		Title:  "Shopping list",
+		Period: list.StartDate + " to " + list.EndDate,
+		Store:  list.Store,
+	}
+	if list.StartDate == list.EndDate {
+		doc.Period = list.StartDate
+	}
+
+	for _, group := range list.Aisles {
+		section := listexport.Aisle{Name: group.Aisle}
+		index := map[string]int{}
+		for _, item := range group.Items {
+			key := strings.ToLower(item.Name)
+			i, ok := index[key]
+			if !ok {
+				i = len(section.Items)
+				index[key] = i
+				section.Items = append(section.Items, listexport.Item{Name: item.Name, Checked: true})
+			}
+			entry := &section.Items[i]
+			if item.Display != "" {
+				entry.Amounts = append(entry.Amounts, item.Display)
+			}
+			if item.Note != "" {
+				entry.Notes = append(entry.Notes, item.Note)
+			}
+			entry.Checked = entry.Checked && item.Checked
+		}
+		doc.Aisles = append(doc.Aisles, section)
+	}
+	return doc
+}
+
+// itemChange is one line a refresh touched, with its quantity beforehand.
+type itemChange struct {
+	item *models.ShoppingListItem
+	from float64
+}
+
+type shoppingListDiff struct {
+	added, updated, flagged, removed []itemChange
+	unchanged                        int
+}
+
+// diffShoppingList works out how a refresh changes the list's items to
+// match the recomputed lines. Generated items are matched by ingredient and
+// unit, then by ingredient alone. Lines no longer needed are removed, unless
+// already checked off, in which case they are kept and flagged as stale.
+// Manual and free-text items are left alone.
+func diffShoppingList(listID uint, items []models.ShoppingListItem, lines []aggregatedIngredient) shoppingListDiff {
+	var diff shoppingListDiff
+
+	var generated []*models.ShoppingListItem
+	for i := range items {
+		if !items[i].Manual && items[i].IngredientID != nil {
+			generated = append(generated, &items[i])
+		}
+	}
+	matched := make(map[*models.ShoppingListItem]bool)
+	match := func(line aggregatedIngredient, sameUnit bool) *models.ShoppingListItem {
+		for _, item := range generated {
+			if matched[item] || *item.IngredientID != line.IngredientID {
+				continue
+			}
+			if sameUnit && !strings.EqualFold(item.Unit, line.Unit) {
+				continue
+			}
+			matched[item] = true
+			return item
+		}
+		return nil
+	}
+
+	// exact matches first so a unit change elsewhere can't take them
+	found := make([]*models.ShoppingListItem, len(lines))
+	for i, line := range lines {
+		found[i] = match(line, true)
+	}
+	for i, line := range lines {
+		if found[i] == nil {
+			found[i] = match(line, false)
+		}
+	}
+
+	for i, line := range lines {
+		item := found[i]
+		if item == nil {
+			ingredientID := line.IngredientID
+			diff.added = append(diff.added, itemChange{item: &models.ShoppingListItem{
+				ShoppingListID: listID,
+				IngredientID:   &ingredientID,
+				Ingredient:     line.Ingredient,
+				Quantity:       line.Quantity,
+				Needed:         line.Needed,
+				Unit:           line.Unit,
+			}})
+			continue
+		}
+
+		from := item.Quantity
+		if !item.Stale && item.Unit == line.Unit && sameQuantity(item.Quantity, line.Quantity) && sameQuantity(item.Needed, line.Needed) {
+			diff.unchanged++
+			continue
+		}
+		item.Quantity, item.Needed, item.Unit, item.Stale = line.Quantity, line.Needed, line.Unit, false
+		diff.updated = append(diff.updated, itemChange{item: item, from: from})
+	}
+
+	for _, item := range generated {
+		switch {
+		case matched[item]:
+		case !item.Checked:
+			diff.removed = append(diff.removed, itemChange{item: item, from: item.Quantity})
+		case item.Stale:
+			diff.unchanged++
+		default:
+			item.Stale = true
+			diff.flagged = append(diff.flagged, itemChange{item: item, from: item.Quantity})
+		}
+	}
+	return diff
+}
+
+func sameQuantity(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// saved lists the items to create or update.
+func (d shoppingListDiff) saved() []*models.ShoppingListItem {
+	var items []*models.ShoppingListItem
+	for _, group := range [][]itemChange{d.added, d.updated, d.flagged} {
+		for _, change := range group {
+			items = append(items, change.item)
+		}
+	}
+	return items
+}
+
+func (d shoppingListDiff) removedItems() []*models.ShoppingListItem {
+	var items []*models.ShoppingListItem
+	for _, change := range d.removed {
+		items = append(items, change.item)
+	}
+	return items
+}
+
+func (d shoppingListDiff) summary() dto.ShoppingListChanges {
+	describe := func(changes []itemChange, removed bool) []dto.ShoppingListItemChange {
+		out := make([]dto.ShoppingListItemChange, 0, len(changes))
+		for _, change := range changes {
+			to := change.item.Quantity
+			if removed {
+				to = 0
+			}
+			out = append(out, dto.ShoppingListItemChange{
+				ID:   change.item.ID,
+				Name: change.item.ItemName(),
+				Unit: change.item.Unit,
+				From: change.from,
+				To:   to,
+			})
+		}
+		return out
+	}
+	return dto.ShoppingListChanges{
+		Added:     describe(d.added, false),
+		Updated:   describe(d.updated, false),
+		Removed:   describe(d.removed, true),
+		Flagged:   describe(d.flagged, false),
+		Unchanged: d.unchanged,
+	}
+}
+
+func coveredResponse(covered []aggregatedIngredient) []dto.ShoppingListItemResponse {
+	var out []dto.ShoppingListItemResponse
+	for _, v := range covered {
+		ingredientID := v.IngredientID
+		out = append(out, dto.ShoppingListItemResponse{
+			IngredientID: &ingredientID,
+			Name:         v.Name,
+			Unit:         v.Unit,
+			Aisle:        aisle.Of(v.Ingredient.Aisle, v.Name),
+			Needed:       v.Needed,
+		})
+	}
+	return out
+}
+
+// shoppingItemResponse describes a saved list item. Free-text items are
+// never priced.
+func shoppingItemResponse(item models.ShoppingListItem, prices map[uint]models.IngredientPrice) dto.ShoppingListItemResponse {
+	name := item.ItemName()
+	needed := item.Needed
+	if needed == 0 {
+		needed = item.Quantity
+	}
+
+	resp := dto.ShoppingListItemResponse{
+		ID:           item.ID,
+		IngredientID: item.IngredientID,
+		Name:         name,
+		Quantity:     item.Quantity,
+		Unit:         item.Unit,
+		Aisle:        aisle.Of(item.Ingredient.Aisle, name),
+		Needed:       needed,
+		ToBuy:        item.Quantity,
+		Display:      units.FormatQuantity(item.Quantity, item.Unit),
+		Note:         item.Note,
+		Manual:       item.Manual,
+		Checked:      item.Checked,
+		Stale:        item.Stale,
+		Version:      item.Version,
+		ClientID:     item.ClientID,
+	}
+	if item.IngredientID != nil {
+		resp.Cost = itemCost(prices, item.Ingredient, item.Quantity, item.Unit)
+	}
+	return resp
+}
+
+// itemCost prices a list line, or returns nil when it can't be priced.
+func itemCost(prices map[uint]models.IngredientPrice, ing models.Ingredient, quantity float64, unit string) *float64 {
+	item, err := priceIngredient(prices, ing, quantity, unit)
+	if err != nil {
+		return nil
+	}
+	return &item.Cost
+}
+
+// arrangeByAisle sorts the list's items into the layout's aisle order, by
+// name within an aisle, and groups them by aisle. A nil layout walks the
+// aisles in the default order.
+func arrangeByAisle(list *dto.ShoppingListResponse, layout []string) {
+	order := aisle.Order(layout)
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		a, b := list.Items[i], list.Items[j]
+		if order[a.Aisle] != order[b.Aisle] {
+			return order[a.Aisle] < order[b.Aisle]
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	list.Aisles = []dto.ShoppingListAisle{}
+	for _, item := range list.Items {
+		last := len(list.Aisles) - 1
+		if last < 0 || list.Aisles[last].Aisle != item.Aisle {
+			list.Aisles = append(list.Aisles, dto.ShoppingListAisle{Aisle: item.Aisle})
+			last++
+		}
+		list.Aisles[last].Items = append(list.Aisles[last].Items, item)
+	}
+}
+
+func estimateTotal(list *dto.ShoppingListResponse) {
+	var total float64
+	for _, item := range list.Items {
+		if item.Cost == nil {
+			list.UnpricedItems++
+			continue
+		}
+		total += *item.Cost
+	}
+	list.EstimatedTotal = pricing.Round(total)
+}
+
+type aggregatedIngredient struct {
+	IngredientID uint
+	Ingredient   models.Ingredient
+	Name         string
+	Quantity     float64
+	Needed       float64 // Quantity before pantry stock was taken off
+	Unit         string
+}
+
+// aggregateMealPlanIngredients scales every planned recipe to its target
+// servings and sums the ingredients. Quantities in known units are summed per
+// dimension, so "500 g" and "1 kg" of flour become one line; unknown units
+// are only merged when they match after normalisation. When one ingredient
+// appears in several dimensions, volume and count lines are folded into its
+// mass line using the ingredient's density and piece weight, where known.
+func aggregateMealPlanIngredients(mealPlans []models.MealPlan) []aggregatedIngredient {
+	type key struct {
+		IngredientID uint
+		Unit         string
+	}
+	type aggrItem struct {
+		Name      string
+		Quantity  float64
+		Raw       float64 // sum in the original unit, exact when only one unit was used
+		Dimension units.Dimension
+		System    units.System
+		Units     map[string]bool
+	}
+
+	aggregated := make(map[key]*aggrItem)
+	ingredients := make(map[uint]models.Ingredient)
+	var order []key
+
+	for _, mp := range mealPlans {
+
+		baseServings := mp.Recipe.Servings
+		if baseServings == 0 {
+			baseServings = 1
+		}
+
+		ratio := float64(mp.TargetServings) / float64(baseServings)
+
+		for _, item := range mp.Recipe.Ingredients {
+			scaledQuantity := item.Quantity * ratio
+			baseQuantity := scaledQuantity
+
+			k := key{item.IngredientID, units.Normalize(item.Unit)}
+			var dim units.Dimension
+			var system units.System
+
+			if base, u, err := units.ToBase(scaledQuantity, item.Unit); err == nil {
+				k.Unit = string(u.Dimension)
+				baseQuantity = base
+				dim = u.Dimension
+				system = u.System
+			}
+
+			v, ok := aggregated[k]
+			if !ok {
+				v = &aggrItem{
+					Name:      item.Ingredient.Name,
+					Dimension: dim,
+					System:    system,
+					Units:     make(map[string]bool),
+				}
+				aggregated[k] = v
+				order = append(order, k)
+			}
+			v.Quantity += baseQuantity
+			v.Raw += scaledQuantity
+			v.Units[units.Normalize(item.Unit)] = true
+			ing := item.Ingredient
+			ing.ID = item.IngredientID // prices are looked up by the line's ingredient
+			ingredients[item.IngredientID] = ing
+		}
+	}
+
+	seen := make(map[uint]bool)
+	for _, first := range order {
+		id := first.IngredientID
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		ing := ingredients[id]
+		massKey := key{id, string(units.Mass)}
+		factors := map[units.Dimension]*float64{units.Volume: ing.Density, units.Count: ing.PieceWeight}
+
+		var convertible []key
+		for _, dim := range []units.Dimension{units.Volume, units.Count} {
+			factor := factors[dim]
+			if _, ok := aggregated[key{id, string(dim)}]; ok && factor != nil && *factor > 0 {
+				convertible = append(convertible, key{id, string(dim)})
+			}
+		}
+		_, hasMass := aggregated[massKey]
+		if len(convertible) == 0 || (!hasMass && len(convertible) < 2) {
+			continue
+		}
+
+		for _, k := range convertible {
+			v := aggregated[k]
+
+			mass, ok := aggregated[massKey]
+			if !ok {
+				mass = &aggrItem{Name: v.Name, Dimension: units.Mass, System: v.System, Units: make(map[string]bool)}
+				aggregated[massKey] = mass
+				// take over the folded line's position
+				for i := range order {
+					if order[i] == k {
+						order[i] = massKey
+					}
+				}
+			}
+			mass.Quantity += v.Quantity * *factors[v.Dimension]
+			for unit := range v.Units {
+				mass.Units[unit] = true
+			}
+			delete(aggregated, k)
+		}
+	}
+
+	var result []aggregatedIngredient
+	for _, k := range order {
+		v, ok := aggregated[k]
+		if !ok {
+			continue
+		}
+		line := aggregatedIngredient{
+			IngredientID: k.IngredientID,
+			Ingredient:   ingredients[k.IngredientID],
+			Name:         v.Name,
+			Quantity:     v.Quantity,
+			Unit:         k.Unit,
+		}
+
+		if v.Dimension != "" {
+			if len(v.Units) == 1 {
+				// every line used the same unit, so keep it
+				for unit := range v.Units {
+					line.Quantity = v.Raw
+					line.Unit = unit
+				}
+			} else {
+				system := v.System
+				if system == "" {
+					system = units.Metric
+				}
+				line.Quantity, line.Unit = units.Present(v.Quantity, v.Dimension, system)
+			}
+		}
+
+		line.Needed = line.Quantity
+		result = append(result, line)
+	}
+
+	return result
+}
+
+// subtractPantry takes stock on hand off each line, converting the pantry's
+// units into the line's where the ingredient's density and piece weight
+// allow. Stock expiring before day is left out, and each item is used up at
+// most once across lines. It returns the lines still to buy and those the
+// pantry fully covers.
+func subtractPantry(lines []aggregatedIngredient, pantry []models.PantryItem, day time.Time) (toBuy, covered []aggregatedIngredient) {
+	type stock struct {
+		Quantity float64
+		Unit     string
+	}
+	available := make(map[uint][]*stock)
+	for _, item := range pantry {
+		if item.Quantity <= 0 || expiredBy(item, day) {
+			continue
+		}
+		available[item.IngredientID] = append(available[item.IngredientID], &stock{item.Quantity, item.Unit})
+	}
+
+	for _, line := range lines {
+		remaining := line.Quantity
+		density, pieceWeight := valueOrZero(line.Ingredient.Density), valueOrZero(line.Ingredient.PieceWeight)
+
+		for _, st := range available[line.IngredientID] {
+			if remaining <= 0 || st.Quantity <= 0 {
+				continue
+			}
+			have, err := pricing.Convert(st.Quantity, st.Unit, line.Unit, density, pieceWeight)
+			if err != nil || have <= 0 {
+				continue
+			}
+			used := math.Min(have, remaining)
+			remaining -= used
+			st.Quantity -= st.Quantity * used / have
+		}
+
+		// float leftovers from unit conversion
+		if remaining < line.Quantity*1e-9 {
+			remaining = 0
+		}
+		if remaining == 0 && line.Quantity > 0 {
+			covered = append(covered, line)
+			continue
+		}
+		line.Quantity = remaining
+		toBuy = append(toBuy, line)
+	}
+
+	return toBuy, covered
 }
@@ -2,10 +2,18 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/aisle"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/listexport"
 	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/realtime"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
 	"gorm.io/gorm"
 )
 
@@ -53,6 +61,16 @@ type MockShoppingListRepo struct {
 	FindItemsFn  func(uint) ([]models.ShoppingListItem, error)
 	FindItemFn   func(uint) (*models.ShoppingListItem, error)
 	UpdateItemFn func(*models.ShoppingListItem) error
+	DeleteItemFn func(*models.ShoppingListItem) error
+	FindByUserFn func(uint, int, int) ([]models.ShoppingList, int64, error)
+	FindLatestFn func(uint, time.Time, time.Time) (*models.ShoppingList, error)
+	DeleteFn     func(*models.ShoppingList) error
+	SyncItemsFn  func([]*models.ShoppingListItem, []*models.ShoppingListItem) error
+	ByClientIDFn func(uint, string) (*models.ShoppingListItem, error)
+	ChangesFn    func(uint, uint) ([]models.ShoppingListChange, error)
+	LatestChgFn  func(uint) (uint, error)
+	FindSyncOpFn func(uint, string) (*models.ShoppingListSyncOp, error)
+	ApplyOpFn    func(*models.ShoppingListSyncOp, []*models.ShoppingListItem, []*models.ShoppingListItem) error
 }
 
 func (m *MockShoppingListRepo) Create(sl *models.ShoppingList) error {
@@ -92,11 +110,103 @@ func (m *MockShoppingListRepo) UpdateItem(i *models.ShoppingListItem) error {
 	}
 	return nil
 }
+func (m *MockShoppingListRepo) DeleteItem(i *models.ShoppingListItem) error {
+	if m.DeleteItemFn != nil {
+		return m.DeleteItemFn(i)
+	}
+	return nil
+}
+func (m *MockShoppingListRepo) FindByUser(u uint, offset, limit int) ([]models.ShoppingList, int64, error) {
+	if m.FindByUserFn != nil {
+		return m.FindByUserFn(u, offset, limit)
+	}
+	return nil, 0, nil
+}
+func (m *MockShoppingListRepo) FindLatestByRange(u uint, start, end time.Time) (*models.ShoppingList, error) {
+	if m.FindLatestFn != nil {
+		return m.FindLatestFn(u, start, end)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *MockShoppingListRepo) Delete(l *models.ShoppingList) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(l)
+	}
+	return nil
+}
+func (m *MockShoppingListRepo) SyncItems(save, remove []*models.ShoppingListItem) error {
+	if m.SyncItemsFn != nil {
+		return m.SyncItemsFn(save, remove)
+	}
+	return nil
+}
+func (m *MockShoppingListRepo) FindItemByClientID(l uint, clientID string) (*models.ShoppingListItem, error) {
+	if m.ByClientIDFn != nil {
+		return m.ByClientIDFn(l, clientID)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *MockShoppingListRepo) ChangesSince(l uint, token uint) ([]models.ShoppingListChange, error) {
+	if m.ChangesFn != nil {
+		return m.ChangesFn(l, token)
+	}
+	return nil, nil
+}
+func (m *MockShoppingListRepo) LatestChange(l uint) (uint, error) {
+	if m.LatestChgFn != nil {
+		return m.LatestChgFn(l)
+	}
+	return 0, nil
+}
+func (m *MockShoppingListRepo) FindSyncOp(l uint, opID string) (*models.ShoppingListSyncOp, error) {
+	if m.FindSyncOpFn != nil {
+		return m.FindSyncOpFn(l, opID)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *MockShoppingListRepo) ApplySyncOp(op *models.ShoppingListSyncOp, save, remove []*models.ShoppingListItem) error {
+	if m.ApplyOpFn != nil {
+		return m.ApplyOpFn(op, save, remove)
+	}
+	return nil
+}
+
+func idPtr(v uint) *uint { return &v }
+
+type MockPriceRepo struct {
+	FindLatestFn func(uint, []uint) (map[uint]models.IngredientPrice, error)
+}
+
+func (m *MockPriceRepo) Create(*models.IngredientPrice) error { return nil }
+func (m *MockPriceRepo) FindByID(uint) (*models.IngredientPrice, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *MockPriceRepo) FindByUser(uint, uint, string) ([]models.IngredientPrice, error) {
+	return nil, nil
+}
+func (m *MockPriceRepo) FindLatest(u uint, ids []uint) (map[uint]models.IngredientPrice, error) {
+	if m.FindLatestFn != nil {
+		return m.FindLatestFn(u, ids)
+	}
+	return map[uint]models.IngredientPrice{}, nil
+}
+func (m *MockPriceRepo) Delete(*models.IngredientPrice) error { return nil }
 
 // =====================================================
 // TESTS
 // =====================================================
 
+// findItem returns the line for an ingredient in a unit, since lines come
+// back in aisle order rather than the order they were planned in.
+func findItem(items []dto.ShoppingListItemResponse, name, unit string) *dto.ShoppingListItemResponse {
+	for i := range items {
+		if items[i].Name == name && items[i].Unit == unit {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
 func TestGenerateShoppingList(t *testing.T) {
 	t.Run("Success Path with Scaling", func(t *testing.T) {
 		service := NewShoppingListService(
@@ -124,9 +234,14 @@ func TestGenerateShoppingList(t *testing.T) {
 				CreateFn:     func(*models.ShoppingList) error { return nil },
 				CreateItemFn: func(*models.ShoppingListItem) error { return nil },
 			},
+			&MockPriceRepo{},
+			&MockPantryRepo{},
+			&MockStoreRepo{},
+			&MockIngredientRepository{},
+			realtime.NewMemory(),
 		)
 
-		resp, err := service.Generate(1, "2025-01-01", "2025-01-07")
+		resp, err := service.Generate(1, "2025-01-01", "2025-01-07", false)
 		if err != nil {
 			t.Fatalf("Expected nil, got %v", err)
 		}
@@ -138,6 +253,210 @@ func TestGenerateShoppingList(t *testing.T) {
 		}
 	})
 
+	t.Run("Merges Compatible Units", func(t *testing.T) {
+		flour := models.Ingredient{Name: "Flour"}
+		service := NewShoppingListService(
+			&MockMealPlanRepoForShoppingList{
+				FindRangeFn: func(u uint, s, e time.Time) ([]models.MealPlan, error) {
+					return []models.MealPlan{
+						{TargetServings: 1, Recipe: models.Recipe{Servings: 1, Ingredients: []models.RecipeIngredient{
+							{IngredientID: 10, Quantity: 500, Unit: "g", Ingredient: flour},
+							{IngredientID: 11, Quantity: 2, Unit: "Cloves", Ingredient: models.Ingredient{Name: "Garlic"}},
+						}}},
+						{TargetServings: 1, Recipe: models.Recipe{Servings: 1, Ingredients: []models.RecipeIngredient{
+							{IngredientID: 10, Quantity: 1, Unit: "kg", Ingredient: flour},
+							{IngredientID: 10, Quantity: 2, Unit: "cups", Ingredient: flour},
+							{IngredientID: 11, Quantity: 1, Unit: "clove", Ingredient: models.Ingredient{Name: "Garlic"}},
+						}}},
+					}, nil
+				},
+			},
+			&MockRecipeIngredientRepo{},
+			&MockShoppingListRepo{
+				CreateFn:     func(*models.ShoppingList) error { return nil },
+				CreateItemFn: func(*models.ShoppingListItem) error { return nil },
+			},
+			&MockPriceRepo{},
+			&MockPantryRepo{},
+			&MockStoreRepo{},
+			&MockIngredientRepository{},
+			realtime.NewMemory(),
+		)
+
+		resp, err := service.Generate(1, "2025-01-01", "2025-01-07", false)
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+
+		lines := map[string]dto.ShoppingListItemResponse{}
+		for _, item := range resp.Items {
+			lines[item.Name+"/"+item.Unit] = item
+		}
+		if len(resp.Items) != 4 {
+			t.Fatalf("Expected 4 lines, got %+v", resp.Items)
+		}
+		if lines["Flour/kg"].Quantity != 1.5 {
+			t.Errorf("Expected 1.5 kg flour, got %+v", resp.Items)
+		}
+		if lines["Flour/cup"].Quantity != 2 {
+			t.Errorf("Expected volume kept separate as 2 cup, got %+v", resp.Items)
+		}
+		if lines["Garlic/cloves"].Quantity != 2 || lines["Garlic/clove"].Quantity != 1 {
+			t.Errorf("Expected unknown units kept per spelling, got %+v", resp.Items)
+		}
+	})
+
+	t.Run("Merges Mass And Volume With Density", func(t *testing.T) {
+		density, eggWeight := 0.85, 50.0
+		sugar := models.Ingredient{Name: "Sugar", Density: &density}
+		eggs := models.Ingredient{Name: "Egg", PieceWeight: &eggWeight}
+		salt := models.Ingredient{Name: "Salt"}
+
+		service := NewShoppingListService(
+			&MockMealPlanRepoForShoppingList{
+				FindRangeFn: func(u uint, s, e time.Time) ([]models.MealPlan, error) {
+					return []models.MealPlan{{TargetServings: 1, Recipe: models.Recipe{Servings: 1, Ingredients: []models.RecipeIngredient{
+						{IngredientID: 1, Quantity: 1, Unit: "cup", Ingredient: sugar},
+						{IngredientID: 1, Quantity: 200, Unit: "g", Ingredient: sugar},
+						{IngredientID: 2, Quantity: 2, Unit: "pcs", Ingredient: eggs},
+						{IngredientID: 2, Quantity: 100, Unit: "ml", Ingredient: eggs},
+						{IngredientID: 3, Quantity: 1, Unit: "tsp", Ingredient: salt},
+						{IngredientID: 3, Quantity: 10, Unit: "g", Ingredient: salt},
+					}}}}, nil
+				},
+			},
+			&MockRecipeIngredientRepo{},
+			&MockShoppingListRepo{
+				CreateFn:     func(*models.ShoppingList) error { return nil },
+				CreateItemFn: func(*models.ShoppingListItem) error { return nil },
+			},
+			&MockPriceRepo{},
+			&MockPantryRepo{},
+			&MockStoreRepo{},
+			&MockIngredientRepository{},
+			realtime.NewMemory(),
+		)
+
+		resp, err := service.Generate(1, "2025-01-01", "2025-01-07", false)
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if len(resp.Items) != 5 {
+			t.Fatalf("Expected 5 lines, got %+v", resp.Items)
+		}
+
+		// 1 cup sugar = 236.59 ml * 0.85 g/ml = 201.1 g, plus 200 g
+		sugarLine := findItem(resp.Items, "Sugar", "g")
+		if sugarLine == nil || sugarLine.Quantity < 401 || sugarLine.Quantity > 402 {
+			t.Errorf("Expected ~401 g sugar, got %+v", resp.Items)
+		}
+		// eggs have no density, so only the count line is known in grams; both stay apart
+		if findItem(resp.Items, "Egg", "pc") == nil || findItem(resp.Items, "Egg", "ml") == nil {
+			t.Errorf("Expected egg lines kept separate, got %+v", resp.Items)
+		}
+		if findItem(resp.Items, "Salt", "tsp") == nil || findItem(resp.Items, "Salt", "g") == nil {
+			t.Errorf("Expected salt without density kept separate, got %+v", resp.Items)
+		}
+	})
+
+	t.Run("Estimates Cost From Latest Prices", func(t *testing.T) {
+		service := NewShoppingListService(
+			&MockMealPlanRepoForShoppingList{
+				FindRangeFn: func(u uint, s, e time.Time) ([]models.MealPlan, error) {
+					return []models.MealPlan{
+						{TargetServings: 2, Recipe: models.Recipe{Servings: 1, Ingredients: []models.RecipeIngredient{
+							{IngredientID: 10, Quantity: 500, Unit: "g", Ingredient: models.Ingredient{Name: "Flour"}},
+							{IngredientID: 11, Quantity: 2, Unit: "cloves", Ingredient: models.Ingredient{Name: "Garlic"}},
+						}}},
+					}, nil
+				},
+			},
+			&MockRecipeIngredientRepo{},
+			&MockShoppingListRepo{
+				CreateFn:     func(*models.ShoppingList) error { return nil },
+				CreateItemFn: func(*models.ShoppingListItem) error { return nil },
+			},
+			&MockPriceRepo{FindLatestFn: func(u uint, ids []uint) (map[uint]models.IngredientPrice, error) {
+				return map[uint]models.IngredientPrice{
+					10: {IngredientID: 10, Price: 2.49, Quantity: 1, Unit: "kg"},
+					11: {IngredientID: 11, Price: 0.8, Quantity: 1, Unit: "bulb"},
+				}, nil
+			}},
+			&MockPantryRepo{},
+			&MockStoreRepo{},
+			&MockIngredientRepository{},
+			realtime.NewMemory(),
+		)
+
+		resp, err := service.Generate(1, "2025-01-01", "2025-01-07", false)
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if flour := findItem(resp.Items, "Flour", "g"); flour == nil || flour.Cost == nil || *flour.Cost != 2.49 {
+			t.Errorf("Expected flour to cost 2.49, got %+v", flour)
+		}
+		// cloves can't be priced per bulb
+		if garlic := findItem(resp.Items, "Garlic", "cloves"); garlic == nil || garlic.Cost != nil || resp.UnpricedItems != 1 || resp.EstimatedTotal != 2.49 {
+			t.Errorf("Unexpected estimate %+v", resp)
+		}
+	})
+
+	t.Run("Subtracts Pantry Stock", func(t *testing.T) {
+		expired := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+		flour := models.Ingredient{ID: 10, Name: "Flour", Density: ptr(0.53)}
+		var saved []*models.ShoppingListItem
+		service := NewShoppingListService(
+			&MockMealPlanRepoForShoppingList{
+				FindRangeFn: func(u uint, s, e time.Time) ([]models.MealPlan, error) {
+					return []models.MealPlan{
+						{TargetServings: 1, Recipe: models.Recipe{Servings: 1, Ingredients: []models.RecipeIngredient{
+							{IngredientID: 10, Quantity: 1, Unit: "kg", Ingredient: flour},
+							{IngredientID: 11, Quantity: 6, Unit: "", Ingredient: models.Ingredient{ID: 11, Name: "Egg"}},
+							{IngredientID: 12, Quantity: 2, Unit: "cloves", Ingredient: models.Ingredient{ID: 12, Name: "Garlic"}},
+						}}},
+					}, nil
+				},
+			},
+			&MockRecipeIngredientRepo{},
+			&MockShoppingListRepo{
+				CreateFn: func(*models.ShoppingList) error { return nil },
+				CreateItemFn: func(i *models.ShoppingListItem) error {
+					saved = append(saved, i)
+					return nil
+				},
+			},
+			&MockPriceRepo{},
+			&MockPantryRepo{FindByUserFn: func(uint) ([]models.PantryItem, error) {
+				return []models.PantryItem{
+					{IngredientID: 10, Quantity: 2, Unit: "cups"}, // 250.8 g
+					{IngredientID: 11, Quantity: 1, Unit: "dozen"},
+					{IngredientID: 12, Quantity: 10, Unit: "cloves", ExpiresAt: &expired},
+				}, nil
+			}},
+			&MockStoreRepo{},
+			&MockIngredientRepository{},
+			realtime.NewMemory(),
+		)
+
+		resp, err := service.Generate(1, "2025-01-01", "2025-01-07", true)
+		if err != nil {
+			t.Fatalf("Expected nil, got %v", err)
+		}
+		if len(resp.Items) != 2 || len(saved) != 2 {
+			t.Fatalf("Expected flour and garlic to buy, got %+v", resp.Items)
+		}
+		if item := findItem(resp.Items, "Flour", "kg"); item == nil || item.Needed != 1 || item.ToBuy < 0.749 || item.ToBuy > 0.75 || saved[0].Needed != 1 {
+			t.Errorf("Unexpected flour line %+v", item)
+		}
+		// expired stock doesn't count
+		if item := findItem(resp.Items, "Garlic", "cloves"); item == nil || item.Needed != 2 || item.ToBuy != 2 {
+			t.Errorf("Unexpected garlic line %+v", item)
+		}
+		if len(resp.Covered) != 1 || resp.Covered[0].Name != "Egg" || resp.Covered[0].Needed != 6 {
+			t.Errorf("Expected eggs to be covered, got %+v", resp.Covered)
+		}
+	})
+
 	t.Run("MealPlan Repo Error", func(t *testing.T) {
 		service := NewShoppingListService(
 			&MockMealPlanRepoForShoppingList{
@@ -147,8 +466,13 @@ func TestGenerateShoppingList(t *testing.T) {
 			},
 			&MockRecipeIngredientRepo{},
 			&MockShoppingListRepo{},
+			&MockPriceRepo{},
+			&MockPantryRepo{},
+			&MockStoreRepo{},
+			&MockIngredientRepository{},
+			realtime.NewMemory(),
 		)
-		_, err := service.Generate(1, "2025-01-01", "2025-01-07")
+		_, err := service.Generate(1, "2025-01-01", "2025-01-07", false)
 		if err == nil || err.Error() != "db error" {
 			t.Errorf("Expected db error, got %v", err)
 		}
@@ -165,8 +489,13 @@ func TestGenerateShoppingList(t *testing.T) {
 			&MockShoppingListRepo{
 				CreateFn: func(*models.ShoppingList) error { return errors.New("header fail") },
 			},
+			&MockPriceRepo{},
+			&MockPantryRepo{},
+			&MockStoreRepo{},
+			&MockIngredientRepository{},
+			realtime.NewMemory(),
 		)
-		_, err := service.Generate(1, "2025-01-01", "2025-01-07")
+		_, err := service.Generate(1, "2025-01-01", "2025-01-07", false)
 		if err == nil || err.Error() != "header fail" {
 			t.Errorf("Expected header fail, got %v", err)
 		}
@@ -191,20 +520,25 @@ func TestGenerateShoppingList(t *testing.T) {
 				CreateFn:     func(*models.ShoppingList) error { return nil },
 				CreateItemFn: func(*models.ShoppingListItem) error { return errors.New("item fail") },
 			},
+			&MockPriceRepo{},
+			&MockPantryRepo{},
+			&MockStoreRepo{},
+			&MockIngredientRepository{},
+			realtime.NewMemory(),
 		)
-		_, err := service.Generate(1, "2025-01-01", "2025-01-07")
+		_, err := service.Generate(1, "2025-01-01", "2025-01-07", false)
 		if err == nil || err.Error() != "item fail" {
 			t.Errorf("Expected item fail, got %v", err)
 		}
 	})
 
 	t.Run("Date Errors", func(t *testing.T) {
-		service := NewShoppingListService(&MockMealPlanRepoForShoppingList{}, &MockRecipeIngredientRepo{}, &MockShoppingListRepo{})
-		_, err := service.Generate(1, "invalid", "2025-01-01")
+		service := NewShoppingListService(&MockMealPlanRepoForShoppingList{}, &MockRecipeIngredientRepo{}, &MockShoppingListRepo{}, &MockPriceRepo{}, &MockPantryRepo{}, &MockStoreRepo{}, &MockIngredientRepository{}, realtime.NewMemory())
+		_, err := service.Generate(1, "invalid", "2025-01-01", false)
 		if err == nil {
 			t.Error("Expected parsing error")
 		}
-		_, err = service.Generate(1, "2025-01-10", "2025-01-01")
+		_, err = service.Generate(1, "2025-01-10", "2025-01-01", false)
 		if err != ErrInvalidDateRange {
 			t.Error("Expected ErrInvalidDateRange")
 		}
@@ -218,22 +552,69 @@ func TestGetShoppingListByID(t *testing.T) {
 				return &models.ShoppingList{UserID: 1, StartDate: time.Now(), EndDate: time.Now()}, nil
 			},
 			FindItemsFn: func(uint) ([]models.ShoppingListItem, error) {
-				return []models.ShoppingListItem{{IngredientID: 1, Ingredient: models.Ingredient{Name: "Salt"}, Quantity: 5}}, nil
+				return []models.ShoppingListItem{{IngredientID: idPtr(1), Ingredient: models.Ingredient{Name: "Salt"}, Quantity: 5}}, nil
 			},
-		})
-		resp, err := service.GetShoppingListByID(1, 1)
+		}, &MockPriceRepo{}, &MockPantryRepo{}, &MockStoreRepo{}, &MockIngredientRepository{}, realtime.NewMemory())
+		resp, err := service.GetShoppingListByID(1, 1, 0)
 		if err != nil || len(resp.Items) == 0 {
 			t.Fatal("Failed to fetch list")
 		}
 	})
 
+	t.Run("Groups Items By Store Aisles", func(t *testing.T) {
+		stores := &MockStoreRepo{FindByIDFn: func(id uint) (*models.StoreLayout, error) {
+			return &models.StoreLayout{ID: id, UserID: 1, Name: "Corner shop", Aisles: []string{aisle.Dairy, aisle.Bakery}}, nil
+		}}
+		service := NewShoppingListService(nil, &MockRecipeIngredientRepo{}, &MockShoppingListRepo{
+			FindByIDFn: func(uint) (*models.ShoppingList, error) {
+				return &models.ShoppingList{UserID: 1, StartDate: time.Now(), EndDate: time.Now()}, nil
+			},
+			FindItemsFn: func(uint) ([]models.ShoppingListItem, error) {
+				return []models.ShoppingListItem{
+					{ID: 1, IngredientID: idPtr(1), Ingredient: models.Ingredient{Name: "Tomato"}},
+					{ID: 2, IngredientID: idPtr(2), Ingredient: models.Ingredient{Name: "Saffron"}},
+					{ID: 3, IngredientID: idPtr(3), Ingredient: models.Ingredient{Name: "Milk"}},
+					{ID: 4, IngredientID: idPtr(4), Ingredient: models.Ingredient{Name: "Sourdough", Aisle: aisle.Bakery}},
+					{ID: 5, IngredientID: idPtr(5), Ingredient: models.Ingredient{Name: "Butter"}},
+					{ID: 6, IngredientID: idPtr(6), Ingredient: models.Ingredient{Name: "Basil"}},
+				}, nil
+			},
+		}, &MockPriceRepo{}, &MockPantryRepo{}, stores, &MockIngredientRepository{}, realtime.NewMemory())
+
+		resp, err := service.GetShoppingListByID(1, 1, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, item := range resp.Items {
+			names = append(names, item.Name)
+		}
+		if got := strings.Join(names, ","); got != "Butter,Milk,Sourdough,Basil,Tomato,Saffron" {
+			t.Errorf("items in order %s", got)
+		}
+		var groups []string
+		for _, g := range resp.Aisles {
+			groups = append(groups, fmt.Sprintf("%s:%d", g.Aisle, len(g.Items)))
+		}
+		if got := strings.Join(groups, ","); got != "dairy:2,bakery:1,produce:2,other:1" || resp.Store != "Corner shop" {
+			t.Errorf("unexpected grouping %s for %q", got, resp.Store)
+		}
+
+		stores.FindByIDFn = func(id uint) (*models.StoreLayout, error) {
+			return &models.StoreLayout{ID: id, UserID: 2}, nil
+		}
+		if _, err := service.GetShoppingListByID(1, 1, 3); err != ErrUnauthorized {
+			t.Errorf("expected ErrUnauthorized for another user's store, got %v", err)
+		}
+	})
+
 	t.Run("Unauthorized", func(t *testing.T) {
 		service := NewShoppingListService(nil, &MockRecipeIngredientRepo{}, &MockShoppingListRepo{
 			FindByIDFn: func(uint) (*models.ShoppingList, error) {
 				return &models.ShoppingList{UserID: 99}, nil
 			},
-		})
-		_, err := service.GetShoppingListByID(1, 1)
+		}, &MockPriceRepo{}, &MockPantryRepo{}, &MockStoreRepo{}, &MockIngredientRepository{}, realtime.NewMemory())
+		_, err := service.GetShoppingListByID(1, 1, 0)
 		if err != ErrUnauthorized {
 			t.Error("Expected unauthorized error")
 		}
@@ -245,9 +626,353 @@ func TestToggleItemChecked(t *testing.T) {
 		FindItemFn:   func(uint) (*models.ShoppingListItem, error) { return &models.ShoppingListItem{ShoppingListID: 1}, nil },
 		FindByIDFn:   func(uint) (*models.ShoppingList, error) { return &models.ShoppingList{UserID: 1}, nil },
 		UpdateItemFn: func(*models.ShoppingListItem) error { return nil },
-	})
+	}, &MockPriceRepo{}, &MockPantryRepo{}, &MockStoreRepo{}, &MockIngredientRepository{}, realtime.NewMemory())
 	err := service.ToggleItemChecked(1, 1)
 	if err != nil {
 		t.Errorf("Expected nil, got %v", err)
 	}
 }
+
+func TestShoppingListManualItems(t *testing.T) {
+	db := setupTestDB()
+	service := NewShoppingListService(
+		repository.NewMealPlanRepository(db),
+		repository.NewRecipeIngredientRepository(db),
+		repository.NewShoppingListRepository(db),
+		repository.NewPriceRepository(db),
+		repository.NewPantryRepository(db),
+		repository.NewStoreLayoutRepository(db),
+		repository.NewIngredientRepository(db),
+		realtime.NewMemory(),
+	)
+	oil := models.Ingredient{Name: "Olive oil"}
+	db.Create(&oil)
+
+	list, err := service.Generate(1, "2026-05-04", "2026-05-10", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Adds Free Text And Ingredient Items", func(t *testing.T) {
+		paper, err := service.AddItem(list.ID, 1, dto.AddManualItemRequest{Name: " Kitchen roll ", Quantity: 2, Note: "the big pack"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if paper.IngredientID != nil || paper.Name != "Kitchen roll" || !paper.Manual || paper.Cost != nil {
+			t.Errorf("unexpected free-text item %+v", paper)
+		}
+
+		bottle, err := service.AddItem(list.ID, 1, dto.AddManualItemRequest{IngredientID: &oil.ID, Quantity: 1, Unit: "bottle"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bottle.IngredientID == nil || *bottle.IngredientID != oil.ID || bottle.Name != "Olive oil" {
+			t.Errorf("unexpected ingredient item %+v", bottle)
+		}
+
+		if _, err := service.AddItem(list.ID, 1, dto.AddManualItemRequest{IngredientID: idPtr(999)}); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected not found for a missing ingredient, got %v", err)
+		}
+		if _, err := service.AddItem(list.ID, 1, dto.AddManualItemRequest{Name: "   "}); err != ErrItemNameRequired {
+			t.Errorf("expected ErrItemNameRequired for a blank name, got %v", err)
+		}
+		if _, err := service.AddItem(list.ID, 2, dto.AddManualItemRequest{Name: "Foil"}); err != ErrUnauthorized {
+			t.Errorf("expected ErrUnauthorized, got %v", err)
+		}
+	})
+
+	t.Run("Edits Items", func(t *testing.T) {
+		got, _ := service.GetShoppingListByID(list.ID, 1, 0)
+		paper := findItem(got.Items, "Kitchen roll", "")
+		bottle := findItem(got.Items, "Olive oil", "bottle")
+		if paper == nil || bottle == nil {
+			t.Fatalf("manual items missing from %+v", got.Items)
+		}
+
+		quantity, name, note := 3.0, "Paper towels", ""
+		edited, err := service.UpdateItem(paper.ID, 1, dto.UpdateShoppingListItemRequest{Name: &name, Quantity: &quantity, Note: &note})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if edited.Name != "Paper towels" || edited.Quantity != 3 || edited.Note != "" {
+			t.Errorf("edit not applied: %+v", edited)
+		}
+
+		if _, err := service.UpdateItem(bottle.ID, 1, dto.UpdateShoppingListItemRequest{Name: &name}); err != ErrItemNameFixed {
+			t.Errorf("expected ErrItemNameFixed renaming an ingredient item, got %v", err)
+		}
+		blank := " "
+		if _, err := service.UpdateItem(paper.ID, 1, dto.UpdateShoppingListItemRequest{Name: &blank}); err != ErrItemNameRequired {
+			t.Errorf("expected ErrItemNameRequired for a blank name, got %v", err)
+		}
+		if _, err := service.UpdateItem(paper.ID, 2, dto.UpdateShoppingListItemRequest{Quantity: &quantity}); err != ErrUnauthorized {
+			t.Errorf("expected ErrUnauthorized, got %v", err)
+		}
+	})
+
+	t.Run("Keeps Manual Items On Regenerate", func(t *testing.T) {
+		again, err := service.Generate(1, "2026-05-04", "2026-05-10", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.ID == list.ID || len(again.Items) != 2 {
+			t.Fatalf("expected both manual items on the new list, got %+v", again.Items)
+		}
+		if item := findItem(again.Items, "Paper towels", ""); item == nil || item.Quantity != 3 || !item.Manual {
+			t.Errorf("edited item not carried over: %+v", item)
+		}
+
+		other, _ := service.Generate(1, "2026-05-11", "2026-05-17", false)
+		if len(other.Items) != 0 {
+			t.Errorf("manual items leaked into another week: %+v", other.Items)
+		}
+	})
+
+	t.Run("Pages Through Lists", func(t *testing.T) {
+		lists, total, err := service.ListShoppingLists(1, dto.ShoppingListQuery{Page: 1, Limit: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 3 || len(lists) != 2 || lists[0].StartDate != "2026-05-11" {
+			t.Errorf("unexpected first page %+v of %d", lists, total)
+		}
+		lists, _, _ = service.ListShoppingLists(1, dto.ShoppingListQuery{Page: 2, Limit: 2})
+		if len(lists) != 1 || lists[0].ID != list.ID || lists[0].Items != 2 {
+			t.Errorf("unexpected second page %+v", lists)
+		}
+	})
+
+	t.Run("Deletes Items And Lists", func(t *testing.T) {
+		got, _ := service.GetShoppingListByID(list.ID, 1, 0)
+		if err := service.DeleteItem(got.Items[0].ID, 2); err != ErrUnauthorized {
+			t.Errorf("expected ErrUnauthorized, got %v", err)
+		}
+		if err := service.DeleteItem(got.Items[0].ID, 1); err != nil {
+			t.Fatal(err)
+		}
+		got, _ = service.GetShoppingListByID(list.ID, 1, 0)
+		if len(got.Items) != 1 {
+			t.Errorf("expected one item left, got %+v", got.Items)
+		}
+
+		if err := service.DeleteShoppingList(list.ID, 2); err != ErrUnauthorized {
+			t.Errorf("expected ErrUnauthorized, got %v", err)
+		}
+		if err := service.DeleteShoppingList(list.ID, 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.GetShoppingListByID(list.ID, 1, 0); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected the list to be gone, got %v", err)
+		}
+		var left int64
+		db.Model(&models.ShoppingListItem{}).Where("shopping_list_id = ?", list.ID).Count(&left)
+		if left != 0 {
+			t.Errorf("%d items left behind", left)
+		}
+	})
+}
+
+func TestRefreshShoppingList(t *testing.T) {
+	db := setupTestDB()
+	service := NewShoppingListService(
+		repository.NewMealPlanRepository(db),
+		repository.NewRecipeIngredientRepository(db),
+		repository.NewShoppingListRepository(db),
+		repository.NewPriceRepository(db),
+		repository.NewPantryRepository(db),
+		repository.NewStoreLayoutRepository(db),
+		repository.NewIngredientRepository(db),
+		realtime.NewMemory(),
+	)
+
+	ids := map[string]uint{}
+	for _, name := range []string{"Rice", "Chicken", "Basil", "Parsley", "Lemon"} {
+		ing := models.Ingredient{Name: name}
+		db.Create(&ing)
+		ids[name] = ing.ID
+	}
+	recipe := func(name string, quantities map[string]float64) uint {
+		r := models.Recipe{UserID: 1, Name: name, Servings: 2, Category: "Dinner"}
+		db.Create(&r)
+		for ing, q := range quantities {
+			db.Create(&models.RecipeIngredient{RecipeID: r.ID, IngredientID: ids[ing], Quantity: q, Unit: "g"})
+		}
+		return r.ID
+	}
+	day := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	dinner := models.MealPlan{UserID: 1, RecipeID: recipe("Chicken rice", map[string]float64{"Rice": 200, "Chicken": 300}), Date: day, MealType: "dinner", TargetServings: 2}
+	lunch := models.MealPlan{UserID: 1, RecipeID: recipe("Herb salad", map[string]float64{"Basil": 20, "Parsley": 30}), Date: day, MealType: "lunch", TargetServings: 2}
+	db.Create(&dinner)
+	db.Create(&lunch)
+
+	list, err := service.Generate(1, "2026-06-01", "2026-06-07", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Chicken", "Basil"} {
+		if err := service.ToggleItemChecked(findItem(list.Items, name, "g").ID, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := service.AddItem(list.ID, 1, dto.AddManualItemRequest{Name: "Foil"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// double the dinner, swap the salad for lemons
+	db.Model(&dinner).Update("target_servings", 4)
+	db.Delete(&lunch)
+	db.Create(&models.MealPlan{UserID: 1, RecipeID: recipe("Lemonade", map[string]float64{"Lemon": 150}), Date: day.AddDate(0, 0, 2), MealType: "snack", TargetServings: 2})
+
+	refreshed, err := service.Refresh(list.ID, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := func(changes []dto.ShoppingListItemChange) string {
+		var out []string
+		for _, c := range changes {
+			out = append(out, fmt.Sprintf("%s %g->%g", c.Name, c.From, c.To))
+		}
+		sort.Strings(out)
+		return strings.Join(out, ",")
+	}
+	summary := refreshed.Changes
+	if got := changed(summary.Added); got != "Lemon 0->150" {
+		t.Errorf("added %s", got)
+	}
+	if got := changed(summary.Updated); got != "Chicken 300->600,Rice 200->400" {
+		t.Errorf("updated %s", got)
+	}
+	if got := changed(summary.Removed); got != "Parsley 30->0" {
+		t.Errorf("removed %s", got)
+	}
+	if got := changed(summary.Flagged); got != "Basil 20->20" {
+		t.Errorf("flagged %s", got)
+	}
+
+	if refreshed.ID != list.ID || len(refreshed.Items) != 5 {
+		t.Fatalf("expected the same list with 5 items, got %d: %+v", refreshed.ID, refreshed.Items)
+	}
+	if item := findItem(refreshed.Items, "Chicken", "g"); item == nil || !item.Checked || item.Quantity != 600 {
+		t.Errorf("chicken lost its checkmark or quantity: %+v", item)
+	}
+	if item := findItem(refreshed.Items, "Basil", "g"); item == nil || !item.Checked || !item.Stale {
+		t.Errorf("basil should be kept as stale: %+v", item)
+	}
+	if item := findItem(refreshed.Items, "Foil", ""); item == nil || !item.Manual {
+		t.Errorf("manual item dropped: %+v", refreshed.Items)
+	}
+
+	again, err := service.Refresh(list.ID, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := again.Changes; len(c.Added)+len(c.Updated)+len(c.Removed)+len(c.Flagged) != 0 || c.Unchanged != 4 {
+		t.Errorf("second refresh should change nothing: %+v", c)
+	}
+
+	if _, err := service.Refresh(list.ID, 2, false); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestExportShoppingList(t *testing.T) {
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	flour := models.Ingredient{ID: 1, Name: "Flour"}
+	service := NewShoppingListService(nil, &MockRecipeIngredientRepo{}, &MockShoppingListRepo{
+		FindByIDFn: func(uint) (*models.ShoppingList, error) {
+			return &models.ShoppingList{ID: 1, UserID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 6)}, nil
+		},
+		FindItemsFn: func(uint) ([]models.ShoppingListItem, error) {
+			return []models.ShoppingListItem{
+				{ID: 1, IngredientID: idPtr(1), Ingredient: flour, Quantity: 500, Unit: "g", Checked: true},
+				{ID: 2, IngredientID: idPtr(1), Ingredient: flour, Quantity: 1.4999999, Unit: "cup"},
+				{ID: 3, IngredientID: idPtr(2), Ingredient: models.Ingredient{ID: 2, Name: "Tomato"}, Quantity: 3, Unit: "pcs", Checked: true},
+				{ID: 4, Name: "Paper towels", Quantity: 1, Note: "recycled", Manual: true},
+			}, nil
+		},
+	}, &MockPriceRepo{}, &MockPantryRepo{}, &MockStoreRepo{}, &MockIngredientRepository{}, realtime.NewMemory())
+
+	export, err := service.ExportShoppingList(1, 1, 0, "md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export.Filename != "shopping-list-2026-06-01.md" || !strings.HasPrefix(export.ContentType, "text/markdown") {
+		t.Errorf("unexpected export %s (%s)", export.Filename, export.ContentType)
+	}
+	doc := string(export.Data)
+	for _, line := range []string{
+		"2026-06-01 to 2026-06-07",
+		"## Produce\n\n- [x] **Tomato** — 3 pcs\n",
+		"- [ ] **Flour** — 500 g + 1 ½ cups\n",
+		"- [ ] **Paper towels** — 1 _(recycled)_\n",
+	} {
+		if !strings.Contains(doc, line) {
+			t.Errorf("missing %q in:\n%s", line, doc)
+		}
+	}
+
+	if _, err := service.ExportShoppingList(1, 1, 0, "docx"); !errors.Is(err, listexport.ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+	if _, err := service.ExportShoppingList(1, 2, 0, "pdf"); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestShoppingListEvents(t *testing.T) {
+	hub := realtime.NewMemory()
+	item := &models.ShoppingListItem{ID: 5, ShoppingListID: 1, Name: "Foil"}
+	service := NewShoppingListService(nil, &MockRecipeIngredientRepo{}, &MockShoppingListRepo{
+		FindByIDFn: func(id uint) (*models.ShoppingList, error) { return &models.ShoppingList{ID: id, UserID: 1}, nil },
+		FindItemFn: func(uint) (*models.ShoppingListItem, error) { return item, nil },
+	}, &MockPriceRepo{}, &MockPantryRepo{}, &MockStoreRepo{}, &MockIngredientRepository{}, hub)
+
+	if _, _, err := service.Subscribe(1, 2); err != ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+	events, stop, err := service.Subscribe(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	next := func() realtime.Event {
+		t.Helper()
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("no event")
+			return realtime.Event{}
+		}
+	}
+
+	if err := service.ToggleItemChecked(5, 1); err != nil {
+		t.Fatal(err)
+	}
+	if event := next(); event.Type != realtime.ItemChecked || event.ItemID != 5 || string(event.Data) != `{"checked":true}` {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	if _, err := service.AddItem(1, 1, dto.AddManualItemRequest{Name: "Napkins"}); err != nil {
+		t.Fatal(err)
+	}
+	if event := next(); event.Type != realtime.ItemAdded || !strings.Contains(string(event.Data), `"name":"Napkins"`) {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	if err := service.DeleteItem(5, 1); err != nil {
+		t.Fatal(err)
+	}
+	if event := next(); event.Type != realtime.ItemRemoved || event.ItemID != 5 {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	// nothing is published when the caller doesn't own the list
+	if err := service.ToggleItemChecked(5, 2); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("unexpected event %+v", <-events)
+	}
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/realtime"
+	"gorm.io/gorm"
+)
+
+// Sync operation outcomes.
+const (
+	SyncApplied   = "applied"   // the change was made, perhaps without some fields (see ignored)
+	SyncDuplicate = "duplicate" // the op_id, or the client_item_id of an add, was seen before
+	SyncConflict  = "conflict"  // every field lost to a newer write
+	SyncDeleted   = "deleted"   // the item has been deleted
+	SyncRejected  = "rejected"  // the operation is invalid
+)
+
+var errSyncNoItem = errors.New("name the item with item_id or client_item_id")
+var errSyncOtherList = errors.New("item is on another list")
+
+// Sync applies the operations in order and then reports what changed since
+// req.Since. Conflicts are settled by these rules:
+//
+//   - An op_id is applied once: its change and its receipt are written
+//     together. Sending it again returns "duplicate", as does an add whose
+//     client_item_id is already on the list.
+//   - Each field (checked, name, quantity, unit, note) keeps the write with
+//     the latest time. An operation's field is ignored when the field was
+//     written at or after the operation's time, so replays change nothing.
+//     Edits made through the other endpoints count as written at server time.
+//   - Client times ahead of the server's clock are taken as now, so a phone
+//     with a fast clock can't win every later conflict.
+//   - Delete beats edit: a delete always applies, and operations on an item
+//     that has been deleted return "deleted" whatever their time.
+//
+// A since of 0, or a token the server never issued, returns the whole list
+// with full set.
+func (s *shoppingListService) Sync(
+	listID uint,
+	userID uint,
+	req dto.SyncShoppingListRequest,
+) (*dto.SyncShoppingListResponse, error) {
+
+	list, err := s.ownedList(listID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.SyncShoppingListResponse{
+		Results: []dto.SyncOperationResult{},
+		Changed: []dto.ShoppingListItemResponse{},
+		Deleted: []uint{},
+	}
+	for _, op := range req.Operations {
+		result, err := s.syncOperation(list, op)
+		if err != nil {
+			return nil, err
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	token, err := s.ShoppingListRepo.LatestChange(list.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Token = token
+	resp.Full = req.Since == 0 || req.Since > token
+
+	items, err := s.ShoppingListRepo.FindItemsByListID(list.ID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	for _, item := range items {
+		if item.IngredientID != nil {
+			ids = append(ids, *item.IngredientID)
+		}
+	}
+	prices, err := s.PriceRepo.FindLatest(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Full {
+		for _, item := range items {
+			resp.Changed = append(resp.Changed, shoppingItemResponse(item, prices))
+		}
+		return resp, nil
+	}
+
+	changes, err := s.ShoppingListRepo.ChangesSince(list.ID, req.Since)
+	if err != nil {
+		return nil, err
+	}
+	touched := map[uint]bool{}
+	for _, change := range changes {
+		touched[change.ItemID] = true
+	}
+	for _, item := range items {
+		if touched[item.ID] {
+			resp.Changed = append(resp.Changed, shoppingItemResponse(item, prices))
+			delete(touched, item.ID)
+		}
+	}
+	// whatever was touched and is no longer on the list was deleted
+	for _, change := range changes {
+		if touched[change.ItemID] {
+			resp.Deleted = append(resp.Deleted, change.ItemID)
+			delete(touched, change.ItemID)
+		}
+	}
+	return resp, nil
+}
+
+// syncWrite is the change an operation makes to an item, written together
+// with the operation's receipt.
+type syncWrite struct {
+	item    *models.ShoppingListItem
+	deleted bool
+	event   string
+}
+
+// syncOperation applies one operation unless its op_id was seen before,
+// writing its change and its receipt in one transaction.
+func (s *shoppingListService) syncOperation(list *models.ShoppingList, op dto.SyncOperation) (dto.SyncOperationResult, error) {
+	receipt, err := s.ShoppingListRepo.FindSyncOp(list.ID, op.OpID)
+	if err == nil {
+		return dto.SyncOperationResult{OpID: op.OpID, Status: SyncDuplicate, ItemID: receipt.ItemID}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.SyncOperationResult{}, err
+	}
+
+	at := op.At
+	if now := time.Now(); at.After(now) {
+		at = now
+	}
+
+	var result dto.SyncOperationResult
+	var write syncWrite
+	if op.Type == "add" {
+		result, write, err = s.syncAdd(list, op, at)
+	} else {
+		result, write, err = s.syncChange(list, op, at)
+	}
+	if err != nil {
+		return dto.SyncOperationResult{}, err
+	}
+	result.OpID = op.OpID
+
+	var save, remove []*models.ShoppingListItem
+	switch {
+	case write.item == nil:
+	case write.deleted:
+		remove = append(remove, write.item)
+	default:
+		save = append(save, write.item)
+	}
+	receipt = &models.ShoppingListSyncOp{
+		ShoppingListID: list.ID,
+		OpID:           op.OpID,
+		ItemID:         result.ItemID,
+		Status:         result.Status,
+	}
+	err = s.ShoppingListRepo.ApplySyncOp(receipt, save, remove)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// another request applied the operation first
+		receipt, err = s.ShoppingListRepo.FindSyncOp(list.ID, op.OpID)
+		if err != nil {
+			return dto.SyncOperationResult{}, err
+		}
+		return dto.SyncOperationResult{OpID: op.OpID, Status: SyncDuplicate, ItemID: receipt.ItemID}, nil
+	}
+	if err != nil {
+		return dto.SyncOperationResult{}, err
+	}
+	result.ItemID = receipt.ItemID
+
+	switch write.event {
+	case realtime.ItemChecked:
+		s.publish(realtime.NewEvent(write.event, list.ID, write.item.ID, map[string]bool{"checked": write.item.Checked}))
+	case realtime.ItemRemoved:
+		s.publish(realtime.NewEvent(write.event, list.ID, write.item.ID, nil))
+	case realtime.ItemAdded, realtime.ItemUpdated:
+		s.publish(realtime.NewEvent(write.event, list.ID, write.item.ID, shoppingItemResponse(*write.item, nil)))
+	}
+	return result, nil
+}
+
+func (s *shoppingListService) syncAdd(list *models.ShoppingList, op dto.SyncOperation, at time.Time) (dto.SyncOperationResult, syncWrite, error) {
+	if op.ClientItemID != "" {
+		existing, err := s.ShoppingListRepo.FindItemByClientID(list.ID, op.ClientItemID)
+		if err == nil {
+			return dto.SyncOperationResult{Status: SyncDuplicate, ItemID: existing.ID}, syncWrite{}, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return dto.SyncOperationResult{}, syncWrite{}, err
+		}
+	}
+
+	var name, unit, note string
+	var quantity float64
+	if op.Name != nil {
+		name = *op.Name
+	}
+	if op.Quantity != nil {
+		quantity = *op.Quantity
+	}
+	if op.Unit != nil {
+		unit = *op.Unit
+	}
+	if op.Note != nil {
+		note = *op.Note
+	}
+
+	item, err := s.manualItem(list.ID, op.IngredientID, name, quantity, unit, note)
+	if err == ErrItemNameRequired || errors.Is(err, gorm.ErrRecordNotFound) {
+		return rejected(err), syncWrite{}, nil
+	}
+	if err != nil {
+		return dto.SyncOperationResult{}, syncWrite{}, err
+	}
+	item.ClientID = op.ClientItemID
+	if op.Checked != nil {
+		item.Checked = *op.Checked
+	}
+	item.Touch(at, models.FieldChecked, models.FieldName, models.FieldQuantity, models.FieldUnit, models.FieldNote)
+
+	return dto.SyncOperationResult{Status: SyncApplied}, syncWrite{item: item, event: realtime.ItemAdded}, nil
+}
+
+// syncChange works out a toggle, edit or delete of an existing item.
+func (s *shoppingListService) syncChange(list *models.ShoppingList, op dto.SyncOperation, at time.Time) (dto.SyncOperationResult, syncWrite, error) {
+	var item *models.ShoppingListItem
+	var err error
+	switch {
+	case op.ItemID != 0:
+		item, err = s.ShoppingListRepo.FindItemByID(op.ItemID)
+	case op.ClientItemID != "":
+		item, err = s.ShoppingListRepo.FindItemByClientID(list.ID, op.ClientItemID)
+	default:
+		return rejected(errSyncNoItem), syncWrite{}, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// items are only ever removed by deletes, and delete beats edit
+		return dto.SyncOperationResult{Status: SyncDeleted, ItemID: op.ItemID}, syncWrite{}, nil
+	}
+	if err != nil {
+		return dto.SyncOperationResult{}, syncWrite{}, err
+	}
+	if item.ShoppingListID != list.ID {
+		return rejected(errSyncOtherList), syncWrite{}, nil
+	}
+	result := dto.SyncOperationResult{Status: SyncApplied, ItemID: item.ID}
+
+	if op.Type == "delete" {
+		return result, syncWrite{item: item, deleted: true, event: realtime.ItemRemoved}, nil
+	}
+
+	applied := 0
+	write := func(field string, set func()) {
+		if last, ok := item.Clock[field]; ok && !at.After(last) {
+			result.Ignored = append(result.Ignored, field)
+			return
+		}
+		set()
+		item.Touch(at, field)
+		applied++
+	}
+
+	event := realtime.ItemUpdated
+	if op.Type == "toggle" {
+		checked := !item.Checked
+		if op.Checked != nil {
+			checked = *op.Checked
+		}
+		write(models.FieldChecked, func() { item.Checked = checked })
+		event = realtime.ItemChecked
+	} else {
+		if op.Name != nil {
+			name := strings.TrimSpace(*op.Name)
+			if item.IngredientID != nil {
+				return rejected(ErrItemNameFixed), syncWrite{}, nil
+			}
+			if name == "" {
+				return rejected(ErrItemNameRequired), syncWrite{}, nil
+			}
+			write(models.FieldName, func() { item.Name = name })
+		}
+		if op.Quantity != nil {
+			write(models.FieldQuantity, func() { item.Quantity = *op.Quantity })
+		}
+		if op.Unit != nil {
+			write(models.FieldUnit, func() { item.Unit = strings.TrimSpace(*op.Unit) })
+		}
+		if op.Note != nil {
+			write(models.FieldNote, func() { item.Note = strings.TrimSpace(*op.Note) })
+		}
+	}
+
+	if applied == 0 {
+		if len(result.Ignored) > 0 {
+			result.Status = SyncConflict
+		}
+		return result, syncWrite{}, nil
+	}
+	return result, syncWrite{item: item, event: event}, nil
+}
+
+func rejected(err error) dto.SyncOperationResult {
+	return dto.SyncOperationResult{Status: SyncRejected, Error: err.Error()}
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/realtime"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+func strPtr(v string) *string       { return &v }
+func float64Ptr(v float64) *float64 { return &v }
+
+// unreceiptedShoppingLists misses the first sync receipt it looks for, as
+// if another request were applying the same operation at the same moment.
+type unreceiptedShoppingLists struct {
+	repository.ShoppingListRepository
+	missed bool
+}
+
+func (r *unreceiptedShoppingLists) FindSyncOp(listID uint, opID string) (*models.ShoppingListSyncOp, error) {
+	if !r.missed {
+		r.missed = true
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.ShoppingListRepository.FindSyncOp(listID, opID)
+}
+
+func TestShoppingListSync(t *testing.T) {
+	db := setupTestDB()
+	service := NewShoppingListService(
+		repository.NewMealPlanRepository(db),
+		repository.NewRecipeIngredientRepository(db),
+		repository.NewShoppingListRepository(db),
+		repository.NewPriceRepository(db),
+		repository.NewPantryRepository(db),
+		repository.NewStoreLayoutRepository(db),
+		repository.NewIngredientRepository(db),
+		realtime.NewMemory(),
+	)
+
+	// each list gets its own week, since manual items carry over between
+	// lists for the same range
+	week := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	newList := func(t *testing.T) *dto.ShoppingListResponse {
+		t.Helper()
+		week = week.AddDate(0, 0, 7)
+		list, err := service.Generate(1, week.Format("2006-01-02"), week.AddDate(0, 0, 6).Format("2006-01-02"), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return list
+	}
+	sync := func(t *testing.T, listID uint, since uint, ops ...dto.SyncOperation) *dto.SyncShoppingListResponse {
+		t.Helper()
+		resp, err := service.Sync(listID, 1, dto.SyncShoppingListRequest{Since: since, Operations: ops})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Results) != len(ops) {
+			t.Fatalf("expected %d results, got %+v", len(ops), resp.Results)
+		}
+		return resp
+	}
+	item := func(t *testing.T, id uint) models.ShoppingListItem {
+		t.Helper()
+		var item models.ShoppingListItem
+		if err := db.First(&item, id).Error; err != nil {
+			t.Fatal(err)
+		}
+		return item
+	}
+
+	t.Run("Rejects Other Users", func(t *testing.T) {
+		list := newList(t)
+		if _, err := service.Sync(list.ID, 2, dto.SyncShoppingListRequest{}); err != ErrUnauthorized {
+			t.Errorf("expected ErrUnauthorized, got %v", err)
+		}
+	})
+
+	t.Run("Replays Are Applied Once", func(t *testing.T) {
+		list := newList(t)
+		at := time.Now().Add(-time.Minute)
+		add := dto.SyncOperation{OpID: "op-1", Type: "add", At: at, ClientItemID: "tmp-1", Name: strPtr("Candles")}
+		toggle := dto.SyncOperation{OpID: "op-2", Type: "toggle", At: at.Add(time.Second), ClientItemID: "tmp-1"}
+
+		first := sync(t, list.ID, list.Token, add, toggle)
+		if first.Results[0].Status != SyncApplied || first.Results[1].Status != SyncApplied {
+			t.Fatalf("unexpected results %+v", first.Results)
+		}
+		id := first.Results[0].ItemID
+
+		// the client didn't hear back and sends the batch again
+		second := sync(t, list.ID, list.Token, add, toggle)
+		for _, result := range second.Results {
+			if result.Status != SyncDuplicate || result.ItemID != id {
+				t.Errorf("expected a duplicate of item %d, got %+v", id, result)
+			}
+		}
+		if got := item(t, id); !got.Checked || got.Version != 2 {
+			t.Errorf("expected the toggle once, got %+v", got)
+		}
+
+		// the same add under a new op_id is still recognised by its client id
+		again := add
+		again.OpID = "op-3"
+		if result := sync(t, list.ID, 0, again).Results[0]; result.Status != SyncDuplicate || result.ItemID != id {
+			t.Errorf("expected a duplicate add, got %+v", result)
+		}
+		var count int64
+		db.Model(&models.ShoppingListItem{}).Where("shopping_list_id = ?", list.ID).Count(&count)
+		if count != 1 {
+			t.Errorf("expected 1 item, got %d", count)
+		}
+	})
+
+	t.Run("Last Writer Wins Per Field", func(t *testing.T) {
+		list := newList(t)
+		at := time.Now().Add(-time.Hour)
+		add := sync(t, list.ID, list.Token, dto.SyncOperation{OpID: "add", Type: "add", At: at,
+			Name: strPtr("Ice"), Quantity: float64Ptr(1), Unit: strPtr("bag")})
+		id := add.Results[0].ItemID
+
+		// made offline before the add above
+		stale := dto.SyncOperation{OpID: "stale", Type: "edit", At: at.Add(-time.Minute),
+			ItemID: id, Quantity: float64Ptr(3), Note: strPtr("crushed")}
+		if result := sync(t, list.ID, list.Token, stale).Results[0]; result.Status != SyncConflict || len(result.Ignored) != 2 {
+			t.Errorf("expected both fields to lose, got %+v", result)
+		}
+
+		// a later edit wins field by field, and the server's own edit is later still
+		if _, err := service.UpdateItem(id, 1, dto.UpdateShoppingListItemRequest{Note: strPtr("cubes")}); err != nil {
+			t.Fatal(err)
+		}
+		fresh := dto.SyncOperation{OpID: "fresh", Type: "edit", At: at.Add(time.Minute),
+			ItemID: id, Quantity: float64Ptr(2), Note: strPtr("crushed")}
+		result := sync(t, list.ID, list.Token, fresh).Results[0]
+		if result.Status != SyncApplied || len(result.Ignored) != 1 || result.Ignored[0] != models.FieldNote {
+			t.Errorf("expected only the note to lose, got %+v", result)
+		}
+		if got := item(t, id); got.Quantity != 2 || got.Note != "cubes" || got.Unit != "bag" || got.Version != 3 {
+			t.Errorf("unexpected item %+v", got)
+		}
+
+		// a clock running ahead is taken as now and can't pin the field
+		ahead := dto.SyncOperation{OpID: "ahead", Type: "edit", At: time.Now().Add(24 * time.Hour),
+			ItemID: id, Unit: strPtr("kg")}
+		sync(t, list.ID, list.Token, ahead)
+		if _, err := service.UpdateItem(id, 1, dto.UpdateShoppingListItemRequest{Unit: strPtr("bags")}); err != nil {
+			t.Fatal(err)
+		}
+		if got := item(t, id); got.Unit != "bags" {
+			t.Errorf("expected the later server edit to win, got %q", got.Unit)
+		}
+	})
+
+	t.Run("Delete Beats Edit", func(t *testing.T) {
+		list := newList(t)
+		added, err := service.AddItem(list.ID, 1, dto.AddManualItemRequest{Name: "Plates"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		deleted := sync(t, list.ID, list.Token,
+			dto.SyncOperation{OpID: "del", Type: "delete", At: time.Now().Add(-time.Hour), ItemID: added.ID})
+		if deleted.Results[0].Status != SyncApplied {
+			t.Fatalf("expected the delete to apply, got %+v", deleted.Results[0])
+		}
+
+		// an edit made after the delete still loses
+		resp := sync(t, list.ID, deleted.Token,
+			dto.SyncOperation{OpID: "late", Type: "edit", At: time.Now(), ItemID: added.ID, Note: strPtr("paper")},
+			dto.SyncOperation{OpID: "late-toggle", Type: "toggle", At: time.Now(), ItemID: added.ID})
+		for _, result := range resp.Results {
+			if result.Status != SyncDeleted {
+				t.Errorf("expected deleted, got %+v", result)
+			}
+		}
+		if err := db.First(&models.ShoppingListItem{}, added.ID).Error; err == nil {
+			t.Error("expected the item to stay deleted")
+		}
+	})
+
+	t.Run("Offline Adds Are Addressed By Client Id", func(t *testing.T) {
+		list := newList(t)
+		at := time.Now().Add(-time.Minute)
+		resp := sync(t, list.ID, list.Token,
+			dto.SyncOperation{OpID: "a", Type: "add", At: at, ClientItemID: "tmp-9", Name: strPtr("Limes"), Quantity: float64Ptr(4)},
+			dto.SyncOperation{OpID: "b", Type: "edit", At: at.Add(time.Second), ClientItemID: "tmp-9", Quantity: float64Ptr(6)},
+			dto.SyncOperation{OpID: "c", Type: "edit", At: at, ClientItemID: "tmp-9", Name: strPtr(" ")})
+		if resp.Results[0].ItemID == 0 || resp.Results[1].ItemID != resp.Results[0].ItemID {
+			t.Fatalf("unexpected results %+v", resp.Results)
+		}
+		if resp.Results[2].Status != SyncRejected || resp.Results[2].Error == "" {
+			t.Errorf("expected an empty name to be rejected, got %+v", resp.Results[2])
+		}
+		if len(resp.Changed) != 1 {
+			t.Fatalf("expected 1 changed item, got %+v", resp.Changed)
+		}
+		got := resp.Changed[0]
+		if got.ClientID != "tmp-9" || got.Name != "Limes" || got.Quantity != 6 || !got.Manual || got.Version != 2 {
+			t.Errorf("unexpected item %+v", got)
+		}
+	})
+
+	t.Run("Writes The Change And Its Receipt Together", func(t *testing.T) {
+		list := newList(t)
+		failReceipts := true
+		db.Callback().Create().Before("gorm:create").Register("test:fail_receipts", func(tx *gorm.DB) {
+			if failReceipts && tx.Statement.Table == "shopping_list_sync_ops" {
+				tx.AddError(errors.New("disk full"))
+			}
+		})
+		defer db.Callback().Create().Remove("test:fail_receipts")
+
+		add := dto.SyncOperation{OpID: "lost", Type: "add", At: time.Now(), Name: strPtr("Charcoal")}
+		if _, err := service.Sync(list.ID, 1, dto.SyncShoppingListRequest{Operations: []dto.SyncOperation{add}}); err == nil {
+			t.Fatal("expected the failed receipt to fail the sync")
+		}
+		count := func() (n int64) {
+			db.Model(&models.ShoppingListItem{}).Where("shopping_list_id = ?", list.ID).Count(&n)
+			return n
+		}
+		if n := count(); n != 0 {
+			t.Fatalf("expected the add to be rolled back, got %d items", n)
+		}
+
+		// the client resends the add, without a client id to spot it by
+		failReceipts = false
+		if result := sync(t, list.ID, list.Token, add).Results[0]; result.Status != SyncApplied {
+			t.Errorf("expected the resent add to apply, got %+v", result)
+		}
+		if result := sync(t, list.ID, list.Token, add).Results[0]; result.Status != SyncDuplicate {
+			t.Errorf("expected a duplicate, got %+v", result)
+		}
+		if n := count(); n != 1 {
+			t.Errorf("expected 1 item, got %d", n)
+		}
+	})
+
+	t.Run("Concurrent Replays Apply Once", func(t *testing.T) {
+		list := newList(t)
+		add := dto.SyncOperation{OpID: "twice", Type: "add", At: time.Now(), Name: strPtr("Matches")}
+		first := sync(t, list.ID, list.Token, add).Results[0]
+
+		// a second request that looked for the receipt before the first wrote it
+		racing := NewShoppingListService(nil, nil, &unreceiptedShoppingLists{ShoppingListRepository: repository.NewShoppingListRepository(db)},
+			repository.NewPriceRepository(db), nil, nil, repository.NewIngredientRepository(db), realtime.NewMemory())
+		resp, err := racing.Sync(list.ID, 1, dto.SyncShoppingListRequest{Operations: []dto.SyncOperation{add}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := resp.Results[0]; result.Status != SyncDuplicate || result.ItemID != first.ItemID {
+			t.Errorf("expected a duplicate of item %d, got %+v", first.ItemID, result)
+		}
+		if len(resp.Changed) != 1 {
+			t.Errorf("expected 1 item, got %+v", resp.Changed)
+		}
+	})
+
+	t.Run("Numbers Changes Per List", func(t *testing.T) {
+		first, second := newList(t), newList(t)
+		if first.Token != 0 || second.Token != 0 {
+			t.Fatalf("expected new lists to start at 0, got %d and %d", first.Token, second.Token)
+		}
+		a, _ := service.AddItem(first.ID, 1, dto.AddManualItemRequest{Name: "Salt"})
+		service.AddItem(second.ID, 1, dto.AddManualItemRequest{Name: "Pepper"})
+		service.ToggleItemChecked(a.ID, 1)
+
+		if got := sync(t, first.ID, 0).Token; got != 2 {
+			t.Errorf("expected token 2 after two changes, got %d", got)
+		}
+		if got := sync(t, second.ID, 0).Token; got != 1 {
+			t.Errorf("expected token 1 after one change, got %d", got)
+		}
+		if resp := sync(t, first.ID, 1); resp.Full || len(resp.Changed) != 1 || !resp.Changed[0].Checked {
+			t.Errorf("expected the toggle since token 1, got %+v", resp)
+		}
+	})
+
+	t.Run("Returns Changes Since The Token", func(t *testing.T) {
+		list := newList(t)
+		keep, _ := service.AddItem(list.ID, 1, dto.AddManualItemRequest{Name: "Straws"})
+		drop, _ := service.AddItem(list.ID, 1, dto.AddManualItemRequest{Name: "Cups"})
+		edit, _ := service.AddItem(list.ID, 1, dto.AddManualItemRequest{Name: "Ice"})
+
+		token := sync(t, list.ID, 0).Token
+		if token != 3 {
+			t.Fatalf("expected token 3, got %d", token)
+		}
+		if err := service.DeleteItem(drop.ID, 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := service.ToggleItemChecked(edit.ID, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		resp := sync(t, list.ID, token)
+		if resp.Full || resp.Token <= token {
+			t.Errorf("expected an incremental answer with a newer token, got %+v", resp)
+		}
+		if len(resp.Changed) != 1 || resp.Changed[0].ID != edit.ID || !resp.Changed[0].Checked || resp.Changed[0].Version != 2 {
+			t.Errorf("unexpected changes %+v", resp.Changed)
+		}
+		if len(resp.Deleted) != 1 || resp.Deleted[0] != drop.ID {
+			t.Errorf("expected item %d to be deleted, got %v", drop.ID, resp.Deleted)
+		}
+
+		// nothing new since the latest token
+		if resp := sync(t, list.ID, resp.Token); len(resp.Changed) != 0 || len(resp.Deleted) != 0 {
+			t.Errorf("expected no changes, got %+v", resp)
+		}
+
+		// a token this list never issued gets the whole list
+		full := sync(t, list.ID, resp.Token+1000)
+		if !full.Full || len(full.Changed) != 2 || len(full.Deleted) != 0 {
+			t.Errorf("expected a full resync, got %+v", full)
+		}
+		for _, got := range full.Changed {
+			if got.ID != keep.ID && got.ID != edit.ID {
+				t.Errorf("unexpected item %+v", got)
+			}
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/aisle"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+)
+
+type StoreService interface {
+	CreateStore(userID uint, req dto.StoreLayoutRequest) (*dto.StoreLayoutResponse, error)
+	GetStores(userID uint) ([]dto.StoreLayoutResponse, error)
+	UpdateStore(storeID uint, userID uint, req dto.StoreLayoutRequest) (*dto.StoreLayoutResponse, error)
+	DeleteStore(storeID uint, userID uint) error
+}
+
+type storeService struct {
+	StoreRepo repository.StoreLayoutRepository
+}
+
+func NewStoreService(storeRepo repository.StoreLayoutRepository) StoreService {
+	return &storeService{StoreRepo: storeRepo}
+}
+
+func (s *storeService) CreateStore(userID uint, req dto.StoreLayoutRequest) (*dto.StoreLayoutResponse, error) {
+	aisles, err := normalizeLayout(req.Aisles)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &models.StoreLayout{
+		UserID: userID,
+		Name:   strings.TrimSpace(req.Name),
+		Aisles: aisles,
+	}
+	if err := s.StoreRepo.Create(store); err != nil {
+		return nil, err
+	}
+
+	resp := storeLayoutResponse(*store)
+	return &resp, nil
+}
+
+func (s *storeService) GetStores(userID uint) ([]dto.StoreLayoutResponse, error) {
+	stores, err := s.StoreRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.StoreLayoutResponse{}
+	for _, store := range stores {
+		response = append(response, storeLayoutResponse(store))
+	}
+	return response, nil
+}
+
+func (s *storeService) UpdateStore(storeID uint, userID uint, req dto.StoreLayoutRequest) (*dto.StoreLayoutResponse, error) {
+	aisles, err := normalizeLayout(req.Aisles)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := s.StoreRepo.FindByID(storeID)
+	if err != nil {
+		return nil, err
+	}
+	if store.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	store.Name = strings.TrimSpace(req.Name)
+	store.Aisles = aisles
+	if err := s.StoreRepo.Update(store); err != nil {
+		return nil, err
+	}
+
+	resp := storeLayoutResponse(*store)
+	return &resp, nil
+}
+
+func (s *storeService) DeleteStore(storeID uint, userID uint) error {
+	store, err := s.StoreRepo.FindByID(storeID)
+	if err != nil {
+		return err
+	}
+	if store.UserID != userID {
+		return ErrUnauthorized
+	}
+	return s.StoreRepo.Delete(store)
+}
+
+// normalizeLayout maps aisle names and aliases to aisles, keeping the first
+// place an aisle is listed.
+func normalizeLayout(names []string) ([]string, error) {
+	seen := make(map[string]bool)
+	aisles := []string{}
+	for _, name := range names {
+		a, err := aisle.Normalize(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[a] {
+			seen[a] = true
+			aisles = append(aisles, a)
+		}
+	}
+	return aisles, nil
+}
+
+func storeLayoutResponse(store models.StoreLayout) dto.StoreLayoutResponse {
+	return dto.StoreLayoutResponse{
+		ID:     store.ID,
+		Name:   store.Name,
+		Aisles: store.Aisles,
+	}
+}
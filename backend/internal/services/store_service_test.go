@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/aisle"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type MockStoreRepo struct {
+	CreateFn   func(*models.StoreLayout) error
+	FindByIDFn func(uint) (*models.StoreLayout, error)
+	UpdateFn   func(*models.StoreLayout) error
+}
+
+func (m *MockStoreRepo) Create(store *models.StoreLayout) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(store)
+	}
+	return nil
+}
+func (m *MockStoreRepo) FindByID(id uint) (*models.StoreLayout, error) {
+	if m.FindByIDFn != nil {
+		return m.FindByIDFn(id)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *MockStoreRepo) FindByUser(uint) ([]models.StoreLayout, error) {
+	return []models.StoreLayout{}, nil
+}
+func (m *MockStoreRepo) Update(store *models.StoreLayout) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(store)
+	}
+	return nil
+}
+func (m *MockStoreRepo) Delete(*models.StoreLayout) error { return nil }
+
+func TestStoreLayouts(t *testing.T) {
+	var saved *models.StoreLayout
+	service := NewStoreService(&MockStoreRepo{
+		CreateFn: func(s *models.StoreLayout) error { saved = s; return nil },
+		FindByIDFn: func(uint) (*models.StoreLayout, error) {
+			return &models.StoreLayout{ID: 2, UserID: 7, Name: "Corner shop"}, nil
+		},
+	})
+
+	store, err := service.CreateStore(1, dto.StoreLayoutRequest{Name: " Big Market ", Aisles: []string{"Dairy & Eggs", "vegetables", "fruit", "Bakery"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{aisle.Dairy, aisle.Produce, aisle.Bakery}
+	if store.Name != "Big Market" || len(store.Aisles) != len(want) || saved.UserID != 1 {
+		t.Fatalf("unexpected store %+v", store)
+	}
+	for i := range want {
+		if store.Aisles[i] != want[i] {
+			t.Errorf("aisle %d = %q, want %q", i, store.Aisles[i], want[i])
+		}
+	}
+
+	if _, err := service.CreateStore(1, dto.StoreLayoutRequest{Name: "X", Aisles: []string{"garden centre"}}); !errors.Is(err, aisle.ErrUnknownAisle) {
+		t.Errorf("expected ErrUnknownAisle, got %v", err)
+	}
+	if _, err := service.UpdateStore(2, 1, dto.StoreLayoutRequest{Name: "X", Aisles: []string{"dairy"}}); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if err := service.DeleteStore(2, 1); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
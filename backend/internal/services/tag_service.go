@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+var ErrTagExists = errors.New("tag already exists")
+var ErrInvalidTag = errors.New("tag name is required")
+
+type TagService interface {
+	CreateTag(userID uint, req dto.CreateTagRequest) (*dto.TagResponse, error)
+	GetTags(userID uint) ([]dto.TagResponse, error)
+	UpdateTag(tagID uint, userID uint, req dto.UpdateTagRequest) error
+	DeleteTag(tagID uint, userID uint) error
+	MigrateCategories(userID uint) (*dto.MigrateCategoriesResponse, error)
+}
+
+type tagService struct {
+	TagRepo    repository.TagRepository
+	RecipeRepo repository.RecipeRepository
+}
+
+func NewTagService(tagRepo repository.TagRepository, recipeRepo repository.RecipeRepository) TagService {
+	return &tagService{TagRepo: tagRepo, RecipeRepo: recipeRepo}
+}
+
+func (s *tagService) CreateTag(userID uint, req dto.CreateTagRequest) (*dto.TagResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrInvalidTag
+	}
+
+	if _, err := s.TagRepo.FindByName(userID, name); err == nil {
+		return nil, ErrTagExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	tag := &models.Tag{UserID: userID, Name: name}
+	if err := s.TagRepo.Create(tag); err != nil {
+		return nil, err
+	}
+
+	return &dto.TagResponse{ID: tag.ID, Name: tag.Name}, nil
+}
+
+func (s *tagService) GetTags(userID uint) ([]dto.TagResponse, error) {
+	tags, err := s.TagRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.TagResponse{}
+	for _, t := range tags {
+		response = append(response, dto.TagResponse{ID: t.ID, Name: t.Name})
+	}
+
+	return response, nil
+}
+
+func (s *tagService) UpdateTag(tagID uint, userID uint, req dto.UpdateTagRequest) error {
+	tag, err := s.TagRepo.FindByID(tagID)
+	if err != nil {
+		return err
+	}
+	if tag.UserID != userID {
+		return ErrUnauthorized
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return ErrInvalidTag
+	}
+
+	if existing, err := s.TagRepo.FindByName(userID, name); err == nil && existing.ID != tag.ID {
+		return ErrTagExists
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	tag.Name = name
+	return s.TagRepo.Update(tag)
+}
+
+func (s *tagService) DeleteTag(tagID uint, userID uint) error {
+	tag, err := s.TagRepo.FindByID(tagID)
+	if err != nil {
+		return err
+	}
+	if tag.UserID != userID {
+		return ErrUnauthorized
+	}
+
+	return s.TagRepo.Delete(tag)
+}
+
+// MigrateCategories turns every recipe's legacy Category into a tag on that recipe.
+// It is safe to run repeatedly: existing tags and links are reused.
+func (s *tagService) MigrateCategories(userID uint) (*dto.MigrateCategoriesResponse, error) {
+	recipes, err := s.RecipeRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.MigrateCategoriesResponse{}
+	tags := make(map[string]*models.Tag)
+
+	for _, r := range recipes {
+		name := strings.TrimSpace(r.Category)
+		if name == "" {
+			continue
+		}
+
+		key := strings.ToLower(name)
+		tag, ok := tags[key]
+		if !ok {
+			tag, err = s.TagRepo.FindByName(userID, name)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				tag = &models.Tag{UserID: userID, Name: name}
+				if err := s.TagRepo.Create(tag); err != nil {
+					return nil, err
+				}
+				result.TagsCreated++
+			} else if err != nil {
+				return nil, err
+			}
+			tags[key] = tag
+		}
+
+		if err := s.TagRepo.AttachToRecipe(tag, r.ID); err != nil {
+			return nil, err
+		}
+		result.RecipesTagged++
+	}
+
+	return result, nil
+}
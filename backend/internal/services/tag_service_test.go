@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/dto"
+	"github.com/NavaneethaPrasad/RecipeManager/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type MockTagRepository struct {
+	CreateFn         func(*models.Tag) error
+	FindByUserIDFn   func(uint) ([]models.Tag, error)
+	FindByIDFn       func(uint) (*models.Tag, error)
+	FindByNameFn     func(uint, string) (*models.Tag, error)
+	UpdateFn         func(*models.Tag) error
+	DeleteFn         func(*models.Tag) error
+	AttachToRecipeFn func(*models.Tag, uint) error
+}
+
+func (m *MockTagRepository) Create(t *models.Tag) error {
+	if m.CreateFn != nil {
+		return m.CreateFn(t)
+	}
+	return nil
+}
+func (m *MockTagRepository) FindByUserID(u uint) ([]models.Tag, error) {
+	if m.FindByUserIDFn != nil {
+		return m.FindByUserIDFn(u)
+	}
+	return []models.Tag{}, nil
+}
+func (m *MockTagRepository) FindByID(id uint) (*models.Tag, error) {
+	if m.FindByIDFn != nil {
+		return m.FindByIDFn(id)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *MockTagRepository) FindByName(u uint, name string) (*models.Tag, error) {
+	if m.FindByNameFn != nil {
+		return m.FindByNameFn(u, name)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *MockTagRepository) Update(t *models.Tag) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(t)
+	}
+	return nil
+}
+func (m *MockTagRepository) Delete(t *models.Tag) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(t)
+	}
+	return nil
+}
+func (m *MockTagRepository) AttachToRecipe(t *models.Tag, recipeID uint) error {
+	if m.AttachToRecipeFn != nil {
+		return m.AttachToRecipeFn(t, recipeID)
+	}
+	return nil
+}
+
+func TestCreateTag(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		service := NewTagService(&MockTagRepository{
+			CreateFn: func(tag *models.Tag) error { tag.ID = 7; return nil },
+		}, &MockRecipeRepository{})
+
+		resp, err := service.CreateTag(1, dto.CreateTagRequest{Name: "  Quick "})
+		if err != nil || resp.ID != 7 || resp.Name != "Quick" {
+			t.Fatalf("unexpected result %+v, %v", resp, err)
+		}
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		service := NewTagService(&MockTagRepository{
+			FindByNameFn: func(uint, string) (*models.Tag, error) { return &models.Tag{ID: 1}, nil },
+		}, &MockRecipeRepository{})
+
+		if _, err := service.CreateTag(1, dto.CreateTagRequest{Name: "quick"}); err != ErrTagExists {
+			t.Fatalf("expected ErrTagExists, got %v", err)
+		}
+	})
+
+	t.Run("Blank Name", func(t *testing.T) {
+		service := NewTagService(&MockTagRepository{}, &MockRecipeRepository{})
+		if _, err := service.CreateTag(1, dto.CreateTagRequest{Name: "   "}); err != ErrInvalidTag {
+			t.Fatalf("expected ErrInvalidTag, got %v", err)
+		}
+	})
+}
+
+func TestUpdateAndDeleteTag_Unauthorized(t *testing.T) {
+	service := NewTagService(&MockTagRepository{
+		FindByIDFn: func(uint) (*models.Tag, error) { return &models.Tag{ID: 1, UserID: 2}, nil },
+	}, &MockRecipeRepository{})
+
+	if err := service.UpdateTag(1, 1, dto.UpdateTagRequest{Name: "x"}); err != ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized on update, got %v", err)
+	}
+	if err := service.DeleteTag(1, 1); err != ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized on delete, got %v", err)
+	}
+}
+
+func TestUpdateTag_NameTaken(t *testing.T) {
+	service := NewTagService(&MockTagRepository{
+		FindByIDFn:   func(uint) (*models.Tag, error) { return &models.Tag{ID: 1, UserID: 1}, nil },
+		FindByNameFn: func(uint, string) (*models.Tag, error) { return &models.Tag{ID: 2, UserID: 1}, nil },
+	}, &MockRecipeRepository{})
+
+	if err := service.UpdateTag(1, 1, dto.UpdateTagRequest{Name: "Vegan"}); err != ErrTagExists {
+		t.Fatalf("expected ErrTagExists, got %v", err)
+	}
+}
+
+func TestMigrateCategories(t *testing.T) {
+	created := 0
+	attached := map[uint]string{}
+
+	service := NewTagService(&MockTagRepository{
+		CreateFn: func(tag *models.Tag) error {
+			created++
+			tag.ID = uint(created)
+			return nil
+		},
+		AttachToRecipeFn: func(tag *models.Tag, recipeID uint) error {
+			attached[recipeID] = tag.Name
+			return nil
+		},
+	}, &MockRecipeRepository{
+		FindByUserIDFn: func(uint) ([]models.Recipe, error) {
+			return []models.Recipe{
+				{ID: 1, Category: "Dinner"},
+				{ID: 2, Category: "dinner "},
+				{ID: 3, Category: "Dessert"},
+				{ID: 4, Category: ""},
+			}, nil
+		},
+	})
+
+	resp, err := service.MigrateCategories(1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.TagsCreated != 2 || resp.RecipesTagged != 3 {
+		t.Fatalf("unexpected counts %+v", resp)
+	}
+	if attached[2] != "Dinner" || attached[3] != "Dessert" {
+		t.Fatalf("unexpected attachments %v", attached)
+	}
+}
+
+func TestMigrateCategories_RepoError(t *testing.T) {
+	service := NewTagService(&MockTagRepository{}, &MockRecipeRepository{
+		FindByUserIDFn: func(uint) ([]models.Recipe, error) { return nil, errors.New("db error") },
+	})
+	if _, err := service.MigrateCategories(1); err == nil || err.Error() != "db error" {
+		t.Fatalf("expected db error, got %v", err)
+	}
+}
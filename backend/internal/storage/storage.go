@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+var ErrUnknownDriver = errors.New("unknown storage driver")
+
+// Storage keeps uploaded files under slash separated keys such as
+// "recipes/12/3f9a.jpg" and hands out URLs a browser can load them from.
+type Storage interface {
+	Put(key string, data []byte, contentType string) error
+	// Delete removes a file; deleting a missing key is not an error.
+	Delete(key string) error
+	URL(key string) string
+}
+
+type Config struct {
+	Driver string // local (default) or s3
+
+	LocalDir  string
+	PublicURL string // URL prefix files are served from; defaults to /uploads, or endpoint/bucket for s3
+
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+func New(cfg Config) (Storage, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", "local":
+		if cfg.PublicURL == "" {
+			cfg.PublicURL = "/uploads"
+		}
+		return NewLocal(cfg.LocalDir, cfg.PublicURL), nil
+	case "s3":
+		return NewS3(cfg)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownDriver, cfg.Driver)
+	}
+}
+
+// cleanKey rejects keys that would escape the storage root.
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if key == "" || cleaned != key || strings.Contains(key, `\`) {
+		return "", fmt.Errorf("%w %q", ErrInvalidKey, key)
+	}
+	return cleaned, nil
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimSuffix(base, "/") + "/" + key
+}
@@ -0,0 +1,91 @@
+package units
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// kitchenFractions are the fractions a measuring set has, as eighths and
+// thirds, with the glyphs used to print them.
+var kitchenFractions = []struct {
+	value float64
+	glyph string
+}{
+	{1.0 / 8, "⅛"}, {1.0 / 4, "¼"}, {1.0 / 3, "⅓"}, {3.0 / 8, "⅜"}, {1.0 / 2, "½"},
+	{5.0 / 8, "⅝"}, {2.0 / 3, "⅔"}, {3.0 / 4, "¾"}, {7.0 / 8, "⅞"},
+}
+
+// fractionTolerance is how close a quantity must be to a kitchen fraction to
+// be printed as one. Anything else is printed as a decimal.
+const fractionTolerance = 0.02
+
+// FormatQuantity writes a quantity and unit for people rather than
+// programs: "1 ½ cups", "250 g", "3". Metric units get at most two decimals;
+// cups, spoons, pieces and units the catalog doesn't know get fractions.
+func FormatQuantity(quantity float64, unit string) string {
+	unit = strings.TrimSpace(unit)
+	amount := formatAmount(quantity, unit)
+	if quantity > 1 && amount != "1" {
+		unit = pluralUnit(unit)
+	}
+	return strings.TrimSpace(amount + " " + unit)
+}
+
+func formatAmount(quantity float64, unit string) string {
+	if quantity <= 0 {
+		return ""
+	}
+	if u, ok := Lookup(unit); ok && u.System == Metric {
+		return strconv.FormatFloat(math.Round(quantity*100)/100, 'f', -1, 64)
+	}
+	return Fraction(quantity)
+}
+
+// Fraction writes a quantity as a whole number and the nearest kitchen
+// fraction, falling back to two decimals when no fraction is close.
+func Fraction(quantity float64) string {
+	whole, rest := math.Modf(quantity)
+	if rest < fractionTolerance {
+		return strconv.FormatFloat(whole, 'f', -1, 64)
+	}
+	if rest > 1-fractionTolerance {
+		return strconv.FormatFloat(whole+1, 'f', -1, 64)
+	}
+	for _, f := range kitchenFractions {
+		if math.Abs(rest-f.value) < fractionTolerance {
+			if whole == 0 {
+				return f.glyph
+			}
+			return strconv.FormatFloat(whole, 'f', -1, 64) + " " + f.glyph
+		}
+	}
+	return strconv.FormatFloat(math.Round(quantity*100)/100, 'f', -1, 64)
+}
+
+// pluralUnit gives the plural of canonical units that are words ("cup",
+// "pint"). A unit with a longer spelled-out alias is an abbreviation
+// ("tbsp", "kg") and, like units the catalog doesn't know, is unchanged.
+func pluralUnit(unit string) string {
+	u, ok := Lookup(unit)
+	if !ok || u.Dimension == Count || u.Name != strings.ToLower(unit) {
+		return unit
+	}
+	for _, entry := range catalog {
+		if entry.unit.Name != u.Name {
+			continue
+		}
+		plural := ""
+		for _, alias := range entry.aliases {
+			if alias == u.Name+"s" {
+				plural = alias
+			} else if len(alias) > len(u.Name) {
+				return unit
+			}
+		}
+		if plural != "" {
+			return plural
+		}
+	}
+	return unit
+}
@@ -0,0 +1,206 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+var ErrUnknownUnit = errors.New("unknown unit")
+var ErrIncompatibleUnits = errors.New("units measure different dimensions")
+var ErrUnknownSystem = errors.New("unknown unit system")
+
+type Dimension string
+
+const (
+	Mass   Dimension = "mass"
+	Volume Dimension = "volume"
+	Count  Dimension = "count"
+)
+
+type System string
+
+const (
+	Metric   System = "metric"
+	US       System = "us"
+	Imperial System = "imperial"
+)
+
+// Unit is a canonical unit. Factor converts one of it into the dimension's
+// base unit: grams for mass, millilitres for volume, pieces for count.
+type Unit struct {
+	Name      string
+	Dimension Dimension
+	System    System
+	Factor    float64
+}
+
+var catalog = []struct {
+	unit    Unit
+	aliases []string
+}{
+	{Unit{"mg", Mass, Metric, 0.001}, []string{"milligram", "milligrams", "milligramme", "milligrammes"}},
+	{Unit{"g", Mass, Metric, 1}, []string{"gr", "gm", "gms", "gram", "grams", "gramme", "grammes"}},
+	{Unit{"kg", Mass, Metric, 1000}, []string{"kgs", "kilo", "kilos", "kilogram", "kilograms", "kilogramme", "kilogrammes"}},
+	{Unit{"oz", Mass, US, 28.349523125}, []string{"ounce", "ounces"}},
+	{Unit{"lb", Mass, US, 453.59237}, []string{"lbs", "pound", "pounds", "#"}},
+
+	{Unit{"ml", Volume, Metric, 1}, []string{"mls", "milliliter", "milliliters", "millilitre", "millilitres", "cc"}},
+	{Unit{"cl", Volume, Metric, 10}, []string{"centiliter", "centiliters", "centilitre", "centilitres"}},
+	{Unit{"dl", Volume, Metric, 100}, []string{"deciliter", "deciliters", "decilitre", "decilitres"}},
+	{Unit{"l", Volume, Metric, 1000}, []string{"liter", "liters", "litre", "litres", "ltr"}},
+	{Unit{"tsp", Volume, US, 4.92892159375}, []string{"t", "tsps", "teaspoon", "teaspoons"}},
+	{Unit{"tbsp", Volume, US, 14.78676478125}, []string{"T", "tbs", "tbl", "tbsps", "tablespoon", "tablespoons"}},
+	{Unit{"fl oz", Volume, US, 29.5735295625}, []string{"floz", "fl. oz", "fl. oz.", "fluid ounce", "fluid ounces"}},
+	{Unit{"cup", Volume, US, 236.5882365}, []string{"c", "cups"}},
+	{Unit{"pint", Volume, US, 473.176473}, []string{"pt", "pints"}},
+	{Unit{"quart", Volume, US, 946.352946}, []string{"qt", "quarts"}},
+	{Unit{"gallon", Volume, US, 3785.411784}, []string{"gal", "gallons"}},
+	{Unit{"imp fl oz", Volume, Imperial, 28.4130625}, []string{"imperial fluid ounce", "imperial fluid ounces"}},
+	{Unit{"imp pint", Volume, Imperial, 568.26125}, []string{"imperial pint", "imperial pints"}},
+	{Unit{"imp quart", Volume, Imperial, 1136.5225}, []string{"imperial quart", "imperial quarts"}},
+	{Unit{"imp gallon", Volume, Imperial, 4546.09}, []string{"imperial gallon", "imperial gallons"}},
+
+	{Unit{"pc", Count, "", 1}, []string{"pcs", "piece", "pieces", "ea", "each", "whole", "unit", "units", "nos"}},
+	{Unit{"dozen", Count, "", 12}, []string{"doz", "dozens"}},
+}
+
+var (
+	byName      = map[string]Unit{}
+	caseAliases = map[string]Unit{}
+	aliases     = map[string]Unit{}
+)
+
+func init() {
+	for _, entry := range catalog {
+		byName[entry.unit.Name] = entry.unit
+		aliases[entry.unit.Name] = entry.unit
+		for _, a := range entry.aliases {
+			// single-letter aliases are case-sensitive: "T" is a tablespoon, "t" a teaspoon
+			if len(a) == 1 {
+				caseAliases[a] = entry.unit
+				continue
+			}
+			aliases[a] = entry.unit
+		}
+	}
+}
+
+// Lookup resolves a unit name or alias to its canonical unit.
+func Lookup(name string) (Unit, bool) {
+	name = strings.TrimSpace(name)
+	if u, ok := caseAliases[name]; ok {
+		return u, true
+	}
+
+	key := strings.ToLower(strings.Join(strings.Fields(name), " "))
+	if u, ok := aliases[key]; ok {
+		return u, true
+	}
+	if u, ok := aliases[strings.TrimSuffix(key, ".")]; ok {
+		return u, true
+	}
+	return Unit{}, false
+}
+
+// Normalize returns the canonical name for a known unit, or the trimmed
+// lower-case input for units the catalog doesn't know ("clove", "pinch").
+func Normalize(name string) string {
+	if u, ok := Lookup(name); ok {
+		return u.Name
+	}
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ToBase converts a quantity into the base unit of its dimension.
+func ToBase(quantity float64, unit string) (float64, Unit, error) {
+	u, ok := Lookup(unit)
+	if !ok {
+		return 0, Unit{}, ErrUnknownUnit
+	}
+	return quantity * u.Factor, u, nil
+}
+
+// Convert converts a quantity between two units of the same dimension.
+func Convert(quantity float64, from, to string) (float64, error) {
+	f, ok := Lookup(from)
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	t, ok := Lookup(to)
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	if f.Dimension != t.Dimension {
+		return 0, ErrIncompatibleUnits
+	}
+	return quantity * f.Factor / t.Factor, nil
+}
+
+// ParseSystem validates a unit system name.
+func ParseSystem(name string) (System, error) {
+	switch System(strings.ToLower(strings.TrimSpace(name))) {
+	case Metric:
+		return Metric, nil
+	case US:
+		return US, nil
+	case Imperial:
+		return Imperial, nil
+	}
+	return "", ErrUnknownSystem
+}
+
+// ladders list, per system and dimension, display units from smallest to
+// largest with the base quantity from which each one is preferred.
+var ladders = map[System]map[Dimension][]struct {
+	name string
+	from float64
+}{
+	Metric: {
+		Mass:   {{"g", 0}, {"kg", 1000}},
+		Volume: {{"ml", 0}, {"l", 1000}},
+	},
+	US: {
+		Mass:   {{"oz", 0}, {"lb", 453.59237}},
+		Volume: {{"tsp", 0}, {"tbsp", 14.78676478125}, {"cup", 59.1470591}, {"gallon", 3785.411784}},
+	},
+	Imperial: {
+		Mass:   {{"oz", 0}, {"lb", 453.59237}},
+		Volume: {{"tsp", 0}, {"tbsp", 14.78676478125}, {"imp fl oz", 28.4130625}, {"imp pint", 568.26125}, {"imp gallon", 4546.09}},
+	},
+}
+
+// Present expresses a base quantity in the most readable unit of a system.
+// Count quantities are returned in pieces.
+func Present(baseQuantity float64, dim Dimension, system System) (float64, string) {
+	if dim == Count {
+		return baseQuantity, "pc"
+	}
+
+	steps := ladders[system][dim]
+	if len(steps) == 0 {
+		steps = ladders[Metric][dim]
+	}
+
+	chosen := steps[0].name
+	for _, step := range steps {
+		if math.Abs(baseQuantity) >= step.from {
+			chosen = step.name
+		}
+	}
+
+	return baseQuantity / byName[chosen].Factor, chosen
+}
+
+// ConvertToSystem re-expresses a quantity in the given system. Units that are
+// unknown, counted, or already in the target system are returned unchanged.
+func ConvertToSystem(quantity float64, unit string, system System) (float64, string) {
+	u, ok := Lookup(unit)
+	if !ok || u.Dimension == Count {
+		return quantity, unit
+	}
+	if u.System == system || (system == Imperial && u.System == US && u.Dimension == Mass) {
+		return quantity, u.Name
+	}
+	return Present(quantity*u.Factor, u.Dimension, system)
+}
@@ -0,0 +1,169 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestLookup(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+		dim  Dimension
+	}{
+		{"g", "g", Mass},
+		{"Grams", "g", Mass},
+		{" kg ", "kg", Mass},
+		{"lbs", "lb", Mass},
+		{"tbsp", "tbsp", Volume},
+		{"Tablespoon", "tbsp", Volume},
+		{"T", "tbsp", Volume},
+		{"t", "tsp", Volume},
+		{"tsp.", "tsp", Volume},
+		{"fluid  ounces", "fl oz", Volume},
+		{"Cups", "cup", Volume},
+		{"litre", "l", Volume},
+		{"pcs", "pc", Count},
+		{"dozen", "dozen", Count},
+	}
+	for _, c := range cases {
+		u, ok := Lookup(c.in)
+		if !ok || u.Name != c.want || u.Dimension != c.dim {
+			t.Errorf("Lookup(%q) = %+v, %v; want %s (%s)", c.in, u, ok, c.want, c.dim)
+		}
+	}
+
+	if _, ok := Lookup("pinch"); ok {
+		t.Error("expected pinch to be unknown")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("Kilograms"); got != "kg" {
+		t.Errorf("expected kg, got %q", got)
+	}
+	if got := Normalize(" Clove "); got != "clove" {
+		t.Errorf("expected unknown units lower-cased, got %q", got)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		qty      float64
+		from, to string
+		want     float64
+	}{
+		{1, "kg", "g", 1000},
+		{500, "g", "kg", 0.5},
+		{1, "lb", "oz", 16},
+		{3, "tsp", "tbsp", 1},
+		{16, "tbsp", "cup", 1},
+		{1, "l", "ml", 1000},
+		{2, "dozen", "pc", 24},
+		{1, "imp pint", "ml", 568.26125},
+	}
+	for _, c := range cases {
+		got, err := Convert(c.qty, c.from, c.to)
+		if err != nil || !approx(got, c.want) {
+			t.Errorf("Convert(%v %s -> %s) = %v, %v; want %v", c.qty, c.from, c.to, got, err, c.want)
+		}
+	}
+
+	if _, err := Convert(1, "cup", "g"); err != ErrIncompatibleUnits {
+		t.Errorf("expected ErrIncompatibleUnits, got %v", err)
+	}
+	if _, err := Convert(1, "handful", "g"); err != ErrUnknownUnit {
+		t.Errorf("expected ErrUnknownUnit, got %v", err)
+	}
+}
+
+func TestPresent(t *testing.T) {
+	cases := []struct {
+		base     float64
+		dim      Dimension
+		system   System
+		wantQty  float64
+		wantUnit string
+	}{
+		{250, Mass, Metric, 250, "g"},
+		{1500, Mass, Metric, 1.5, "kg"},
+		{750, Volume, Metric, 750, "ml"},
+		{2000, Volume, Metric, 2, "l"},
+		{453.59237 * 2, Mass, US, 2, "lb"},
+		{28.349523125 * 4, Mass, US, 4, "oz"},
+		{236.5882365 * 2, Volume, US, 2, "cup"},
+		{14.78676478125 * 2, Volume, US, 2, "tbsp"},
+		{4.92892159375, Volume, US, 1, "tsp"},
+		{6, Count, Metric, 6, "pc"},
+	}
+	for _, c := range cases {
+		qty, unit := Present(c.base, c.dim, c.system)
+		if !approx(qty, c.wantQty) || unit != c.wantUnit {
+			t.Errorf("Present(%v, %s, %s) = %v %s; want %v %s", c.base, c.dim, c.system, qty, unit, c.wantQty, c.wantUnit)
+		}
+	}
+}
+
+func TestConvertToSystem(t *testing.T) {
+	qty, unit := ConvertToSystem(2, "cup", Metric)
+	if !approx(qty, 473.176473) || unit != "ml" {
+		t.Errorf("expected 473.18 ml, got %v %s", qty, unit)
+	}
+
+	qty, unit = ConvertToSystem(1, "kg", US)
+	if !approx(qty, 1000/453.59237) || unit != "lb" {
+		t.Errorf("expected lb, got %v %s", qty, unit)
+	}
+
+	qty, unit = ConvertToSystem(3, "Cloves", US)
+	if qty != 3 || unit != "Cloves" {
+		t.Errorf("expected unknown unit untouched, got %v %s", qty, unit)
+	}
+
+	qty, unit = ConvertToSystem(2, "tablespoons", US)
+	if qty != 2 || unit != "tbsp" {
+		t.Errorf("expected same-system unit canonicalised only, got %v %s", qty, unit)
+	}
+}
+
+func TestParseSystem(t *testing.T) {
+	if s, err := ParseSystem("US"); err != nil || s != US {
+		t.Errorf("expected us, got %v %v", s, err)
+	}
+	if _, err := ParseSystem("martian"); err != ErrUnknownSystem {
+		t.Errorf("expected ErrUnknownSystem, got %v", err)
+	}
+}
+
+func TestFormatQuantity(t *testing.T) {
+	cases := []struct {
+		quantity float64
+		unit     string
+		want     string
+	}{
+		{1.4999999, "cup", "1 ½ cups"},
+		{0.333333, "cup", "⅓ cup"},
+		{1, "cup", "1 cup"},
+		{1.004, "cups", "1 cups"},
+		{2.75, "tbsp", "2 ¾ tbsp"},
+		{0.125, "tsp", "⅛ tsp"},
+		{2, "pint", "2 pints"},
+		{3, "", "3"},
+		{2, "clove", "2 clove"},
+		{1.9999, "pc", "2 pc"},
+		{1.23, "", "1.23"},
+		{0.41, "pinch", "0.41 pinch"},
+		{250.0000001, "g", "250 g"},
+		{1.456, "kg", "1.46 kg"},
+		{0, "g", "g"},
+	}
+	for _, c := range cases {
+		if got := FormatQuantity(c.quantity, c.unit); got != c.want {
+			t.Errorf("FormatQuantity(%v, %q) = %q, want %q", c.quantity, c.unit, got, c.want)
+		}
+	}
+}